@@ -0,0 +1,100 @@
+package astits
+
+// bitrateWindowSize is the number of PCRs bitrates are estimated over
+const bitrateWindowSize = 10
+
+// bitrateEstimator estimates per-PID bitrates over a sliding window of PCRs
+type bitrateEstimator struct {
+	// We use map[uint32] instead map[uint16] as go runtime provide optimized hash functions for (u)int32/64 keys
+	bytes   map[uint32]int64 // Cumulative number of bytes, indexed by PID
+	pcrPID  int              // -1 until a PCR has been found
+	samples []bitrateSample
+}
+
+// bitrateSample represents the cumulative number of bytes per PID when a PCR has been found
+type bitrateSample struct {
+	bytes map[uint32]int64
+	pcr   *ClockReference
+}
+
+// newBitrateEstimator creates a new bitrate estimator
+func newBitrateEstimator() *bitrateEstimator {
+	return &bitrateEstimator{
+		bytes:  make(map[uint32]int64),
+		pcrPID: -1,
+	}
+}
+
+// add accounts for a packet of the provided size
+func (e *bitrateEstimator) add(p *Packet, size int) {
+	// Update bytes
+	e.bytes[uint32(p.Header.PID)] += int64(size)
+
+	// No PCR
+	if !p.Header.HasAdaptationField || p.AdaptationField == nil || !p.AdaptationField.HasPCR {
+		return
+	}
+
+	// Only PCRs of the first PID carrying them are used since PCRs of different programs may not share the same clock
+	if e.pcrPID == -1 {
+		e.pcrPID = int(p.Header.PID)
+	} else if e.pcrPID != int(p.Header.PID) {
+		return
+	}
+
+	// Reset window on discontinuities
+	if l := len(e.samples); p.AdaptationField.DiscontinuityIndicator || (l > 0 && p.AdaptationField.PCR.Sub(e.samples[l-1].pcr) <= 0) {
+		e.reset()
+	}
+
+	// Add sample
+	s := bitrateSample{
+		bytes: make(map[uint32]int64, len(e.bytes)),
+		pcr:   p.AdaptationField.PCR,
+	}
+	for pid, n := range e.bytes {
+		s.bytes[pid] = n
+	}
+	e.samples = append(e.samples, s)
+
+	// Slide window
+	if len(e.samples) > bitrateWindowSize {
+		e.samples = e.samples[1:]
+	}
+}
+
+// reset resets the window, which is needed when PCRs are not continuous anymore
+func (e *bitrateEstimator) reset() {
+	e.samples = nil
+}
+
+// bitrate returns the bitrate, in bits per second, of the provided PID between the first and the last PCRs of the
+// window. PIDNull returns the total bitrate of all PIDs but the null packets one. 0 is returned when less than 2
+// PCRs are in the window.
+func (e *bitrateEstimator) bitrate(pid uint16) float64 {
+	// Not enough PCRs
+	l := len(e.samples)
+	if l < 2 {
+		return 0
+	}
+
+	// Get duration
+	first, last := e.samples[0], e.samples[l-1]
+	ticks := last.pcr.Sub(first.pcr)
+	if ticks <= 0 {
+		return 0
+	}
+
+	// Get bytes
+	var n int64
+	if pid == PIDNull {
+		for p, v := range last.bytes {
+			if p != uint32(PIDNull) {
+				n += v - first.bytes[p]
+			}
+		}
+	} else {
+		n = last.bytes[uint32(pid)] - first.bytes[uint32(pid)]
+	}
+	return float64(n*8) * 90000 / float64(ticks)
+}
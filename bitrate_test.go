@@ -0,0 +1,35 @@
+package astits
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitrateEstimator(t *testing.T) {
+	e := newBitrateEstimator()
+	pcr := func(pid uint16, base int64, discontinuity bool) *Packet {
+		return &Packet{
+			AdaptationField: &PacketAdaptationField{DiscontinuityIndicator: discontinuity, HasPCR: true, PCR: &ClockReference{Base: base}},
+			Header:          PacketHeader{HasAdaptationField: true, PID: pid},
+		}
+	}
+
+	// Window slides
+	for idx := 0; idx < 2*bitrateWindowSize; idx++ {
+		e.add(pcr(0x100, int64(idx)*9000, false), 188)
+	}
+	assert.Len(t, e.samples, bitrateWindowSize)
+	assert.Equal(t, float64(188*8*10), e.bitrate(0x100))
+
+	// PCRs of other PIDs are ignored
+	e.add(pcr(0x101, 0, false), 188)
+	assert.Len(t, e.samples, bitrateWindowSize)
+
+	// Discontinuities reset the window
+	e.add(pcr(0x100, 0, true), 188)
+	assert.Len(t, e.samples, 1)
+	assert.Equal(t, float64(0), e.bitrate(0x100))
+	e.add(pcr(0x100, 0, false), 188)
+	assert.Len(t, e.samples, 1)
+}
@@ -0,0 +1,131 @@
+package astits
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// NAL unit types carrying SEI messages
+const (
+	nalUnitTypeH264SEI       = 6
+	nalUnitTypeH265PrefixSEI = 39
+	nalUnitTypeH265SuffixSEI = 40
+)
+
+// SEI payload type of registered user data
+const seiPayloadTypeUserDataRegisteredITUTT35 = 4
+
+// cea708Prefix is the ITU-T T.35 prefix of ATSC A/53 caption user data: USA country code, ATSC provider code,
+// "GA94" user identifier and cc_data user data type code
+var cea708Prefix = []byte{0xb5, 0x00, 0x31, 'G', 'A', '9', '4', 0x03}
+
+// ExtractCEA708 extracts closed captions cc_data carried in SEI registered user data of H.264 and H.265 video
+// elementary stream data
+// cc_data of all caption SEI messages are concatenated and consist of 3-byte cc_valid, cc_type and cc_data_1/2
+// constructs as per CEA-708
+// Link: https://www.atsc.org/wp-content/uploads/2015/03/a_53-Part-4-2009.pdf
+func ExtractCEA708(pesData []byte, streamType StreamType) (ccData []byte, err error) {
+	// Get NAL unit header length
+	var headerLength int
+	switch streamType {
+	case StreamTypeH264Video:
+		headerLength = 1
+	case StreamTypeH265Video:
+		headerLength = 2
+	default:
+		err = fmt.Errorf("astits: stream type %s is not supported", streamType)
+		return
+	}
+
+	// Loop through NAL units
+	for _, nal := range splitNALUnits(pesData) {
+		// Check NAL unit type
+		if len(nal) <= headerLength {
+			continue
+		}
+		switch streamType {
+		case StreamTypeH264Video:
+			if nal[0]&0x1f != nalUnitTypeH264SEI {
+				continue
+			}
+		case StreamTypeH265Video:
+			if t := (nal[0] >> 1) & 0x3f; t != nalUnitTypeH265PrefixSEI && t != nalUnitTypeH265SuffixSEI {
+				continue
+			}
+		}
+
+		// Parse SEI messages
+		var b []byte
+		if b, err = extractSEICEA708(removeEmulationPreventionBytes(nal[headerLength:])); err != nil {
+			err = fmt.Errorf("astits: extracting CEA-708 from SEI failed: %w", err)
+			return
+		}
+		ccData = append(ccData, b...)
+	}
+	return
+}
+
+// extractSEICEA708 extracts cc_data of caption SEI messages of a SEI RBSP
+func extractSEICEA708(rbsp []byte) (ccData []byte, err error) {
+	for len(rbsp) > 0 {
+		// RBSP trailing bits
+		if rbsp[0] == 0x80 {
+			return
+		}
+
+		// Payload type and size are coded as a sequence of 0xff bytes followed by a last byte
+		var ok bool
+		var payloadType, payloadSize int
+		if payloadType, rbsp, ok = parseSEIValue(rbsp); !ok {
+			err = fmt.Errorf("astits: SEI payload type is truncated")
+			return
+		}
+		if payloadSize, rbsp, ok = parseSEIValue(rbsp); !ok {
+			err = fmt.Errorf("astits: SEI payload size is truncated")
+			return
+		}
+		if payloadSize > len(rbsp) {
+			err = fmt.Errorf("astits: SEI payload size %d is bigger than %d remaining bytes", payloadSize, len(rbsp))
+			return
+		}
+		payload := rbsp[:payloadSize]
+		rbsp = rbsp[payloadSize:]
+
+		// Only caption registered user data is processed
+		if payloadType != seiPayloadTypeUserDataRegisteredITUTT35 || !bytes.HasPrefix(payload, cea708Prefix) {
+			continue
+		}
+		payload = payload[len(cea708Prefix):]
+
+		// process_em_data_flag, process_cc_data_flag, additional_data_flag, cc_count and em_data
+		if len(payload) < 2 {
+			err = fmt.Errorf("astits: caption user data is truncated")
+			return
+		}
+
+		// cc_data is not meant to be processed
+		if payload[0]&0x40 == 0 {
+			continue
+		}
+
+		// cc_data
+		ccCount := int(payload[0] & 0x1f)
+		if len(payload) < 2+3*ccCount {
+			err = fmt.Errorf("astits: caption user data is truncated")
+			return
+		}
+		ccData = append(ccData, payload[2:2+3*ccCount]...)
+	}
+	return
+}
+
+// parseSEIValue parses a SEI payload type or size
+func parseSEIValue(b []byte) (v int, rest []byte, ok bool) {
+	for idx, c := range b {
+		v += int(c)
+		if c != 0xff {
+			return v, b[idx+1:], true
+		}
+	}
+	return
+}
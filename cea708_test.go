@@ -0,0 +1,73 @@
+package astits
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ceaSEINALBytes(header []byte, ccData []byte) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteByte(5)              // Unregistered user data payload type
+	buf.WriteByte(2)              // Unregistered user data payload size
+	buf.Write([]byte{0xaa, 0xbb}) // Unregistered user data
+	buf.WriteByte(4)              // Registered user data payload type
+	payload := append([]byte{}, cea708Prefix...)
+	payload = append(payload, 0x40|uint8(len(ccData)/3)) // Process cc data flag and cc count
+	payload = append(payload, 0xff)                      // Em data
+	payload = append(payload, ccData...)                 // cc data
+	payload = append(payload, 0xff)                      // Marker bits
+	buf.WriteByte(uint8(len(payload)))                   // Registered user data payload size
+	buf.Write(payload)                                   // Registered user data
+	buf.WriteByte(0x80)                                  // RBSP trailing bits
+
+	// Add emulation prevention bytes
+	b := append([]byte{0, 0, 0, 1}, header...)
+	var zeros int
+	for _, c := range buf.Bytes() {
+		if zeros >= 2 && c <= 3 {
+			b = append(b, 3)
+			zeros = 0
+		}
+		if c == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+		b = append(b, c)
+	}
+	return b
+}
+
+func TestExtractCEA708(t *testing.T) {
+	// cc_data requiring an emulation prevention byte
+	ccData := []byte{0xfc, 0x00, 0x00, 0x01, 0x94, 0x20}
+
+	// H.264
+	b := []byte{0, 0, 0, 1, 0x09, 0xf0}                    // Access unit delimiter
+	b = append(b, ceaSEINALBytes([]byte{0x06}, ccData)...) // SEI
+	b = append(b, 0, 0, 1, 0x65, 0x88, 0x84)               // IDR slice
+	cc, err := ExtractCEA708(b, StreamTypeH264Video)
+	assert.NoError(t, err)
+	assert.Equal(t, ccData, cc)
+
+	// H.265
+	cc, err = ExtractCEA708(ceaSEINALBytes([]byte{nalUnitTypeH265PrefixSEI << 1, 1}, []byte{0xfc, 0x94, 0x20}), StreamTypeH265Video)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xfc, 0x94, 0x20}, cc)
+
+	// No captions
+	cc, err = ExtractCEA708([]byte{0, 0, 1, 0x65, 0x88}, StreamTypeH264Video)
+	assert.NoError(t, err)
+	assert.Empty(t, cc)
+
+	// Truncated SEI
+	b = ceaSEINALBytes([]byte{0x06}, []byte{0xfc, 0x94, 0x20})
+	_, err = ExtractCEA708(b[:len(b)-6], StreamTypeH264Video)
+	assert.Error(t, err)
+
+	// Unsupported stream type
+	_, err = ExtractCEA708(nil, StreamTypeMPEG2Video)
+	assert.Error(t, err)
+}
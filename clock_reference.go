@@ -4,6 +4,9 @@ import (
 	"time"
 )
 
+// clockReferenceBaseModulo is the modulo at which clock reference bases, which are 33 bits long, wrap around
+const clockReferenceBaseModulo = int64(1) << 33
+
 // ClockReference represents a clock reference
 // Base is based on a 90 kHz clock and extension is based on a 27 MHz clock
 type ClockReference struct {
@@ -18,6 +21,30 @@ func newClockReference(base, extension int64) *ClockReference {
 	}
 }
 
+// Add adds the provided number of 90 kHz ticks to the clock reference base, wrapping around at 2^33
+// The extension is left untouched
+func (p ClockReference) Add(ticks int64) *ClockReference {
+	b := (p.Base + ticks) % clockReferenceBaseModulo
+	if b < 0 {
+		b += clockReferenceBaseModulo
+	}
+	return newClockReference(b, p.Extension)
+}
+
+// Sub returns the number of 90 kHz ticks between the provided clock reference base and the clock reference base
+// Since bases wrap around at 2^33, the shortest signed distance is returned
+// Extensions are ignored
+func (p ClockReference) Sub(o *ClockReference) int64 {
+	d := (p.Base - o.Base) % clockReferenceBaseModulo
+	if d < 0 {
+		d += clockReferenceBaseModulo
+	}
+	if d >= clockReferenceBaseModulo/2 {
+		d -= clockReferenceBaseModulo
+	}
+	return d
+}
+
 // Duration converts the clock reference into duration
 func (p ClockReference) Duration() time.Duration {
 	return time.Duration(p.Base*1e9/90000) + time.Duration(p.Extension*1e9/27000000)
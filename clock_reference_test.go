@@ -13,3 +13,17 @@ func TestClockReference(t *testing.T) {
 	assert.Equal(t, 36344825768814*time.Nanosecond, clockReference.Duration())
 	assert.Equal(t, int64(36344), clockReference.Time().Unix())
 }
+
+func TestClockReferenceArithmetic(t *testing.T) {
+	// Add
+	assert.Equal(t, newClockReference(3271034419, 58), clockReference.Add(100))
+	assert.Equal(t, newClockReference(3271034219, 58), clockReference.Add(-100))
+	assert.Equal(t, newClockReference(99, 0), newClockReference(1<<33-1, 0).Add(100))
+	assert.Equal(t, newClockReference(1<<33-100, 0), newClockReference(0, 0).Add(-100))
+
+	// Sub
+	assert.Equal(t, int64(100), clockReference.Add(100).Sub(clockReference))
+	assert.Equal(t, int64(-100), clockReference.Sub(clockReference.Add(100)))
+	assert.Equal(t, int64(100), newClockReference(99, 0).Sub(newClockReference(1<<33-1, 0)))
+	assert.Equal(t, int64(-100), newClockReference(1<<33-1, 0).Sub(newClockReference(99, 0)))
+}
@@ -169,6 +169,11 @@ func main() {
 					}
 					mux.SetPCRPID(es.ElementaryPID)
 					muxers[es.ElementaryPID] = mux
+					defer func() {
+						if err = mux.Close(); err != nil {
+							log.Print(err)
+						}
+					}()
 
 					if !pmtsPrinted {
 						log.Printf("\t\tES PID %d type %s",
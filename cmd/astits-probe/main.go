@@ -1,6 +1,7 @@
 package main
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
@@ -9,14 +10,19 @@ import (
 	"io"
 	"log"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/asticode/go-astikit"
 	"github.com/asticode/go-astits"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pkg/profile"
 )
 
@@ -25,7 +31,7 @@ var (
 	ctx, cancel     = context.WithCancel(context.Background())
 	cpuProfiling    = flag.Bool("cp", false, "if yes, cpu profiling is enabled")
 	dataTypes       = astikit.NewFlagStrings()
-	format          = flag.String("f", "", "the format")
+	format          = flag.String("f", "", "the format (json for programs and jitter, ndjson for data)")
 	inputPath       = flag.String("i", "", "the input path")
 	memoryProfiling = flag.Bool("mp", false, "if yes, memory profiling is enabled")
 )
@@ -33,10 +39,10 @@ var (
 func main() {
 	// Init
 	flag.Usage = func() {
-		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s <data|packets|default>:\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s <data|jitter|packets|default>:\n", os.Args[0])
 		flag.PrintDefaults()
 	}
-	flag.Var(dataTypes, "d", "the datatypes whitelist (all, pat, pmt, pes, eit, nit, sdt, tot)")
+	flag.Var(dataTypes, "d", "the datatypes whitelist (all, pat, pmt, pes, eit, nit, sdt, sit, tdt, tot)")
 	cmd := astikit.FlagCmd()
 	flag.Parse()
 
@@ -51,30 +57,54 @@ func main() {
 	}
 
 	// Build the reader
-	var r io.Reader
+	var r io.ReadCloser
 	var err error
 	if r, err = buildReader(ctx); err != nil {
 		log.Fatal(fmt.Errorf("astits: parsing input failed: %w", err))
 	}
 
 	// Make sure the reader is closed properly
-	if c, ok := r.(io.Closer); ok {
-		defer c.Close()
-	}
-
-	// Create the demuxer
-	var dmx = astits.NewDemuxer(ctx, r, astits.DemuxerOptLogger(log.Default()))
+	defer r.Close()
 
 	// Switch on command
 	switch cmd {
 	case "data":
+		// Create the demuxer
+		dmx := newDemuxer(ctx, r)
+		defer dmx.Close()
+
 		// Fetch data
 		if err = data(dmx); err != nil {
 			if !errors.Is(err, astits.ErrNoMorePackets) {
 				log.Fatal(fmt.Errorf("astits: fetching data failed: %w", err))
 			}
 		}
+	case "jitter":
+		// Fetch jitter
+		var js []*Jitter
+		if js, err = jitter(ctx, r); err != nil {
+			log.Fatal(fmt.Errorf("astits: fetching jitter failed: %w", err))
+		}
+
+		// Print
+		switch *format {
+		case "json":
+			var e = json.NewEncoder(os.Stdout)
+			e.SetIndent("", "  ")
+			if err = e.Encode(js); err != nil {
+				log.Fatal(fmt.Errorf("astits: json encoding to stdout failed: %w", err))
+			}
+		default:
+			fmt.Println("Jitter is:")
+			for _, j := range js {
+				log.Printf("* %s\n", j)
+			}
+		}
 	case "packets":
+		// Create the demuxer
+		dmx := newDemuxer(ctx, r)
+		defer dmx.Close()
+
 		// Fetch packets
 		if err = packets(dmx); err != nil {
 			if !errors.Is(err, astits.ErrNoMorePackets) {
@@ -82,6 +112,10 @@ func main() {
 			}
 		}
 	default:
+		// Create the demuxer
+		dmx := newDemuxer(ctx, r)
+		defer dmx.Close()
+
 		// Fetch the programs
 		var pgms []*Program
 		if pgms, err = programs(dmx); err != nil {
@@ -107,6 +141,10 @@ func main() {
 	}
 }
 
+func newDemuxer(ctx context.Context, r io.Reader, opts ...func(*astits.Demuxer)) *astits.Demuxer {
+	return astits.NewDemuxer(ctx, r, append([]func(*astits.Demuxer){astits.DemuxerOptLogger(log.Default())}, opts...)...)
+}
+
 func handleSignals() {
 	ch := make(chan os.Signal, 1)
 	signal.Notify(ch)
@@ -124,7 +162,23 @@ func handleSignals() {
 	}()
 }
 
-func buildReader(ctx context.Context) (r io.Reader, err error) {
+// readCloser reads from a reader and closes its closers in order when closed
+type readCloser struct {
+	io.Reader
+	cs []io.Closer
+}
+
+// Close implements the io.Closer interface
+func (r *readCloser) Close() (err error) {
+	for _, c := range r.cs {
+		if errClose := c.Close(); errClose != nil && err == nil {
+			err = errClose
+		}
+	}
+	return
+}
+
+func buildReader(ctx context.Context) (r io.ReadCloser, err error) {
 	// Validate input
 	if len(*inputPath) <= 0 {
 		err = errors.New("use -i to indicate an input path")
@@ -140,7 +194,13 @@ func buildReader(ctx context.Context) (r io.Reader, err error) {
 
 	// Switch on scheme
 	switch u.Scheme {
-	case "udp":
+	case "http", "https":
+		// Open HTTP body
+		if r, err = openHTTP(ctx, *inputPath); err != nil {
+			err = fmt.Errorf("astits: opening http body failed: %w", err)
+			return
+		}
+	case "rtp", "udp":
 		// Resolve addr
 		var addr *net.UDPAddr
 		if addr, err = net.ResolveUDPAddr("udp", u.Host); err != nil {
@@ -156,14 +216,77 @@ func buildReader(ctx context.Context) (r io.Reader, err error) {
 		}
 		c.SetReadBuffer(4096)
 		r = c
+
+		// Strip RTP headers
+		if u.Scheme == "rtp" {
+			r = &readCloser{Reader: astits.NewRTPReader(c), cs: []io.Closer{c}}
+		}
 	default:
 		// Open file
-		var f *os.File
-		if f, err = os.Open(*inputPath); err != nil {
-			err = fmt.Errorf("astits: opening %s failed: %w", *inputPath, err)
+		if r, err = openFile(*inputPath); err != nil {
+			err = fmt.Errorf("astits: opening file failed: %w", err)
+			return
+		}
+	}
+	return
+}
+
+// openHTTP sends a GET request, following redirects, and returns the response body which is streamed as it's read
+func openHTTP(ctx context.Context, u string) (rc io.ReadCloser, err error) {
+	// Create request
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, u, nil); err != nil {
+		err = fmt.Errorf("astits: creating request to %s failed: %w", u, err)
+		return
+	}
+
+	// Send request
+	var resp *http.Response
+	if resp, err = http.DefaultClient.Do(req); err != nil {
+		err = fmt.Errorf("astits: sending request to %s failed: %w", u, err)
+		return
+	}
+
+	// Check status code
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		err = fmt.Errorf("astits: invalid status code %d for %s", resp.StatusCode, u)
+		return
+	}
+	rc = resp.Body
+	return
+}
+
+// openFile opens a file and transparently decompresses it based on its extension
+// Closing the returned reader closes both the decompressor and the file
+func openFile(path string) (rc io.ReadCloser, err error) {
+	// Open file
+	var f *os.File
+	if f, err = os.Open(path); err != nil {
+		err = fmt.Errorf("astits: opening %s failed: %w", path, err)
+		return
+	}
+	rc = f
+
+	// Switch on extension
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gz":
+		var r *gzip.Reader
+		if r, err = gzip.NewReader(f); err != nil {
+			f.Close()
+			err = fmt.Errorf("astits: creating gzip reader for %s failed: %w", path, err)
+			return
+		}
+		rc = &readCloser{Reader: r, cs: []io.Closer{r, f}}
+	case ".zst":
+		var d *zstd.Decoder
+		if d, err = zstd.NewReader(f); err != nil {
+			f.Close()
+			err = fmt.Errorf("astits: creating zstd reader for %s failed: %w", path, err)
 			return
 		}
-		r = f
+		r := d.IOReadCloser()
+		rc = &readCloser{Reader: r, cs: []io.Closer{r, f}}
 	}
 	return
 }
@@ -200,7 +323,7 @@ func packets(dmx *astits.Demuxer) (err error) {
 
 func data(dmx *astits.Demuxer) (err error) {
 	// Determine which data to log
-	var logAll, logEIT, logNIT, logPAT, logPES, logPMT, logSDT, logTOT bool
+	var logAll, logEIT, logNIT, logPAT, logPES, logPMT, logSDT, logSIT, logTDT, logTOT bool
 	if _, ok := dataTypes.Map["all"]; ok {
 		logAll = true
 	}
@@ -222,10 +345,22 @@ func data(dmx *astits.Demuxer) (err error) {
 	if _, ok := dataTypes.Map["sdt"]; ok {
 		logSDT = true
 	}
+	if _, ok := dataTypes.Map["sit"]; ok {
+		logSIT = true
+	}
+	if _, ok := dataTypes.Map["tdt"]; ok {
+		logTDT = true
+	}
 	if _, ok := dataTypes.Map["tot"]; ok {
 		logTOT = true
 	}
 
+	// Create NDJSON encoder
+	var e *json.Encoder
+	if *format == "ndjson" {
+		e = json.NewEncoder(os.Stdout)
+	}
+
 	// Loop through data
 	var d *astits.DemuxerData
 	log.Println("Fetching data...")
@@ -239,6 +374,17 @@ func data(dmx *astits.Demuxer) (err error) {
 			return
 		}
 
+		// Output one JSON object per data
+		if e != nil {
+			if o := newData(d); o != nil && (logAll || dataTypes.Map[o.Type]) {
+				if err = e.Encode(o); err != nil {
+					err = fmt.Errorf("astits: json encoding to stdout failed: %w", err)
+					return
+				}
+			}
+			continue
+		}
+
 		// Log data
 		if d.EIT != nil && (logAll || logEIT) {
 			log.Printf("EIT: %d\n", d.PID)
@@ -271,6 +417,11 @@ func data(dmx *astits.Demuxer) (err error) {
 			}
 		} else if d.SDT != nil && (logAll || logSDT) {
 			log.Printf("SDT: %d\n", d.PID)
+		} else if d.SIT != nil && (logAll || logSIT) {
+			log.Printf("SIT: %d\n", d.PID)
+		} else if d.TDT != nil && (logAll || logTDT) {
+			log.Printf("TDT: %d\n", d.PID)
+			log.Printf("  UTC Time: %v\n", d.TDT.UTCTime)
 		} else if d.TOT != nil && (logAll || logTOT) {
 			log.Printf("TOT: %d\n", d.PID)
 		}
@@ -278,6 +429,172 @@ func data(dmx *astits.Demuxer) (err error) {
 	return
 }
 
+// jitterTracker tracks the PCRs of programs and matches each PES with the PCR preceding its first packet
+// Since a PES is only returned once the next one starts, the last PCR of its program at that time precedes the first
+// packet of the next PES
+type jitterTracker struct {
+	dmx *astits.Demuxer
+	// We use map[uint32] instead map[uint16] as go runtime provide optimized hash functions for (u)int32/64 keys
+	pcrPIDs map[uint32]uint16     // PCR PIDs indexed by elementary PID
+	pcrs    map[uint32]*jitterPCR // Indexed by PCR PID
+	ptss    map[uint32]*jitterPTS // Indexed by elementary PID
+}
+
+// jitterPCR tracks the intervals between consecutive PCRs of a PID, in 90kHz ticks
+type jitterPCR struct {
+	count, max, min, sum int64
+	last                 *astits.ClockReference
+}
+
+// jitterPTS tracks the offsets between PTSs of a PID and their PCRs, in 90kHz ticks
+type jitterPTS struct {
+	count, max, sum int64
+	next            *astits.ClockReference // PCR preceding the first packet of the next PES
+}
+
+func newJitterTracker(dmx *astits.Demuxer) *jitterTracker {
+	return &jitterTracker{
+		dmx:     dmx,
+		pcrPIDs: make(map[uint32]uint16),
+		pcrs:    make(map[uint32]*jitterPCR),
+		ptss:    make(map[uint32]*jitterPTS),
+	}
+}
+
+// add implements the astits.PCRCallback signature
+func (s *jitterPCR) add(p astits.PCRSample) {
+	// Only PCRs are tracked
+	if p.IsOPCR {
+		return
+	}
+
+	// Update intervals
+	if s.last != nil {
+		d := p.PCR.Sub(s.last)
+		if s.count == 0 || d > s.max {
+			s.max = d
+		}
+		if s.count == 0 || d < s.min {
+			s.min = d
+		}
+		s.count++
+		s.sum += d
+	}
+	s.last = p.PCR
+}
+
+// add processes a demuxer data
+func (t *jitterTracker) add(d *astits.DemuxerData) {
+	// Update PCR PIDs
+	if d.PMT != nil {
+		for _, es := range d.PMT.ElementaryStreams {
+			t.pcrPIDs[uint32(es.ElementaryPID)] = d.PMT.PCRPID
+		}
+		if _, ok := t.pcrs[uint32(d.PMT.PCRPID)]; !ok {
+			s := &jitterPCR{}
+			t.pcrs[uint32(d.PMT.PCRPID)] = s
+			t.dmx.OnPCR(d.PMT.PCRPID, s.add)
+		}
+	}
+
+	// Only PES of known programs are tracked
+	if d.PES == nil {
+		return
+	}
+	pcrPID, ok := t.pcrPIDs[uint32(d.PID)]
+	if !ok {
+		return
+	}
+
+	// Get PTS state
+	s, ok := t.ptss[uint32(d.PID)]
+	if !ok {
+		s = &jitterPTS{}
+		t.ptss[uint32(d.PID)] = s
+	}
+
+	// Get PCR, preferably from the first packet itself
+	pcr := s.next
+	if d.PID == pcrPID && d.FirstPacket != nil && d.FirstPacket.AdaptationField != nil && d.FirstPacket.AdaptationField.HasPCR {
+		pcr = d.FirstPacket.AdaptationField.PCR
+	}
+	s.next = t.pcrs[uint32(pcrPID)].last
+
+	// Update offset
+	if pcr == nil || d.PES.Header.OptionalHeader == nil || d.PES.Header.OptionalHeader.PTS == nil {
+		return
+	}
+	o := d.PES.Header.OptionalHeader.PTS.Sub(pcr)
+	if s.count == 0 || o > s.max {
+		s.max = o
+	}
+	s.count++
+	s.sum += o
+}
+
+// jitters returns the jitter of PIDs with PTS, sorted by PID
+func (t *jitterTracker) jitters() (js []*Jitter) {
+	for pid, s := range t.ptss {
+		// No PTS
+		if s.count == 0 {
+			continue
+		}
+
+		// Create jitter
+		j := &Jitter{
+			PCRPID:        t.pcrPIDs[pid],
+			PID:           uint16(pid),
+			PTSOffsetMax:  ticksToDuration(s.max),
+			PTSOffsetMean: ticksToDuration(s.sum / s.count),
+		}
+
+		// Add PCR intervals
+		if c, ok := t.pcrs[uint32(j.PCRPID)]; ok && c.count > 0 {
+			mean := c.sum / c.count
+			j.PCRIntervalJitter = ticksToDuration(mean - c.min)
+			if c.max-mean > mean-c.min {
+				j.PCRIntervalJitter = ticksToDuration(c.max - mean)
+			}
+			j.PCRIntervalMax = ticksToDuration(c.max)
+			j.PCRIntervalMean = ticksToDuration(mean)
+		}
+		js = append(js, j)
+	}
+	sort.Slice(js, func(i, j int) bool { return js[i].PID < js[j].PID })
+	return
+}
+
+func ticksToDuration(ticks int64) time.Duration {
+	return time.Duration(ticks * 1e9 / 90000)
+}
+
+func jitter(ctx context.Context, r io.Reader, opts ...func(*astits.Demuxer)) (js []*Jitter, err error) {
+	// Create the demuxer
+	dmx := newDemuxer(ctx, r, opts...)
+	defer dmx.Close()
+	t := newJitterTracker(dmx)
+
+	// Loop through data
+	var d *astits.DemuxerData
+	log.Println("Fetching jitter...")
+	for {
+		// Get next data
+		if d, err = dmx.NextData(); err != nil {
+			if err == astits.ErrNoMorePackets {
+				err = nil
+				break
+			}
+			err = fmt.Errorf("astits: getting next data failed: %w", err)
+			return
+		}
+
+		// Add data
+		t.add(d)
+	}
+	js = t.jitters()
+	return
+}
+
 func programs(dmx *astits.Demuxer) (o []*Program, err error) {
 	// Loop through data
 	var d *astits.DemuxerData
@@ -321,7 +638,7 @@ func programs(dmx *astits.Demuxer) (o []*Program, err error) {
 
 			// Add elementary streams
 			for _, es := range d.PMT.ElementaryStreams {
-				var s = newStream(es.ElementaryPID, es.StreamType)
+				var s = newStream(es.ElementaryPID, es.StreamType, es.Codec())
 				for _, d := range es.ElementaryStreamDescriptors {
 					s.Descriptors = append(s.Descriptors, descriptorToString(d))
 				}
@@ -355,11 +672,85 @@ type Program struct {
 
 // Stream represents a stream
 type Stream struct {
+	Codec       string            `json:"codec,omitempty"`
 	Descriptors []string          `json:"descriptors,omitempty"`
 	ID          uint16            `json:"id,omitempty"`
 	Type        astits.StreamType `json:"type,omitempty"`
 }
 
+// Jitter represents the PTS vs PCR offset of a PID with PTS and the PCR interval jitter of its program
+// PCR interval jitter is the maximum deviation of PCR intervals from their mean
+type Jitter struct {
+	PCRIntervalJitter time.Duration `json:"pcr_interval_jitter"`
+	PCRIntervalMax    time.Duration `json:"pcr_interval_max"`
+	PCRIntervalMean   time.Duration `json:"pcr_interval_mean"`
+	PCRPID            uint16        `json:"pcr_pid"`
+	PID               uint16        `json:"pid"`
+	PTSOffsetMax      time.Duration `json:"pts_offset_max"`
+	PTSOffsetMean     time.Duration `json:"pts_offset_mean"`
+}
+
+type Data struct {
+	DTS               *int64     `json:"dts,omitempty"`
+	PCRPID            *uint16    `json:"pcr_pid,omitempty"`
+	PID               uint16     `json:"pid"`
+	PTS               *int64     `json:"pts,omitempty"`
+	Programs          []*Program `json:"programs,omitempty"`
+	StreamID          *uint8     `json:"stream_id,omitempty"`
+	TransportStreamID *uint16    `json:"transport_stream_id,omitempty"`
+	Type              string     `json:"type"`
+	UTCTime           *time.Time `json:"utc_time,omitempty"`
+}
+
+// newData returns the key fields of a demuxer data, or nil if its type is not handled
+func newData(d *astits.DemuxerData) (o *Data) {
+	o = &Data{PID: d.PID}
+	switch {
+	case d.EIT != nil:
+		o.Type = "eit"
+	case d.NIT != nil:
+		o.Type = "nit"
+	case d.PAT != nil:
+		o.Type = "pat"
+		o.TransportStreamID = &d.PAT.TransportStreamID
+		for _, p := range d.PAT.Programs {
+			o.Programs = append(o.Programs, newProgram(p.ProgramNumber, p.ProgramMapID))
+		}
+	case d.PES != nil:
+		o.Type = "pes"
+		o.StreamID = &d.PES.Header.StreamID
+		if h := d.PES.Header.OptionalHeader; h != nil {
+			if h.PTS != nil {
+				o.PTS = &h.PTS.Base
+			}
+			if h.DTS != nil {
+				o.DTS = &h.DTS.Base
+			}
+		}
+	case d.PMT != nil:
+		o.Type = "pmt"
+		o.PCRPID = &d.PMT.PCRPID
+		p := newProgram(d.PMT.ProgramNumber, d.PID)
+		for _, es := range d.PMT.ElementaryStreams {
+			p.Streams = append(p.Streams, newStream(es.ElementaryPID, es.StreamType, es.Codec()))
+		}
+		o.Programs = []*Program{p}
+	case d.SDT != nil:
+		o.Type = "sdt"
+	case d.SIT != nil:
+		o.Type = "sit"
+	case d.TDT != nil:
+		o.Type = "tdt"
+		o.UTCTime = &d.TDT.UTCTime
+	case d.TOT != nil:
+		o.Type = "tot"
+		o.UTCTime = &d.TOT.UTCTime
+	default:
+		return nil
+	}
+	return
+}
+
 func newProgram(id, mapID uint16) *Program {
 	return &Program{
 		ID:    id,
@@ -367,13 +758,19 @@ func newProgram(id, mapID uint16) *Program {
 	}
 }
 
-func newStream(id uint16, _type astits.StreamType) *Stream {
+func newStream(id uint16, _type astits.StreamType, codec string) *Stream {
 	return &Stream{
-		ID:   id,
-		Type: _type,
+		Codec: codec,
+		ID:    id,
+		Type:  _type,
 	}
 }
 
+// String implements the Stringer interface
+func (j Jitter) String() string {
+	return fmt.Sprintf("[%d] - PTS offset: max %s, mean %s - PCR PID: %d - PCR interval: max %s, mean %s, jitter %s", j.PID, j.PTSOffsetMax, j.PTSOffsetMean, j.PCRPID, j.PCRIntervalMax, j.PCRIntervalMean, j.PCRIntervalJitter)
+}
+
 // String implements the Stringer interface
 func (p Program) String() (o string) {
 	o = fmt.Sprintf("[%d] - Map ID: %d", p.ID, p.MapID)
@@ -405,6 +802,11 @@ func (s Stream) String() (o string) {
 		t = "H265 video"
 	}
 
+	// Codec has been identified through a registration descriptor
+	if s.Codec != "" && s.Codec != s.Type.String() {
+		t = s.Codec
+	}
+
 	// Output
 	o = fmt.Sprintf("[%d] - Type: %s", s.ID, t)
 	for _, d := range s.Descriptors {
@@ -447,7 +849,7 @@ func descriptorToString(d *astits.Descriptor) string {
 	case astits.DescriptorTagAC3:
 		return fmt.Sprintf("[AC3] ac3 asvc: %d | bsid: %d | component type: %d | mainid: %d | info: %s", d.AC3.ASVC, d.AC3.BSID, d.AC3.ComponentType, d.AC3.MainID, d.AC3.AdditionalInfo)
 	case astits.DescriptorTagComponent:
-		return fmt.Sprintf("[Component] language: %s | text: %s | component tag: %d | component type: %d | stream content: %d | stream content ext: %d", d.Component.ISO639LanguageCode, d.Component.Text, d.Component.ComponentTag, d.Component.ComponentType, d.Component.StreamContent, d.Component.StreamContentExt)
+		return fmt.Sprintf("[Component] language: %s | text: %s | component tag: %d | component type: %d | stream content: %d | stream content ext: %d | description: %s", d.Component.ISO639LanguageCode, d.Component.Text, d.Component.ComponentTag, d.Component.ComponentType, d.Component.StreamContent, d.Component.StreamContentExt, d.Component.Describe())
 	case astits.DescriptorTagContent:
 		var os []string
 		for _, i := range d.Content.Items {
@@ -461,7 +863,11 @@ func descriptorToString(d *astits.Descriptor) string {
 		}
 		return s
 	case astits.DescriptorTagISO639LanguageAndAudioType:
-		return fmt.Sprintf("[ISO639 language and audio type] language: %s | audio type: %d", d.ISO639LanguageAndAudioType.Language, d.ISO639LanguageAndAudioType.Type)
+		var os []string
+		for _, i := range d.ISO639LanguageAndAudioType.Items {
+			os = append(os, fmt.Sprintf("language: %s | audio type: %d", i.Language, i.Type))
+		}
+		return "[ISO639 language and audio type] " + strings.Join(os, " - ")
 	case astits.DescriptorTagMaximumBitrate:
 		return fmt.Sprintf("[Maximum bitrate] maximum bitrate: %d", d.MaximumBitrate.Bitrate)
 	case astits.DescriptorTagNetworkName:
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astits"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+func patPMTBytes(t *testing.T) []byte {
+	buf := &bytes.Buffer{}
+	m := astits.NewMuxer(context.Background(), buf)
+	assert.NoError(t, m.AddElementaryStream(astits.PMTElementaryStream{ElementaryPID: 0x100, StreamType: astits.StreamTypeH264Video}))
+	m.SetPCRPID(0x100)
+	_, err := m.WriteTables()
+	assert.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestDataNDJSON(t *testing.T) {
+	// Set flags
+	f := *format
+	defer func() { *format = f }()
+	*format = "ndjson"
+	dataTypes.Map = map[string]bool{"pes": true, "pmt": true}
+	defer func() { dataTypes.Map = map[string]bool{} }()
+
+	// Build stream
+	buf := &bytes.Buffer{}
+	m := astits.NewMuxer(context.Background(), buf)
+	assert.NoError(t, m.AddElementaryStream(astits.PMTElementaryStream{ElementaryPID: 0x100, StreamType: astits.StreamTypeH264Video}))
+	m.SetPCRPID(0x100)
+	_, err := m.WriteElementaryStreamData(0x100, []byte("data"), &astits.ClockReference{Base: 3000}, &astits.ClockReference{Base: 1000}, true)
+	assert.NoError(t, err)
+
+	// Capture stdout
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	os.Stdout = w
+	err = data(astits.NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), astits.DemuxerOptPacketSize(astits.MpegTsPacketSize)))
+	os.Stdout = stdout
+	assert.Equal(t, astits.ErrNoMorePackets, err)
+	assert.NoError(t, w.Close())
+	b, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+
+	// PAT is filtered out
+	assert.Equal(t, `{"pcr_pid":256,"pid":4096,"programs":[{"id":1,"map_id":4096,"streams":[{"codec":"H264 Video","id":256,"type":27}]}],"type":"pmt"}
+{"dts":1000,"pid":256,"pts":3000,"stream_id":224,"type":"pes"}
+`, string(b))
+}
+
+func TestOpenHTTP(t *testing.T) {
+	b := patPMTBytes(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/segment.ts", func(w http.ResponseWriter, r *http.Request) { w.Write(b) })
+	mux.Handle("/redirect.ts", http.RedirectHandler("/segment.ts", http.StatusFound))
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	// Redirects are followed and the body is demuxed
+	rc, err := openHTTP(context.Background(), s.URL+"/redirect.ts")
+	assert.NoError(t, err)
+	defer rc.Close()
+	dmx := astits.NewDemuxer(context.Background(), rc, astits.DemuxerOptPacketSize(astits.MpegTsPacketSize))
+	var pids []uint16
+	for {
+		d, err := dmx.NextData()
+		if err == astits.ErrNoMorePackets {
+			break
+		}
+		assert.NoError(t, err)
+		if d.PMT != nil {
+			pids = append(pids, d.PMT.PCRPID)
+		}
+	}
+	assert.Equal(t, []uint16{0x100}, pids)
+
+	// Non 200 responses fail
+	_, err = openHTTP(context.Background(), s.URL+"/unknown.ts")
+	assert.Error(t, err)
+}
+
+func TestOpenFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "astits-probe")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Raw
+	p := filepath.Join(dir, "raw.ts")
+	assert.NoError(t, ioutil.WriteFile(p, []byte("raw"), 0644))
+	r, err := openFile(p)
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("raw"), b)
+	assert.NoError(t, r.Close())
+
+	// Gzip
+	p = filepath.Join(dir, "compressed.ts.gz")
+	f, err := os.Create(p)
+	assert.NoError(t, err)
+	w := gzip.NewWriter(f)
+	_, err = w.Write([]byte("compressed"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.NoError(t, f.Close())
+	r, err = openFile(p)
+	assert.NoError(t, err)
+	b, err = ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("compressed"), b)
+
+	// Closing closes both the decompressor and the file
+	assert.NoError(t, r.Close())
+	assert.Error(t, r.(*readCloser).cs[1].Close())
+
+	// Zstd
+	p = filepath.Join(dir, "compressed.ts.zst")
+	f, err = os.Create(p)
+	assert.NoError(t, err)
+	zw, err := zstd.NewWriter(f)
+	assert.NoError(t, err)
+	_, err = zw.Write([]byte("compressed"))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, f.Close())
+	r, err = openFile(p)
+	assert.NoError(t, err)
+	b, err = ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("compressed"), b)
+	assert.NoError(t, r.Close())
+	assert.Error(t, r.(*readCloser).cs[1].Close())
+
+	// Invalid gzip
+	p = filepath.Join(dir, "invalid.ts.gz")
+	assert.NoError(t, ioutil.WriteFile(p, []byte("raw"), 0644))
+	_, err = openFile(p)
+	assert.Error(t, err)
+}
+
+func TestJitter(t *testing.T) {
+	// Build stream with PCR intervals alternating between 30ms and 50ms, video PTS 100ms ahead of PCR and audio PTS
+	// alternating between 40ms and 60ms ahead of PCR. Since the PCR preceding a PES is only known once the previous PES of
+	// the PID has been returned, the first PES of each PID is not taken into account.
+	buf := &bytes.Buffer{}
+	m := astits.NewMuxer(context.Background(), buf)
+	assert.NoError(t, m.AddElementaryStream(astits.PMTElementaryStream{ElementaryPID: 0x100, StreamType: astits.StreamTypeH264Video}))
+	assert.NoError(t, m.AddElementaryStream(astits.PMTElementaryStream{ElementaryPID: 0x101, StreamType: astits.StreamTypeAACAudio}))
+	m.SetPCRPID(0x100)
+	_, err := m.WriteTables()
+	assert.NoError(t, err)
+	pcr := &astits.ClockReference{Base: 90000}
+	for idx := 0; idx < 5; idx++ {
+		if idx > 0 {
+			pcr = pcr.Add(2700 + int64(idx%2)*1800)
+		}
+		_, err := m.WriteData(&astits.MuxerData{AdaptationField: &astits.PacketAdaptationField{HasPCR: true, PCR: pcr}, PID: 0x100})
+		assert.NoError(t, err)
+		_, err = m.WriteElementaryStreamData(0x100, []byte("video"), pcr.Add(9000), nil, true)
+		assert.NoError(t, err)
+		_, err = m.WriteElementaryStreamData(0x101, []byte("audio"), pcr.Add(3600+int64(idx%2)*1800), nil, true)
+		assert.NoError(t, err)
+	}
+
+	e := []*Jitter{
+		{
+			PCRIntervalJitter: 10 * time.Millisecond,
+			PCRIntervalMax:    50 * time.Millisecond,
+			PCRIntervalMean:   40 * time.Millisecond,
+			PCRPID:            0x100,
+			PID:               0x100,
+			PTSOffsetMax:      100 * time.Millisecond,
+			PTSOffsetMean:     100 * time.Millisecond,
+		},
+		{
+			PCRIntervalJitter: 10 * time.Millisecond,
+			PCRIntervalMax:    50 * time.Millisecond,
+			PCRIntervalMean:   40 * time.Millisecond,
+			PCRPID:            0x100,
+			PID:               0x101,
+			PTSOffsetMax:      60 * time.Millisecond,
+			PTSOffsetMean:     50 * time.Millisecond,
+		},
+	}
+	js, err := jitter(context.Background(), bytes.NewReader(buf.Bytes()), astits.DemuxerOptPacketSize(astits.MpegTsPacketSize))
+	assert.NoError(t, err)
+	assert.Equal(t, e, js)
+
+	// Duplicate packets dropped by the demuxer don't shift PES and PCRs
+	var b []byte
+	var count int
+	for i := 0; i+astits.MpegTsPacketSize <= buf.Len(); i += astits.MpegTsPacketSize {
+		p := buf.Bytes()[i : i+astits.MpegTsPacketSize]
+		b = append(b, p...)
+		if pid := uint16(p[1]&0x1f)<<8 | uint16(p[2]); pid == 0x101 && p[1]&0x40 > 0 {
+			if count++; count == 2 {
+				b = append(b, p...)
+			}
+		}
+	}
+	js, err = jitter(context.Background(), bytes.NewReader(b), astits.DemuxerOptPacketSize(astits.MpegTsPacketSize))
+	assert.NoError(t, err)
+	assert.Equal(t, e, js)
+}
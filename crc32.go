@@ -4,6 +4,12 @@ const (
 	crc32Polynomial = uint32(0xffffffff)
 )
 
+// ComputeCRC32 computes the MPEG-2 CRC32 of the provided bytes, which is the CRC32 expected at the end of a PSI
+// section computed over the section bytes, from the table ID to the byte preceding the CRC32
+func ComputeCRC32(bs []byte) uint32 {
+	return computeCRC32(bs)
+}
+
 func computeCRC32(bs []byte) uint32 {
 	return updateCRC32(crc32Polynomial, bs)
 }
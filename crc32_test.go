@@ -1,9 +1,12 @@
 package astits
 
 import (
+	"bytes"
 	"encoding/binary"
-	"github.com/stretchr/testify/assert"
 	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
 )
 
 var (
@@ -35,3 +38,17 @@ func Test_updateCRC32(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeCRC32(t *testing.T) {
+	// PAT section as found in psiBytes()
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	w.Write(uint8(0))                      // PAT table ID
+	w.Write("1")                           // PAT syntax section indicator
+	w.Write("1")                           // PAT private bit
+	w.Write("11")                          // PAT reserved
+	w.Write("000000010001")                // PAT section length
+	w.Write(psiSectionSyntaxHeaderBytes()) // PAT syntax section header
+	w.Write(patBytes())                    // PAT data
+	assert.Equal(t, uint32(0x60739f61), ComputeCRC32(buf.Bytes()))
+}
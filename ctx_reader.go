@@ -0,0 +1,102 @@
+package astits
+
+import (
+	"context"
+	"io"
+)
+
+// ctxReader represents a reader whose reads return as soon as its context is cancelled, even if the underlying read
+// is blocking (e.g. an idle UDP socket)
+// Reads are done by a single goroutine into a buffer that is never handed to the caller. The goroutine exits once
+// the underlying reader returns an error, in which case the next read starts a new one, or once the reader is closed
+// or its context is cancelled. A read pending at that time is left behind, and its result is dropped.
+type ctxReader struct {
+	buf    []byte
+	cancel context.CancelFunc
+	ctx    context.Context
+	done   chan struct{}
+	r      io.Reader
+	reqs   chan []byte
+	res    chan ctxReaderResult
+}
+
+type ctxReaderResult struct {
+	b   []byte
+	err error
+}
+
+// newCtxReader creates a new context reader
+func newCtxReader(ctx context.Context, r io.Reader) *ctxReader {
+	ctx, cancel := context.WithCancel(ctx)
+	return &ctxReader{
+		cancel: cancel,
+		ctx:    ctx,
+		r:      r,
+	}
+}
+
+// close stops the goroutine without closing the underlying reader
+func (r *ctxReader) close() {
+	r.cancel()
+}
+
+// Read implements the io.Reader interface
+func (r *ctxReader) Read(p []byte) (n int, err error) {
+	// Check ctx error
+	if err = r.ctx.Err(); err != nil {
+		return
+	}
+
+	// Start goroutine
+	if r.reqs == nil {
+		r.done = make(chan struct{})
+		r.reqs = make(chan []byte)
+		r.res = make(chan ctxReaderResult, 1)
+		go r.read(r.done, r.reqs, r.res)
+	}
+
+	// Buffer is only reused once the previous read is over, which is always the case unless the context has been
+	// cancelled
+	if cap(r.buf) < len(p) {
+		r.buf = make([]byte, len(p))
+	}
+
+	// Send request
+	select {
+	case r.reqs <- r.buf[:len(p)]:
+	case <-r.ctx.Done():
+		err = r.ctx.Err()
+		return
+	}
+
+	// Wait for either the read or the context
+	select {
+	case res := <-r.res:
+		n, err = copy(p, res.b), res.err
+
+		// Goroutine has exited
+		if err != nil {
+			r.reqs = nil
+		}
+	case <-r.ctx.Done():
+		err = r.ctx.Err()
+	}
+	return
+}
+
+// read reads from the underlying reader until it returns an error or the context is cancelled
+func (r *ctxReader) read(done chan struct{}, reqs chan []byte, res chan ctxReaderResult) {
+	defer close(done)
+	for {
+		select {
+		case b := <-reqs:
+			n, err := r.r.Read(b)
+			res <- ctxReaderResult{b: b[:n], err: err}
+			if err != nil {
+				return
+			}
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
@@ -11,20 +11,38 @@ const (
 	PIDPAT  uint16 = 0x0    // Program Association Table (PAT) contains a directory listing of all Program Map Tables.
 	PIDCAT  uint16 = 0x1    // Conditional Access Table (CAT) contains a directory listing of all ITU-T Rec. H.222 entitlement management message streams used by Program Map Tables.
 	PIDTSDT uint16 = 0x2    // Transport Stream Description Table (TSDT) contains descriptors related to the overall transport stream
+	PIDSDT  uint16 = 0x11   // Service Description Table (SDT) describes the services of the transport stream
+	PIDTDT  uint16 = 0x14   // Time and Date Table (TDT) and Time Offset Table (TOT) contain the UTC time and the local time offsets
 	PIDNull uint16 = 0x1fff // Null Packet (used for fixed bandwidth padding)
+
+	PIDATSCBase uint16 = 0x1ffb // ATSC PSIP base PID, which carries the MGT and VCTs. Only parsed when DemuxerOptATSC is enabled.
 )
 
 // DemuxerData represents a data parsed by Demuxer
 type DemuxerData struct {
-	EIT         *EITData
-	FirstPacket *Packet
-	NIT         *NITData
-	PAT         *PATData
-	PES         *PESData
-	PID         uint16
-	PMT         *PMTData
-	SDT         *SDTData
-	TOT         *TOTData
+	CA               bool // Set for ECM and EMM payloads, which are returned as is in Raw since they are private to the CA system
+	CAT              *CATData
+	CRCMismatch      bool // Only set for PSI data whose CRC32 doesn't match, which is only returned when DemuxerOptIgnoreCRCErrors is enabled
+	EIT              *EITData
+	FirstPacket      *Packet
+	MGT              *MGTData
+	NIT              *NITData
+	PAT              *PATData
+	PES              *PESData
+	Packets          []*Packet // Only set when DemuxerOptKeepPackets is enabled
+	PID              uint16
+	PMT              *PMTData
+	Raw              []byte            // Only set for ECM and EMM payloads, and when the payload is scrambled at the transport level, in which case it can't be parsed
+	RawSection       []byte            // Only set for PSI data when DemuxerOptKeepRawSections is enabled
+	RawSectionHeader *PSISectionHeader // Header of RawSection, which provides the table ID of tables that are not parsed
+	SCTE35           *SCTE35Data
+	Scrambled        bool // Set when either the transport or the PES scrambling control indicates the payload is scrambled
+	SDT              *SDTData
+	SIT              *SITData
+	TDT              *TDTData
+	TOT              *TOTData
+	TSDT             *TSDTData
+	VCT              *VCTData
 }
 
 // MuxerData represents a data to be written by Muxer
@@ -34,8 +52,31 @@ type MuxerData struct {
 	PES             *PESData
 }
 
+// dataParseOptions represents the options parseData and the PSI parsing functions it calls are provided with
+type dataParseOptions struct {
+	atsc            bool // Whether ATSC PSIP tables should be parsed
+	ignoreCRCErrors bool // Whether PSI sections whose CRC32 doesn't match should be returned instead of failing
+	keepRawSections bool // Whether raw PSI sections should be attached to the data
+	strictPES       bool // Whether truncated PES data should be rejected instead of clamped
+}
+
+// dataReuser holds the PES data and buffers that are reused or retained by parseData when DemuxerOptReuseData or
+// DemuxerOptZeroCopyPES is enabled
+type dataReuser struct {
+	d        DemuxerData
+	ds       []*DemuxerData
+	payload  *bytesPoolItem // Payload the last PES data aliases when zero copy is enabled
+	pes      PESData
+	pesData  []byte
+	reuse    bool
+	zeroCopy bool
+}
+
 // parseData parses a payload spanning over multiple packets and returns a set of data
-func parseData(ps []*Packet, prs PacketsParser, pm *programMap) (ds []*DemuxerData, err error) {
+// onPSIData is optional and is executed with every PSI data parsed
+// r is optional and, when provided, PES data is parsed into it instead of being allocated if reuse is enabled, or
+// aliases the payload, which is then only released on the next call, if zero copy is enabled
+func parseData(ps []*Packet, prs PacketsParser, pm *programMap, onPSIData func(pid uint16, d *PSIData), o dataParseOptions, r *dataReuser) (ds []*DemuxerData, err error) {
 	// Use custom parser first
 	if prs != nil {
 		var skip bool
@@ -53,9 +94,20 @@ func parseData(ps []*Packet, prs PacketsParser, pm *programMap) (ds []*DemuxerDa
 		l += len(p.Payload)
 	}
 
+	// Release the payload the previous PES data was aliasing
+	if r != nil && r.payload != nil {
+		bytesPool.put(r.payload)
+		r.payload = nil
+	}
+
 	// Get the slice for payload from pool
 	payload := bytesPool.get(l)
-	defer bytesPool.put(payload)
+	defer func() {
+		// Payload is retained when PES data aliases it
+		if r == nil || r.payload != payload {
+			bytesPool.put(payload)
+		}
+	}()
 
 	// Append payload
 	var c int
@@ -76,44 +128,105 @@ func parseData(ps []*Packet, prs PacketsParser, pm *programMap) (ds []*DemuxerDa
 	}
 
 	// Parse payload
-	if pid == PIDCAT {
-		// Information in a CAT payload is private and dependent on the CA system. Use the PacketsParser
-		// to parse this type of payload
-	} else if isPSIPayload(pid, pm) {
+	if pm.isCAPIDUnlocked(pid) {
+		// Information in ECM and EMM payloads is private and dependent on the CA system, therefore we return it as is.
+		// Use the PacketsParser to parse this type of payload. We need to copy it since the payload goes back to the pool
+		raw := make([]byte, len(payload.s))
+		copy(raw, payload.s)
+
+		// Append data
+		ds = []*DemuxerData{
+			{
+				CA:          true,
+				FirstPacket: fp,
+				PID:         pid,
+				Raw:         raw,
+			},
+		}
+	} else if isPSIPayload(pid, pm, o.atsc) {
 		// Parse PSI data
 		var psiData *PSIData
-		if psiData, err = parsePSIData(i); err != nil {
+		if psiData, err = parsePSIData(i, o); err != nil {
 			err = fmt.Errorf("astits: parsing PSI data failed: %w", err)
 			return
 		}
 
+		// Callback
+		if onPSIData != nil {
+			onPSIData(pid, psiData)
+		}
+
 		// Append data
 		ds = psiData.toData(fp, pid)
+	} else if isScrambled(fp.Header.TransportScramblingControl) {
+		// Scrambled payload can't be parsed, therefore we return the ciphertext as is. PSI payloads are never scrambled.
+		// We need to copy it since the payload goes back to the pool
+		raw := make([]byte, len(payload.s))
+		copy(raw, payload.s)
+
+		// Append data
+		ds = []*DemuxerData{
+			{
+				FirstPacket: fp,
+				PID:         pid,
+				Raw:         raw,
+				Scrambled:   true,
+			},
+		}
 	} else if isPESPayload(payload.s) {
 		// Parse PES data
 		var pesData *PESData
-		if pesData, err = parsePESData(i); err != nil {
+		if pesData, err = parsePESData(i, o.strictPES, r); err != nil {
 			err = fmt.Errorf("astits: parsing PES data failed: %w", err)
 			return
 		}
 
+		// PES data aliases the payload
+		if r != nil && r.zeroCopy {
+			r.payload = payload
+		}
+
+		// Create data
+		var d *DemuxerData
+		if r != nil && r.reuse {
+			d = &r.d
+		} else {
+			d = &DemuxerData{}
+		}
+		*d = DemuxerData{
+			FirstPacket: fp,
+			PES:         pesData,
+			PID:         pid,
+			Scrambled:   pesData.Header.OptionalHeader != nil && isScrambled(pesData.Header.OptionalHeader.ScramblingControl),
+		}
+
 		// Append data
-		ds = []*DemuxerData{
-			{
-				FirstPacket: fp,
-				PES:         pesData,
-				PID:         pid,
-			},
+		if r != nil && r.reuse {
+			r.ds = append(r.ds[:0], d)
+			ds = r.ds
+		} else {
+			ds = []*DemuxerData{d}
 		}
 	}
 	return
 }
 
+// isScrambled checks whether a scrambling control indicates the payload is scrambled
+func isScrambled(scramblingControl uint8) bool {
+	return scramblingControl == ScramblingControlScrambledWithEvenKey ||
+		scramblingControl == ScramblingControlScrambledWithOddKey
+}
+
 // isPSIPayload checks whether the payload is a PSI one
-func isPSIPayload(pid uint16, pm *programMap) bool {
-	return pid == PIDPAT || // PAT
+// PIDs forced to carry PES data never carry PSI
+func isPSIPayload(pid uint16, pm *programMap, atsc bool) bool {
+	return !pm.isPESUnlocked(pid) && (pid == PIDPAT || // PAT
+		(atsc && pid == PIDATSCBase) || // ATSC PSIP
+		pid == PIDCAT || // CAT
+		pid == PIDTSDT || // TSDT
 		pm.existsUnlocked(pid) || // PMT
-		((pid >= 0x10 && pid <= 0x14) || (pid >= 0x1e && pid <= 0x1f)) //DVB
+		pm.isSCTE35Unlocked(pid) || // SCTE35
+		((pid >= 0x10 && pid <= 0x14) || (pid >= 0x1e && pid <= 0x1f))) //DVB
 }
 
 // isPESPayload checks whether the payload is a PES one
@@ -165,8 +278,9 @@ func isPSIComplete(ps []*Packet) bool {
 			return false
 		}
 
-		// Check whether we need to stop the parsing
-		if shouldStopPSIParsing(PSITableID(b)) {
+		// Check whether we need to stop the parsing. Sections of unknown tables are accounted for as well, since they
+		// are parsed when raw sections are kept
+		if PSITableID(b) == PSITableIDNull {
 			break
 		}
 
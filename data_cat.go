@@ -0,0 +1,26 @@
+package astits
+
+import (
+	"fmt"
+
+	"github.com/asticode/go-astikit"
+)
+
+// CATData represents a CAT data
+// Chapter: 2.4.4.6 | Link: https://www.itu.int/rec/T-REC-H.222.0
+type CATData struct {
+	Descriptors []*Descriptor
+}
+
+// parseCATSection parses a CAT section
+func parseCATSection(i *astikit.BytesIterator, offsetSectionsEnd int) (d *CATData, err error) {
+	// Create data
+	d = &CATData{}
+
+	// Descriptors
+	if d.Descriptors, err = parseDescriptorsUntil(i, offsetSectionsEnd); err != nil {
+		err = fmt.Errorf("astits: parsing descriptors failed: %w", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,28 @@
+package astits
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCATSection(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	w.Write(uint8(DescriptorTagCA)) // Descriptor #1 tag
+	w.Write(uint8(4))               // Descriptor #1 length
+	w.Write(uint16(0x0500))         // Descriptor #1 CA system ID
+	w.Write("111")                  // Descriptor #1 reserved
+	w.Write("0000100000001")        // Descriptor #1 CA PID
+	b := buf.Bytes()
+
+	d, err := parseCATSection(astikit.NewBytesIterator(b), len(b))
+	assert.NoError(t, err)
+	assert.Equal(t, &CATData{Descriptors: []*Descriptor{{
+		CA:     &DescriptorCA{CAPID: 0x101, CASystemID: 0x0500},
+		Length: 4,
+		Tag:    DescriptorTagCA,
+	}}}, d)
+}
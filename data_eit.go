@@ -122,3 +122,50 @@ func parseEITSection(i *astikit.BytesIterator, offsetSectionsEnd int, tableIDExt
 	}
 	return
 }
+
+func calcEITSectionLength(d *EITData) uint16 {
+	ret := uint16(6) // transport stream ID, original network ID, segment last section number and last table ID
+	for _, e := range d.Events {
+		ret += 12 // event ID, start time, duration, flags and descriptors loop length
+		ret += calcDescriptorsLength(e.Descriptors)
+	}
+	return ret
+}
+
+func writeEITSection(w *astikit.BitsWriter, d *EITData) (int, error) {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.TransportStreamID)
+	b.Write(d.OriginalNetworkID)
+	b.Write(d.SegmentLastSectionNumber)
+	b.Write(d.LastTableID)
+	bytesWritten := 6
+
+	for _, e := range d.Events {
+		b.Write(e.EventID)
+		bytesWritten += 2
+
+		n, err := writeDVBTime(w, e.StartTime)
+		if err != nil {
+			return 0, err
+		}
+		bytesWritten += n
+
+		if n, err = writeDVBDurationSeconds(w, e.Duration); err != nil {
+			return 0, err
+		}
+		bytesWritten += n
+
+		b.WriteN(e.RunningStatus, 3)
+		b.Write(e.HasFreeCSAMode)
+		b.WriteN(calcDescriptorsLength(e.Descriptors), 12)
+		bytesWritten += 2
+
+		if n, err = writeDescriptors(w, e.Descriptors); err != nil {
+			return 0, err
+		}
+		bytesWritten += n
+	}
+
+	return bytesWritten, b.Err()
+}
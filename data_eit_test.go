@@ -46,3 +46,82 @@ func TestParseEITSection(t *testing.T) {
 	assert.Equal(t, d, eit)
 	assert.NoError(t, err)
 }
+
+func TestWriteEITSection(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	n, err := writeEITSection(w, eit)
+	assert.NoError(t, err)
+	assert.Equal(t, n, buf.Len())
+	assert.Equal(t, int(calcEITSectionLength(eit)), n)
+	d, err := parseEITSection(astikit.NewBytesIterator(buf.Bytes()), n, eit.ServiceID)
+	assert.NoError(t, err)
+	assert.Equal(t, eit, d)
+}
+
+func TestWritePSIDataEIT(t *testing.T) {
+	d := &EITData{
+		Events: []*EITDataEvent{{
+			Descriptors: []*Descriptor{
+				{
+					Content: &DescriptorContent{Items: []*DescriptorContentItem{{
+						ContentNibbleLevel1: 1,
+						ContentNibbleLevel2: 4,
+						UserByte:            2,
+					}}},
+					Length: 2,
+					Tag:    DescriptorTagContent,
+				},
+				{
+					Length: 4,
+					ParentalRating: &DescriptorParentalRating{Items: []*DescriptorParentalRatingItem{{
+						CountryCode: []byte("FRA"),
+						Rating:      9,
+					}}},
+					Tag: DescriptorTagParentalRating,
+				},
+				{
+					Length: 18,
+					ShortEvent: &DescriptorShortEvent{
+						EventName: []byte("name"),
+						Language:  []byte("fra"),
+						Text:      []byte("text text"),
+					},
+					Tag: DescriptorTagShortEvent,
+				},
+			},
+			Duration:      dvbDurationSeconds,
+			EventID:       6,
+			RunningStatus: RunningStatusRunning,
+			StartTime:     dvbTime,
+		}},
+		LastTableID:       uint8(PSITableIDEITStart),
+		OriginalNetworkID: 3,
+		ServiceID:         1,
+		TransportStreamID: 2,
+	}
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	_, err := writePSIData(w, &PSIData{Sections: []*PSISection{{
+		Header: &PSISectionHeader{
+			PrivateBit:             true,
+			SectionLength:          calcEITSectionLength(d),
+			SectionSyntaxIndicator: true,
+			TableID:                PSITableIDEITStart,
+		},
+		Syntax: &PSISectionSyntax{
+			Data: &PSISectionSyntaxData{EIT: d},
+			Header: &PSISectionSyntaxHeader{
+				CurrentNextIndicator: true,
+				TableIDExtension:     d.ServiceID,
+			},
+		},
+	}}})
+	assert.NoError(t, err)
+
+	pd, err := parsePSIData(astikit.NewBytesIterator(buf.Bytes()), dataParseOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, pd.Sections, 1) {
+		assert.Equal(t, d, pd.Sections[0].Syntax.Data.EIT)
+	}
+}
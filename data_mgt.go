@@ -0,0 +1,71 @@
+package astits
+
+import (
+	"fmt"
+
+	"github.com/asticode/go-astikit"
+)
+
+// MGTData represents an ATSC Master Guide Table data
+// Chapter: 6.2 | Link: https://www.atsc.org/wp-content/uploads/2021/04/A65_2013.pdf
+type MGTData struct {
+	Descriptors     []*Descriptor
+	ProtocolVersion uint8
+	Tables          []*MGTDataTable
+}
+
+// MGTDataTable represents a MGT data table
+type MGTDataTable struct {
+	Descriptors   []*Descriptor
+	NumberBytes   uint32
+	PID           uint16
+	Type          uint16
+	VersionNumber uint8
+}
+
+// parseMGTSection parses a MGT section
+func parseMGTSection(i *astikit.BytesIterator) (d *MGTData, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(3); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create data
+	d = &MGTData{ProtocolVersion: uint8(bs[0])}
+
+	// Loop through tables
+	for idx := 0; idx < int(uint16(bs[1])<<8|uint16(bs[2])); idx++ {
+		// Get next bytes
+		var tbs []byte
+		if tbs, err = i.NextBytesNoCopy(9); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Create table
+		t := &MGTDataTable{
+			NumberBytes:   uint32(tbs[5])<<24 | uint32(tbs[6])<<16 | uint32(tbs[7])<<8 | uint32(tbs[8]),
+			PID:           uint16(tbs[2]&0x1f)<<8 | uint16(tbs[3]),
+			Type:          uint16(tbs[0])<<8 | uint16(tbs[1]),
+			VersionNumber: uint8(tbs[4] & 0x1f),
+		}
+
+		// Descriptors
+		if t.Descriptors, err = parseDescriptors(i); err != nil {
+			err = fmt.Errorf("astits: parsing descriptors failed: %w", err)
+			return
+		}
+
+		// Append table
+		d.Tables = append(d.Tables, t)
+	}
+
+	// Descriptors
+	if d.Descriptors, err = parseDescriptors(i); err != nil {
+		err = fmt.Errorf("astits: parsing descriptors failed: %w", err)
+		return
+	}
+	return
+}
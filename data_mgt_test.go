@@ -0,0 +1,42 @@
+package astits
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+var mgt = &MGTData{
+	Tables: []*MGTDataTable{{
+		NumberBytes:   0x1234,
+		PID:           0x1d00,
+		Type:          0x100,
+		VersionNumber: 3,
+	}},
+}
+
+func mgtBytes() []byte {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	w.Write(uint8(0))            // Protocol version
+	w.Write(uint16(1))           // Tables defined
+	w.Write(uint16(0x100))       // Table #1 type
+	w.Write("111")               // Table #1 reserved
+	w.WriteN(uint16(0x1d00), 13) // Table #1 PID
+	w.Write("111")               // Table #1 reserved
+	w.WriteN(uint8(3), 5)        // Table #1 version number
+	w.Write(uint32(0x1234))      // Table #1 number bytes
+	w.Write("1111")              // Table #1 reserved
+	w.WriteN(uint16(0), 12)      // Table #1 descriptors length
+	w.Write("1111")              // Reserved
+	w.WriteN(uint16(0), 12)      // Descriptors length
+	return buf.Bytes()
+}
+
+func TestParseMGTSection(t *testing.T) {
+	d, err := parseMGTSection(astikit.NewBytesIterator(mgtBytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, mgt, d)
+}
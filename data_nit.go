@@ -78,3 +78,46 @@ func parseNITSection(i *astikit.BytesIterator, tableIDExtension uint16) (d *NITD
 	}
 	return
 }
+
+func calcNITSectionLength(d *NITData) uint16 {
+	ret := uint16(4) // network descriptors and transport stream loop lengths
+	ret += calcDescriptorsLength(d.NetworkDescriptors)
+	ret += calcNITTransportStreamLoopLength(d)
+	return ret
+}
+
+func calcNITTransportStreamLoopLength(d *NITData) uint16 {
+	ret := uint16(0)
+	for _, ts := range d.TransportStreams {
+		ret += 6 // transport stream ID, original network ID and descriptors length
+		ret += calcDescriptorsLength(ts.TransportDescriptors)
+	}
+	return ret
+}
+
+func writeNITSection(w *astikit.BitsWriter, d *NITData) (int, error) {
+	b := astikit.NewBitsWriterBatch(w)
+
+	bytesWritten, err := writeDescriptorsWithLength(w, d.NetworkDescriptors)
+	if err != nil {
+		return 0, err
+	}
+
+	b.WriteN(uint8(0xff), 4) // reserved for future use
+	b.WriteN(calcNITTransportStreamLoopLength(d), 12)
+	bytesWritten += 2
+
+	for _, ts := range d.TransportStreams {
+		b.Write(ts.TransportStreamID)
+		b.Write(ts.OriginalNetworkID)
+		bytesWritten += 4
+
+		n, err := writeDescriptorsWithLength(w, ts.TransportDescriptors)
+		if err != nil {
+			return 0, err
+		}
+		bytesWritten += n
+	}
+
+	return bytesWritten, b.Err()
+}
@@ -38,3 +38,15 @@ func TestParseNITSection(t *testing.T) {
 	assert.Equal(t, d, nit)
 	assert.NoError(t, err)
 }
+
+func TestWriteNITSection(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	n, err := writeNITSection(w, nit)
+	assert.NoError(t, err)
+	assert.Equal(t, n, buf.Len())
+	assert.Equal(t, int(calcNITSectionLength(nit)), n)
+	d, err := parseNITSection(astikit.NewBytesIterator(buf.Bytes()), nit.NetworkID)
+	assert.NoError(t, err)
+	assert.Equal(t, nit, d)
+}
@@ -27,6 +27,13 @@ const (
 	StreamIDPrivateStream2 = 191
 )
 
+// PTS DTS prefixes
+const (
+	ptsOrDTSPrefixDTS        = 0x1
+	ptsOrDTSPrefixPTSOnly    = 0x2
+	ptsOrDTSPrefixPTSWithDTS = 0x3
+)
+
 // Trick mode controls
 const (
 	TrickModeControlFastForward = 0
@@ -109,22 +116,51 @@ type DSMTrickMode struct {
 	TrickModeControl    uint8
 }
 
+// PayloadLength returns the authoritative payload size, which is the PES packet length minus the optional header,
+// clamped to the data that is actually available. When the PES packet length is zero, the payload is unbounded and
+// the data length is returned. This is useful to split private data streams such as DVB teletext or DSM-CC.
+func (d *PESData) PayloadLength() int {
+	if d.Header == nil || d.Header.PacketLength == 0 {
+		return len(d.Data)
+	}
+	l := int(d.Header.PacketLength)
+	if d.Header.OptionalHeader != nil {
+		l -= 3 + int(d.Header.OptionalHeader.HeaderLength)
+	}
+	if l < 0 {
+		l = 0
+	} else if l > len(d.Data) {
+		l = len(d.Data)
+	}
+	return l
+}
+
 func (h *PESHeader) IsVideoStream() bool {
 	return h.StreamID == 0xe0 ||
 		h.StreamID == 0xfd
 }
 
 // parsePESData parses a PES data
-func parsePESData(i *astikit.BytesIterator) (d *PESData, err error) {
+// strict indicates whether a PES whose packet length exceeds the available bytes should be rejected with
+// ErrPESTruncated rather than clamped to the available bytes, and whether PTS and DTS whose prefix or marker bits are
+// invalid should be rejected with ErrPESTimestampInvalid
+// r is optional and, when provided, data is parsed into it and its data bytes are copied into its reused buffer if
+// reuse is enabled, whereas data bytes alias the iterator bytes if zero copy is enabled
+func parsePESData(i *astikit.BytesIterator, strict bool, r *dataReuser) (d *PESData, err error) {
 	// Create data
-	d = &PESData{}
+	if r != nil && r.reuse {
+		r.pes = PESData{}
+		d = &r.pes
+	} else {
+		d = &PESData{}
+	}
 
 	// Skip first 3 bytes that are there to identify the PES payload
 	i.Seek(3)
 
 	// Parse header
 	var dataStart, dataEnd int
-	if d.Header, dataStart, dataEnd, err = parsePESHeader(i); err != nil {
+	if d.Header, dataStart, dataEnd, err = parsePESHeader(i, strict); err != nil {
 		err = fmt.Errorf("astits: parsing PES header failed: %w", err)
 		return
 	}
@@ -135,10 +171,36 @@ func parsePESData(i *astikit.BytesIterator) (d *PESData, err error) {
 		return
 	}
 
+	// Truncated data
+	if dataEnd > i.Len() {
+		if strict {
+			err = fmt.Errorf("astits: data end %d is after payload end %d: %w", dataEnd, i.Len(), ErrPESTruncated)
+			return
+		}
+		dataEnd = i.Len()
+		if dataEnd < dataStart {
+			dataEnd = dataStart
+		}
+	}
+
 	// Seek to data
 	i.Seek(dataStart)
 
 	// Extract data
+	if r != nil {
+		var bs []byte
+		if bs, err = i.NextBytesNoCopy(dataEnd - dataStart); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+		if r.zeroCopy {
+			d.Data = bs
+			return
+		}
+		r.pesData = append(r.pesData[:0], bs...)
+		d.Data = r.pesData
+		return
+	}
 	if d.Data, err = i.NextBytes(dataEnd - dataStart); err != nil {
 		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
 		return
@@ -152,7 +214,7 @@ func hasPESOptionalHeader(streamID uint8) bool {
 }
 
 // parsePESHeader parses a PES header
-func parsePESHeader(i *astikit.BytesIterator) (h *PESHeader, dataStart, dataEnd int, err error) {
+func parsePESHeader(i *astikit.BytesIterator, strict bool) (h *PESHeader, dataStart, dataEnd int, err error) {
 	// Create header
 	h = &PESHeader{}
 
@@ -185,7 +247,7 @@ func parsePESHeader(i *astikit.BytesIterator) (h *PESHeader, dataStart, dataEnd
 
 	// Optional header
 	if hasPESOptionalHeader(h.StreamID) {
-		if h.OptionalHeader, dataStart, err = parsePESOptionalHeader(i); err != nil {
+		if h.OptionalHeader, dataStart, err = parsePESOptionalHeader(i, strict); err != nil {
 			err = fmt.Errorf("astits: parsing PES optional header failed: %w", err)
 			return
 		}
@@ -196,7 +258,7 @@ func parsePESHeader(i *astikit.BytesIterator) (h *PESHeader, dataStart, dataEnd
 }
 
 // parsePESOptionalHeader parses a PES optional header
-func parsePESOptionalHeader(i *astikit.BytesIterator) (h *PESOptionalHeader, dataStart int, err error) {
+func parsePESOptionalHeader(i *astikit.BytesIterator, strict bool) (h *PESOptionalHeader, dataStart int, err error) {
 	// Create header
 	h = &PESOptionalHeader{}
 
@@ -256,15 +318,33 @@ func parsePESOptionalHeader(i *astikit.BytesIterator) (h *PESOptionalHeader, dat
 
 	// PTS/DTS
 	if h.PTSDTSIndicator == PTSDTSIndicatorOnlyPTS {
+		if strict {
+			if err = checkPTSOrDTS(i, ptsOrDTSPrefixPTSOnly); err != nil {
+				err = fmt.Errorf("astits: checking PTS failed: %w", err)
+				return
+			}
+		}
 		if h.PTS, err = parsePTSOrDTS(i); err != nil {
 			err = fmt.Errorf("astits: parsing PTS failed: %w", err)
 			return
 		}
 	} else if h.PTSDTSIndicator == PTSDTSIndicatorBothPresent {
+		if strict {
+			if err = checkPTSOrDTS(i, ptsOrDTSPrefixPTSWithDTS); err != nil {
+				err = fmt.Errorf("astits: checking PTS failed: %w", err)
+				return
+			}
+		}
 		if h.PTS, err = parsePTSOrDTS(i); err != nil {
 			err = fmt.Errorf("astits: parsing PTS failed: %w", err)
 			return
 		}
+		if strict {
+			if err = checkPTSOrDTS(i, ptsOrDTSPrefixDTS); err != nil {
+				err = fmt.Errorf("astits: checking DTS failed: %w", err)
+				return
+			}
+		}
 		if h.DTS, err = parsePTSOrDTS(i); err != nil {
 			err = fmt.Errorf("astits: parsing PTS failed: %w", err)
 			return
@@ -314,7 +394,7 @@ func parsePESOptionalHeader(i *astikit.BytesIterator) (h *PESOptionalHeader, dat
 			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
 			return
 		}
-		h.CRC = uint16(bs[0])>>8 | uint16(bs[1])
+		h.CRC = uint16(bs[0])<<8 | uint16(bs[1])
 	}
 
 	// Extension
@@ -419,6 +499,31 @@ func parsePTSOrDTS(i *astikit.BytesIterator) (cr *ClockReference, err error) {
 	return
 }
 
+// checkPTSOrDTS checks the 4-bit prefix and the 3 marker bits of the next PTS or DTS without consuming it
+func checkPTSOrDTS(i *astikit.BytesIterator, prefix uint8) (err error) {
+	// Get next bytes
+	offset := i.Offset()
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(5); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+	i.Seek(offset)
+
+	// Check prefix
+	if p := uint8(bs[0] >> 4); p != prefix {
+		err = fmt.Errorf("astits: prefix %#x is not %#x: %w", p, prefix, ErrPESTimestampInvalid)
+		return
+	}
+
+	// Check marker bits
+	if bs[0]&0x1 == 0 || bs[2]&0x1 == 0 || bs[4]&0x1 == 0 {
+		err = fmt.Errorf("astits: marker bits are not set: %w", ErrPESTimestampInvalid)
+		return
+	}
+	return
+}
+
 // parseESCR parses an ESCR
 func parseESCR(i *astikit.BytesIterator) (cr *ClockReference, err error) {
 	var bs []byte
@@ -453,10 +558,11 @@ func calcPESDataLength(h *PESHeader, payloadLeft []byte, isPayloadStart bool, by
 // first packet will contain PES header with optional PES header and payload, if possible
 // all consequential packets will contain just payload
 // for the last packet caller must add AF with stuffing, see calcPESDataLength
-func writePESData(w *astikit.BitsWriter, h *PESHeader, payloadLeft []byte, isPayloadStart bool, bytesAvailable int) (totalBytesWritten, payloadBytesWritten int, err error) {
+// videoPacketLength indicates whether the packet length of video PES packets should be written whenever it fits
+func writePESData(w *astikit.BitsWriter, h *PESHeader, payloadLeft []byte, isPayloadStart, videoPacketLength bool, bytesAvailable int) (totalBytesWritten, payloadBytesWritten int, err error) {
 	if isPayloadStart {
 		var n int
-		n, err = writePESHeader(w, h, len(payloadLeft))
+		n, err = writePESHeader(w, h, len(payloadLeft), videoPacketLength)
 		if err != nil {
 			return
 		}
@@ -477,22 +583,30 @@ func writePESData(w *astikit.BitsWriter, h *PESHeader, payloadLeft []byte, isPay
 	return
 }
 
-func writePESHeader(w *astikit.BitsWriter, h *PESHeader, payloadSize int) (int, error) {
+// calcPESPacketLength returns the packet length of a PES whose payload is payloadSize bytes long
+func calcPESPacketLength(h *PESHeader, payloadSize int) int {
+	l := payloadSize
+	if hasPESOptionalHeader(h.StreamID) {
+		l += int(calcPESOptionalHeaderLength(h.OptionalHeader))
+	}
+	return l
+}
+
+func writePESHeader(w *astikit.BitsWriter, h *PESHeader, payloadSize int, videoPacketLength bool) (int, error) {
 	b := astikit.NewBitsWriterBatch(w)
 
 	b.WriteN(uint32(0x000001), 24) // packet_start_code_prefix
 	b.Write(h.StreamID)
 
-	pesPacketLength := 0
-
-	if !h.IsVideoStream() {
-		pesPacketLength = payloadSize
-		if hasPESOptionalHeader(h.StreamID) {
-			pesPacketLength += int(calcPESOptionalHeaderLength(h.OptionalHeader))
-		}
-		if pesPacketLength > 0xffff {
+	// Packet length can only be zero for video streams: it is written as zero unless videoPacketLength is true and
+	// the actual one fits
+	pesPacketLength := calcPESPacketLength(h, payloadSize)
+	if h.IsVideoStream() {
+		if !videoPacketLength || pesPacketLength > 0xffff {
 			pesPacketLength = 0
 		}
+	} else if pesPacketLength > 0xffff {
+		return 0, fmt.Errorf("astits: packet length %d of non-video PES is too big: %w", pesPacketLength, ErrPESPacketTooLong)
 	}
 
 	b.Write(uint16(pesPacketLength))
@@ -541,7 +655,7 @@ func calcPESOptionalHeaderDataLength(h *PESOptionalHeader) (length uint8) {
 	}
 
 	if h.HasCRC {
-		//length += 4 // TODO
+		length += 2
 	}
 
 	if h.HasExtension {
@@ -590,8 +704,7 @@ func writePESOptionalHeader(w *astikit.BitsWriter, h *PESOptionalHeader) (int, e
 	b.Write(h.HasESRate)
 	b.Write(h.HasDSMTrickMode)
 	b.Write(h.HasAdditionalCopyInfo)
-	b.Write(false) // CRC of previous PES packet. not supported yet
-	//b.Write(h.HasCRC)
+	b.Write(h.HasCRC)
 	b.Write(h.HasExtension)
 
 	pesOptionalHeaderDataLength := calcPESOptionalHeaderDataLength(h)
@@ -651,7 +764,8 @@ func writePESOptionalHeader(w *astikit.BitsWriter, h *PESOptionalHeader) (int, e
 	}
 
 	if h.HasCRC {
-		// TODO, not supported
+		b.Write(h.CRC)
+		bytesWritten += 2
 	}
 
 	if h.HasExtension {
@@ -2,6 +2,7 @@ package astits
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/asticode/go-astikit"
@@ -303,7 +304,7 @@ var pesTestCases = []pesTestCase{
 			w.Write(dsmTrickModeSlowBytes())     // DSM trick mode
 			w.Write("11111111")                  // Additional copy info
 			if withCRC {
-				w.Write(uint16(4)) // CRC
+				w.Write(uint16(0x1234)) // CRC
 			}
 			// Extension starts here
 			w.Write("1")                        // Private data flag
@@ -334,7 +335,7 @@ var pesTestCases = []pesTestCase{
 			Header: &PESHeader{
 				OptionalHeader: &PESOptionalHeader{
 					AdditionalCopyInfo:              127,
-					CRC:                             4,
+					CRC:                             0x1234,
 					DataAlignmentIndicator:          true,
 					DSMTrickMode:                    dsmTrickModeSlow,
 					DTS:                             dtsClockReference,
@@ -399,21 +400,127 @@ func TestParsePESData(t *testing.T) {
 			tc.headerBytesFunc(w, true, true)
 			tc.optionalHeaderBytesFunc(w, true, true)
 			tc.bytesFunc(w, true, true)
-			d, err := parsePESData(astikit.NewBytesIterator(buf.Bytes()))
+			d, err := parsePESData(astikit.NewBytesIterator(buf.Bytes()), false, nil)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.pesData, d)
 		})
 	}
 }
 
+func TestParsePESDataTruncated(t *testing.T) {
+	// Packet length is 4 but only 2 data bytes are available
+	buf := bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &buf})
+	pesTestCases[0].headerBytesFunc(w, true, true)
+	w.Write([]byte("da"))
+	bs := buf.Bytes()
+
+	// Default mode clamps data
+	d, err := parsePESData(astikit.NewBytesIterator(bs), false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, pesTestCases[0].pesData.Header, d.Header)
+	assert.Equal(t, []byte("da"), d.Data)
+
+	// Strict mode returns an error
+	_, err = parsePESData(astikit.NewBytesIterator(bs), true, nil)
+	assert.True(t, errors.Is(err, ErrPESTruncated))
+}
+
+func TestParsePESDataTimestampMarkers(t *testing.T) {
+	pesBytes := func(pts, dts []byte) []byte {
+		buf := bytes.Buffer{}
+		w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &buf})
+		w.Write("000000000000000000000001") // Prefix
+		w.Write(uint8(0xe0))                // Stream ID
+		w.Write(uint16(0))                  // Packet length
+		w.Write("10000000")                 // Marker bits and flags
+		if dts == nil {
+			w.Write("10000000") // PTS/DTS indicator and flags
+		} else {
+			w.Write("11000000") // PTS/DTS indicator and flags
+		}
+		w.Write(uint8(len(pts) + len(dts))) // Header length
+		w.Write(pts)                        // PTS
+		w.Write(dts)                        // DTS
+		w.Write([]byte("data"))             // Data
+		return buf.Bytes()
+	}
+	withoutMarker := func(b []byte) []byte {
+		b = append([]byte{}, b...)
+		b[2] &^= 0x1
+		return b
+	}
+
+	for _, c := range []struct {
+		b     []byte
+		name  string
+		valid bool
+	}{
+		{b: pesBytes(ptsBytes("0010"), nil), name: "pts only", valid: true},
+		{b: pesBytes(ptsBytes("0011"), dtsBytes("0001")), name: "pts and dts", valid: true},
+		{b: pesBytes(ptsBytes("0011"), nil), name: "pts only with invalid prefix"},
+		{b: pesBytes(ptsBytes("0010"), dtsBytes("0001")), name: "pts with invalid prefix"},
+		{b: pesBytes(ptsBytes("0011"), dtsBytes("0011")), name: "dts with invalid prefix"},
+		{b: pesBytes(withoutMarker(ptsBytes("0010")), nil), name: "pts with invalid marker"},
+		{b: pesBytes(ptsBytes("0011"), withoutMarker(dtsBytes("0001"))), name: "dts with invalid marker"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			// Default mode doesn't check timestamps
+			d, err := parsePESData(astikit.NewBytesIterator(c.b), false, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("data"), d.Data)
+
+			// Strict mode does
+			d, err = parsePESData(astikit.NewBytesIterator(c.b), true, nil)
+			if c.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, ptsClockReference, d.Header.OptionalHeader.PTS)
+			} else {
+				assert.True(t, errors.Is(err, ErrPESTimestampInvalid))
+			}
+		})
+	}
+}
+
+func TestPESDataPayloadLength(t *testing.T) {
+	// Private stream PES followed by unrelated bytes
+	buf := bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &buf})
+	w.Write("000000000000000000000001")    // Prefix
+	w.Write(uint8(StreamIDPrivateStream1)) // Stream ID
+	w.Write(uint16(13))                    // Packet length
+	w.Write("10000000")                    // Marker bits and flags
+	w.Write("00000000")                    // Flags
+	w.Write(uint8(2))                      // Header length
+	w.Write([]byte{0xff, 0xff})            // Stuffing
+	w.Write([]byte("teletext"))            // Data
+	w.Write([]byte("next"))                // Unrelated bytes
+	bs := buf.Bytes()
+
+	d, err := parsePESData(astikit.NewBytesIterator(bs), false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(13), d.Header.PacketLength)
+	assert.Equal(t, 8, d.PayloadLength())
+	assert.Equal(t, []byte("teletext"), d.Data[:d.PayloadLength()])
+
+	// Payload length is clamped to the available data
+	d, err = parsePESData(astikit.NewBytesIterator(bs[:len(bs)-8]), false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, d.PayloadLength())
+
+	// Unbounded PES
+	d = &PESData{Data: []byte("data"), Header: &PESHeader{StreamID: 0xe0}}
+	assert.Equal(t, 4, d.PayloadLength())
+}
+
 func TestWritePESData(t *testing.T) {
 	for _, tc := range pesTestCases {
 		t.Run(tc.name, func(t *testing.T) {
 			bufExpected := bytes.Buffer{}
 			wExpected := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &bufExpected})
-			tc.headerBytesFunc(wExpected, false, false)
-			tc.optionalHeaderBytesFunc(wExpected, false, false)
-			tc.bytesFunc(wExpected, false, false)
+			tc.headerBytesFunc(wExpected, false, true)
+			tc.optionalHeaderBytesFunc(wExpected, false, true)
+			tc.bytesFunc(wExpected, false, true)
 
 			bufActual := bytes.Buffer{}
 			wActual := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &bufActual})
@@ -428,6 +535,7 @@ func TestWritePESData(t *testing.T) {
 					tc.pesData.Header,
 					tc.pesData.Data[payloadPos:],
 					start,
+					false,
 					MpegTsPacketSize-mpegTsPacketHeaderSize,
 				)
 				assert.NoError(t, err)
@@ -449,13 +557,13 @@ func TestWritePESHeader(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			bufExpected := bytes.Buffer{}
 			wExpected := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &bufExpected})
-			tc.headerBytesFunc(wExpected, false, false)
-			tc.optionalHeaderBytesFunc(wExpected, false, false)
+			tc.headerBytesFunc(wExpected, false, true)
+			tc.optionalHeaderBytesFunc(wExpected, false, true)
 
 			bufActual := bytes.Buffer{}
 			wActual := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &bufActual})
 
-			n, err := writePESHeader(wActual, tc.pesData.Header, len(tc.pesData.Data))
+			n, err := writePESHeader(wActual, tc.pesData.Header, len(tc.pesData.Data), false)
 			assert.NoError(t, err)
 			assert.Equal(t, n, bufActual.Len())
 			assert.Equal(t, bufExpected.Len(), bufActual.Len())
@@ -469,7 +577,7 @@ func TestWritePESOptionalHeader(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			bufExpected := bytes.Buffer{}
 			wExpected := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &bufExpected})
-			tc.optionalHeaderBytesFunc(wExpected, false, false)
+			tc.optionalHeaderBytesFunc(wExpected, false, true)
 
 			bufActual := bytes.Buffer{}
 			wActual := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &bufActual})
@@ -499,7 +607,7 @@ func BenchmarkParsePESData(b *testing.B) {
 		b.Run(tc.name, func(b *testing.B) {
 			b.ReportAllocs()
 			for i := 0; i < b.N; i++ {
-				parsePESData(astikit.NewBytesIterator(bss[ti]))
+				parsePESData(astikit.NewBytesIterator(bss[ti]), false, nil)
 			}
 		})
 	}
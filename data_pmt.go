@@ -26,6 +26,7 @@ const (
 	StreamTypeH264Video                  StreamType = 0x1B // Rec. ITU-T H.264 | ISO/IEC 14496-10
 	StreamTypeH265Video                  StreamType = 0x24 // Rec. ITU-T H.265 | ISO/IEC 23008-2
 	StreamTypeHEVCVideo                  StreamType = 0x24
+	StreamTypeVVCVideo                   StreamType = 0x33 // Rec. ITU-T H.266 | ISO/IEC 23090-3
 	StreamTypeCAVSVideo                  StreamType = 0x42
 	StreamTypeVC1Video                   StreamType = 0xea
 	StreamTypeDIRACVideo                 StreamType = 0xd1
@@ -52,6 +53,110 @@ type PMTElementaryStream struct {
 	StreamType                  StreamType    // This defines the structure of the data contained within the elementary packet identifier.
 }
 
+// registrationFormatIdentifierAV1 is the registration descriptor format identifier of AV1 streams ("AV01")
+// Link: https://aomediacodec.github.io/av1-mpeg2-ts/
+const registrationFormatIdentifierAV1 = 0x41563031
+
+// registrationCodecs indexes codec names by registration descriptor format identifier
+// Link: https://smpte-ra.org/registered-mpeg-ts-ids
+var registrationCodecs = map[uint32]string{
+	0x41432d33:                      "AC3 Audio",  // AC-3
+	0x41432d34:                      "AC4 Audio",  // AC-4
+	registrationFormatIdentifierAV1: "AV1 Video",  // AV01
+	0x45414333:                      "EAC3 Audio", // EAC3
+	0x48455643:                      "H265 Video", // HEVC
+	0x4f707573:                      "Opus Audio", // Opus
+	0x56432d31:                      "VC1 Video",  // VC-1
+}
+
+// Codec returns the name of the codec carried by the elementary stream. Registration descriptors are looked up
+// first since private streams, such as Opus or AV1 ones, are only identified this way. It falls back to the
+// stream type name otherwise.
+func (es *PMTElementaryStream) Codec() string {
+	for _, d := range FindDescriptors(es.ElementaryStreamDescriptors, DescriptorTagRegistration) {
+		if d.Registration == nil {
+			continue
+		}
+		if c, ok := registrationCodecs[d.Registration.FormatIdentifier]; ok {
+			return c
+		}
+	}
+	return es.StreamType.String()
+}
+
+// IsAV1 checks whether the elementary stream carries AV1 video, which is indicated by a private data stream type
+// and a registration descriptor whose format identifier is "AV01"
+func (es *PMTElementaryStream) IsAV1() bool {
+	if es.StreamType != StreamTypePrivateData {
+		return false
+	}
+	for _, d := range FindDescriptors(es.ElementaryStreamDescriptors, DescriptorTagRegistration) {
+		if d.Registration != nil && d.Registration.FormatIdentifier == registrationFormatIdentifierAV1 {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSubtitle checks whether the elementary stream carries DVB subtitles, which is indicated by a subtitling descriptor
+func (es *PMTElementaryStream) IsSubtitle() bool {
+	return FindDescriptor(es.ElementaryStreamDescriptors, DescriptorTagSubtitling) != nil
+}
+
+// IsTeletext checks whether the elementary stream carries teletext, which is indicated by a teletext or VBI teletext
+// descriptor
+func (es *PMTElementaryStream) IsTeletext() bool {
+	return FindDescriptor(es.ElementaryStreamDescriptors, DescriptorTagTeletext) != nil ||
+		FindDescriptor(es.ElementaryStreamDescriptors, DescriptorTagVBITeletext) != nil
+}
+
+// StreamByPID returns the elementary stream with the provided PID, if any
+func (d *PMTData) StreamByPID(pid uint16) (*PMTElementaryStream, bool) {
+	for _, es := range d.ElementaryStreams {
+		if es.ElementaryPID == pid {
+			return es, true
+		}
+	}
+	return nil, false
+}
+
+// VideoStreams returns the video elementary streams
+func (d *PMTData) VideoStreams() (ess []*PMTElementaryStream) {
+	for _, es := range d.ElementaryStreams {
+		if es.StreamType.IsVideo() {
+			ess = append(ess, es)
+		}
+	}
+	return
+}
+
+// AudioStreams returns the audio elementary streams
+func (d *PMTData) AudioStreams() (ess []*PMTElementaryStream) {
+	for _, es := range d.ElementaryStreams {
+		if es.StreamType.IsAudio() {
+			ess = append(ess, es)
+		}
+	}
+	return
+}
+
+// ECMPIDs returns the CA PIDs found in the program-level and elementary-stream-level CA descriptors, which carry ECMs
+func (d *PMTData) ECMPIDs() (pids []uint16) {
+	for _, dsc := range FindDescriptors(d.ProgramDescriptors, DescriptorTagCA) {
+		if dsc.CA != nil {
+			pids = append(pids, dsc.CA.CAPID)
+		}
+	}
+	for _, es := range d.ElementaryStreams {
+		for _, dsc := range FindDescriptors(es.ElementaryStreamDescriptors, DescriptorTagCA) {
+			if dsc.CA != nil {
+				pids = append(pids, dsc.CA.CAPID)
+			}
+		}
+	}
+	return
+}
+
 // parsePMTSection parses a PMT section
 func parsePMTSection(i *astikit.BytesIterator, offsetSectionsEnd int, tableIDExtension uint16) (d *PMTData, err error) {
 	// Create data
@@ -171,6 +276,7 @@ func (t StreamType) IsVideo() bool {
 		StreamTypeMPEG4Video,
 		StreamTypeH264Video,
 		StreamTypeH265Video,
+		StreamTypeVVCVideo,
 		StreamTypeCAVSVideo,
 		StreamTypeVC1Video,
 		StreamTypeDIRACVideo:
@@ -220,6 +326,8 @@ func (t StreamType) String() string {
 		return "H264 Video"
 	case StreamTypeH265Video:
 		return "H265 Video"
+	case StreamTypeVVCVideo:
+		return "VVC Video"
 	case StreamTypeCAVSVideo:
 		return "CAVS Video"
 	case StreamTypeVC1Video:
@@ -240,10 +348,44 @@ func (t StreamType) String() string {
 	return "Unknown"
 }
 
+// MIMEType returns the MIME type of the elementary stream, which is useful to set HTTP content types or to build
+// manifests. "application/octet-stream" is returned when the stream type has no well-known MIME type.
+func (t StreamType) MIMEType() string {
+	switch t {
+	case StreamTypeMPEG1Video, StreamTypeMPEG2Video:
+		return "video/mpeg"
+	case StreamTypeMPEG1Audio, StreamTypeMPEG2Audio:
+		return "audio/mpeg"
+	case StreamTypeAACAudio:
+		return "audio/aac"
+	case StreamTypeMPEG4Video:
+		return "video/mp4v-es"
+	case StreamTypeAACLATMAudio:
+		return "audio/MP4A-LATM"
+	case StreamTypeH264Video:
+		return "video/H264"
+	case StreamTypeH265Video:
+		return "video/H265"
+	case StreamTypeVVCVideo:
+		return "video/H266"
+	case StreamTypeVC1Video:
+		return "video/vc1"
+	case StreamTypeAC3Audio:
+		return "audio/ac3"
+	case StreamTypeDTSAudio:
+		return "audio/vnd.dts"
+	case StreamTypeTRUEHDAudio:
+		return "audio/vnd.dolby.mlp"
+	case StreamTypeEAC3Audio:
+		return "audio/eac3"
+	}
+	return "application/octet-stream"
+}
+
 func (t StreamType) ToPESStreamID() uint8 {
 	switch t {
 	case StreamTypeMPEG1Video, StreamTypeMPEG2Video, StreamTypeMPEG4Video, StreamTypeH264Video,
-		StreamTypeH265Video, StreamTypeCAVSVideo, StreamTypeVC1Video:
+		StreamTypeH265Video, StreamTypeVVCVideo, StreamTypeCAVSVideo, StreamTypeVC1Video:
 		return 0xe0
 	case StreamTypeDIRACVideo:
 		return 0xfd
@@ -50,6 +50,122 @@ func TestWritePMTSection(t *testing.T) {
 	assert.Equal(t, pmtBytes(), buf.Bytes())
 }
 
+func TestPMTDataStreams(t *testing.T) {
+	audio := &PMTElementaryStream{ElementaryPID: 0x101, StreamType: StreamTypeAACAudio}
+	video := &PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video}
+	data := &PMTElementaryStream{ElementaryPID: 0x102, StreamType: StreamTypePrivateData}
+	d := &PMTData{ElementaryStreams: []*PMTElementaryStream{video, audio, data}}
+
+	es, ok := d.StreamByPID(0x101)
+	assert.True(t, ok)
+	assert.Equal(t, audio, es)
+	_, ok = d.StreamByPID(0x200)
+	assert.False(t, ok)
+
+	assert.Equal(t, []*PMTElementaryStream{video}, d.VideoStreams())
+	assert.Equal(t, []*PMTElementaryStream{audio}, d.AudioStreams())
+	assert.Empty(t, (&PMTData{}).VideoStreams())
+}
+
+func TestStreamTypeVVCVideo(t *testing.T) {
+	assert.True(t, StreamTypeVVCVideo.IsVideo())
+	assert.False(t, StreamTypeVVCVideo.IsAudio())
+	assert.Equal(t, "VVC Video", StreamTypeVVCVideo.String())
+	assert.Equal(t, uint8(0xe0), StreamTypeVVCVideo.ToPESStreamID())
+}
+
+func TestStreamTypeMIMEType(t *testing.T) {
+	for st, m := range map[StreamType]string{
+		StreamTypeAACAudio:       "audio/aac",
+		StreamTypeAC3Audio:       "audio/ac3",
+		StreamTypeEAC3Audio:      "audio/eac3",
+		StreamTypeH264Video:      "video/H264",
+		StreamTypeH265Video:      "video/H265",
+		StreamTypeMPEG2Audio:     "audio/mpeg",
+		StreamTypeMPEG2Video:     "video/mpeg",
+		StreamTypePrivateSection: "application/octet-stream",
+		StreamTypeSCTE35:         "application/octet-stream",
+	} {
+		assert.Equal(t, m, st.MIMEType(), st.String())
+	}
+}
+
+func TestPMTElementaryStreamCodec(t *testing.T) {
+	newES := func(t StreamType, formatIdentifier uint32) *PMTElementaryStream {
+		return &PMTElementaryStream{
+			ElementaryPID: 0x100,
+			ElementaryStreamDescriptors: []*Descriptor{{
+				Registration: &DescriptorRegistration{FormatIdentifier: formatIdentifier},
+				Tag:          DescriptorTagRegistration,
+			}},
+			StreamType: t,
+		}
+	}
+	assert.Equal(t, "Opus Audio", newES(StreamTypePrivateData, 0x4f707573).Codec()) // Opus
+	assert.Equal(t, "AV1 Video", newES(StreamTypePrivateData, 0x41563031).Codec())  // AV01
+	assert.Equal(t, "AC4 Audio", newES(StreamTypePrivateData, 0x41432d34).Codec())  // AC-4
+	assert.Equal(t, "SCTE 35", newES(StreamTypeSCTE35, 0x43554549).Codec())         // CUEI
+	assert.Equal(t, "H264 Video", (&PMTElementaryStream{StreamType: StreamTypeH264Video}).Codec())
+}
+
+func TestPMTElementaryStreamIsAV1(t *testing.T) {
+	es := &PMTElementaryStream{
+		ElementaryPID: 0x100,
+		ElementaryStreamDescriptors: []*Descriptor{{
+			Registration: &DescriptorRegistration{FormatIdentifier: 0x41563031}, // AV01
+			Tag:          DescriptorTagRegistration,
+		}},
+		StreamType: StreamTypePrivateData,
+	}
+	assert.True(t, es.IsAV1())
+
+	es.ElementaryStreamDescriptors[0].Registration.FormatIdentifier = 0x48455643 // HEVC
+	assert.False(t, es.IsAV1())
+
+	assert.False(t, (&PMTElementaryStream{StreamType: StreamTypePrivateData}).IsAV1())
+	assert.False(t, (&PMTElementaryStream{
+		ElementaryStreamDescriptors: []*Descriptor{{Registration: &DescriptorRegistration{FormatIdentifier: 0x41563031}, Tag: DescriptorTagRegistration}},
+		StreamType:                  StreamTypeH264Video,
+	}).IsAV1())
+}
+
+func TestPMTElementaryStreamIsSubtitleAndIsTeletext(t *testing.T) {
+	es := &PMTElementaryStream{
+		ElementaryPID:               0x103,
+		ElementaryStreamDescriptors: []*Descriptor{{Subtitling: &DescriptorSubtitling{}, Tag: DescriptorTagSubtitling}},
+		StreamType:                  StreamTypePrivateData,
+	}
+	assert.True(t, es.IsSubtitle())
+	assert.False(t, es.IsTeletext())
+
+	es.ElementaryStreamDescriptors = []*Descriptor{{Tag: DescriptorTagVBITeletext, VBITeletext: &DescriptorTeletext{}}}
+	assert.False(t, es.IsSubtitle())
+	assert.True(t, es.IsTeletext())
+
+	es.ElementaryStreamDescriptors = []*Descriptor{{Tag: DescriptorTagTeletext, Teletext: &DescriptorTeletext{}}}
+	assert.True(t, es.IsTeletext())
+
+	assert.False(t, (&PMTElementaryStream{StreamType: StreamTypePrivateData}).IsSubtitle())
+}
+
+func TestPMTDataECMPIDs(t *testing.T) {
+	d := &PMTData{
+		ElementaryStreams: []*PMTElementaryStream{
+			{ElementaryPID: 0x100},
+			{
+				ElementaryPID: 0x101,
+				ElementaryStreamDescriptors: []*Descriptor{
+					{Tag: DescriptorTagISO639LanguageAndAudioType},
+					{CA: &DescriptorCA{CAPID: 0x201, CASystemID: 0x0500}, Tag: DescriptorTagCA},
+				},
+			},
+		},
+		ProgramDescriptors: []*Descriptor{{CA: &DescriptorCA{CAPID: 0x200, CASystemID: 0x0500}, Tag: DescriptorTagCA}},
+	}
+	assert.Equal(t, []uint16{0x200, 0x201}, d.ECMPIDs())
+	assert.Empty(t, (&PMTData{}).ECMPIDs())
+}
+
 func BenchmarkParsePMTSection(b *testing.B) {
 	b.ReportAllocs()
 	bs := pmtBytes()
@@ -9,34 +9,43 @@ import (
 // PSI table IDs
 const (
 	PSITableTypeBAT     = "BAT"
+	PSITableTypeCAT     = "CAT"
+	PSITableTypeCVCT    = "CVCT"
 	PSITableTypeDIT     = "DIT"
 	PSITableTypeEIT     = "EIT"
+	PSITableTypeMGT     = "MGT"
 	PSITableTypeNIT     = "NIT"
 	PSITableTypeNull    = "Null"
 	PSITableTypePAT     = "PAT"
 	PSITableTypePMT     = "PMT"
 	PSITableTypeRST     = "RST"
+	PSITableTypeSCTE35  = "SCTE35"
 	PSITableTypeSDT     = "SDT"
 	PSITableTypeSIT     = "SIT"
 	PSITableTypeST      = "ST"
 	PSITableTypeTDT     = "TDT"
 	PSITableTypeTOT     = "TOT"
+	PSITableTypeTSDT    = "TSDT"
+	PSITableTypeTVCT    = "TVCT"
 	PSITableTypeUnknown = "Unknown"
 )
 
 type PSITableID uint16
 
 const (
-	PSITableIDPAT  PSITableID = 0x00
-	PSITableIDPMT  PSITableID = 0x02
-	PSITableIDBAT  PSITableID = 0x4a
-	PSITableIDDIT  PSITableID = 0x7e
-	PSITableIDRST  PSITableID = 0x71
-	PSITableIDSIT  PSITableID = 0x7f
-	PSITableIDST   PSITableID = 0x72
-	PSITableIDTDT  PSITableID = 0x70
-	PSITableIDTOT  PSITableID = 0x73
-	PSITableIDNull PSITableID = 0xff
+	PSITableIDPAT    PSITableID = 0x00
+	PSITableIDCAT    PSITableID = 0x01
+	PSITableIDPMT    PSITableID = 0x02
+	PSITableIDTSDT   PSITableID = 0x03
+	PSITableIDBAT    PSITableID = 0x4a
+	PSITableIDDIT    PSITableID = 0x7e
+	PSITableIDRST    PSITableID = 0x71
+	PSITableIDSCTE35 PSITableID = 0xfc
+	PSITableIDSIT    PSITableID = 0x7f
+	PSITableIDST     PSITableID = 0x72
+	PSITableIDTDT    PSITableID = 0x70
+	PSITableIDTOT    PSITableID = 0x73
+	PSITableIDNull   PSITableID = 0xff
 
 	PSITableIDEITStart    PSITableID = 0x4e
 	PSITableIDEITEnd      PSITableID = 0x6f
@@ -44,6 +53,11 @@ const (
 	PSITableIDSDTVariant2 PSITableID = 0x46
 	PSITableIDNITVariant1 PSITableID = 0x40
 	PSITableIDNITVariant2 PSITableID = 0x41
+
+	// ATSC PSIP tables are only parsed when DemuxerOptATSC is enabled
+	PSITableIDMGT  PSITableID = 0xc7
+	PSITableIDTVCT PSITableID = 0xc8
+	PSITableIDCVCT PSITableID = 0xc9
 )
 
 // PSIData represents a PSI data
@@ -55,9 +69,12 @@ type PSIData struct {
 
 // PSISection represents a PSI section
 type PSISection struct {
-	CRC32  uint32 // A checksum of the entire table excluding the pointer field, pointer filler bytes and the trailing CRC32.
-	Header *PSISectionHeader
-	Syntax *PSISectionSyntax
+	CRC32          uint32 // A checksum of the entire table excluding the pointer field, pointer filler bytes and the trailing CRC32.
+	CRCValid       bool   // Set when the section has no CRC32 or when its CRC32 matches. Sections whose CRC32 doesn't match are only returned when DemuxerOptIgnoreCRCErrors is enabled.
+	Header         *PSISectionHeader
+	LengthMismatch bool   // Set when the bytes consumed by parsing the table data don't match the declared section length. Tables that are not parsed are never flagged.
+	RawSection     []byte // Section bytes, from the table ID to the end of the section. Only set when raw sections are kept.
+	Syntax         *PSISectionSyntax
 }
 
 // PSISectionHeader represents a PSI section header
@@ -86,16 +103,23 @@ type PSISectionSyntaxHeader struct {
 
 // PSISectionSyntaxData represents a PSI section syntax data
 type PSISectionSyntaxData struct {
-	EIT *EITData
-	NIT *NITData
-	PAT *PATData
-	PMT *PMTData
-	SDT *SDTData
-	TOT *TOTData
+	CAT    *CATData
+	EIT    *EITData
+	MGT    *MGTData
+	NIT    *NITData
+	PAT    *PATData
+	PMT    *PMTData
+	SCTE35 *SCTE35Data
+	SDT    *SDTData
+	SIT    *SITData
+	TDT    *TDTData
+	TOT    *TOTData
+	TSDT   *TSDTData
+	VCT    *VCTData
 }
 
 // parsePSIData parses a PSI data
-func parsePSIData(i *astikit.BytesIterator) (d *PSIData, err error) {
+func parsePSIData(i *astikit.BytesIterator, o dataParseOptions) (d *PSIData, err error) {
 	// Init data
 	d = &PSIData{}
 
@@ -116,7 +140,7 @@ func parsePSIData(i *astikit.BytesIterator) (d *PSIData, err error) {
 	var s *PSISection
 	var stop bool
 	for i.HasBytesLeft() && !stop {
-		if s, stop, err = parsePSISection(i); err != nil {
+		if s, stop, err = parsePSISection(i, o); err != nil {
 			err = fmt.Errorf("astits: parsing PSI table failed: %w", err)
 			return
 		}
@@ -126,32 +150,36 @@ func parsePSIData(i *astikit.BytesIterator) (d *PSIData, err error) {
 }
 
 // parsePSISection parses a PSI section
-func parsePSISection(i *astikit.BytesIterator) (s *PSISection, stop bool, err error) {
+// A section whose CRC32 doesn't match is flagged instead of failing when CRC errors are ignored, and its syntax is
+// left empty when it can't be parsed
+func parsePSISection(i *astikit.BytesIterator, o dataParseOptions) (s *PSISection, stop bool, err error) {
 	// Init section
-	s = &PSISection{}
+	s = &PSISection{CRCValid: true}
 
 	// Parse header
-	var offsetStart, offsetSectionsEnd, offsetEnd int
-	if s.Header, offsetStart, _, offsetSectionsEnd, offsetEnd, err = parsePSISectionHeader(i); err != nil {
+	var offsetStart, offsetSectionsStart, offsetSectionsEnd, offsetEnd int
+	s.Header, offsetStart, offsetSectionsStart, offsetSectionsEnd, offsetEnd, err = parsePSISectionHeader(i, o)
+
+	// Sections of unknown tables are only parsed when raw sections are kept, and are most likely stuffing or garbage
+	// when they don't fit in the payload
+	if isUnknownPSITable(s.Header.TableID, o.atsc) && (err != nil || offsetEnd > i.Len()) {
+		err = nil
+		stop = true
+		return
+	} else if err != nil {
 		err = fmt.Errorf("astits: parsing PSI section header failed: %w", err)
 		return
 	}
 
 	// Check whether we need to stop the parsing
-	if shouldStopPSIParsing(s.Header.TableID) {
+	if shouldStopPSIParsing(s.Header.TableID, o) {
 		stop = true
 		return
 	}
 
 	// Check whether there's a syntax section
 	if s.Header.SectionLength > 0 {
-		// Parse syntax
-		if s.Syntax, err = parsePSISectionSyntax(i, s.Header, offsetSectionsEnd); err != nil {
-			err = fmt.Errorf("astits: parsing PSI section syntax failed: %w", err)
-			return
-		}
-
-		// Process CRC32
+		// Process CRC32 first, so that a corrupted section can be detected before its syntax is parsed
 		if s.Header.TableID.hasCRC32() {
 			// Seek to the end of the sections
 			i.Seek(offsetSectionsEnd)
@@ -174,13 +202,40 @@ func parsePSISection(i *astikit.BytesIterator) (s *PSISection, stop bool, err er
 			crc32 := computeCRC32(crc32Data)
 
 			// Check CRC32
-			if crc32 != s.CRC32 {
-				err = fmt.Errorf("astits: Table CRC32 %x != computed CRC32 %x", s.CRC32, crc32)
+			if crc32 != s.CRC32 && o.ignoreCRCErrors {
+				s.CRCValid = false
+			} else if crc32 != s.CRC32 {
+				err = fmt.Errorf("astits: Table CRC32 %x != computed CRC32 %x: %w", s.CRC32, crc32, ErrPSICRCMismatch)
+				return
+			}
+
+			// Seek to the start of the sections
+			i.Seek(offsetSectionsStart)
+		}
+
+		// Parse syntax
+		if s.Syntax, s.LengthMismatch, err = parsePSISectionSyntax(i, s.Header, offsetSectionsEnd); err != nil {
+			// A section whose CRC32 doesn't match is most likely corrupted, therefore it is skipped instead of failing
+			if !s.CRCValid {
+				s.Syntax = nil
+				s.LengthMismatch = false
+				err = nil
+			} else {
+				err = fmt.Errorf("astits: parsing PSI section syntax failed: %w", err)
 				return
 			}
 		}
 	}
 
+	// Keep raw section
+	if o.keepRawSections {
+		i.Seek(offsetStart)
+		if s.RawSection, err = i.NextBytes(offsetEnd - offsetStart); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+	}
+
 	// Seek to the end of the section
 	i.Seek(offsetEnd)
 	return
@@ -198,13 +253,21 @@ func parseCRC32(i *astikit.BytesIterator) (c uint32, err error) {
 }
 
 // shouldStopPSIParsing checks whether the PSI parsing should be stopped
-func shouldStopPSIParsing(tableID PSITableID) bool {
+// ATSC PSIP tables are only known when they are parsed, and unknown tables are parsed as well when raw sections are
+// kept, so that custom tables can be handled by the caller
+func shouldStopPSIParsing(tableID PSITableID, o dataParseOptions) bool {
 	return tableID == PSITableIDNull ||
-		tableID.isUnknown()
+		(isUnknownPSITable(tableID, o.atsc) && !o.keepRawSections)
+}
+
+// isUnknownPSITable checks whether the table is not parsed
+// atsc indicates whether ATSC PSIP tables are parsed
+func isUnknownPSITable(tableID PSITableID, atsc bool) bool {
+	return tableID.isUnknown() && !(atsc && tableID.isATSC())
 }
 
 // parsePSISectionHeader parses a PSI section header
-func parsePSISectionHeader(i *astikit.BytesIterator) (h *PSISectionHeader, offsetStart, offsetSectionsStart, offsetSectionsEnd, offsetEnd int, err error) {
+func parsePSISectionHeader(i *astikit.BytesIterator, o dataParseOptions) (h *PSISectionHeader, offsetStart, offsetSectionsStart, offsetSectionsEnd, offsetEnd int, err error) {
 	// Init
 	h = &PSISectionHeader{}
 	offsetStart = i.Offset()
@@ -223,7 +286,7 @@ func parsePSISectionHeader(i *astikit.BytesIterator) (h *PSISectionHeader, offse
 	h.TableType = h.TableID.Type()
 
 	// Check whether we need to stop the parsing
-	if shouldStopPSIParsing(h.TableID) {
+	if shouldStopPSIParsing(h.TableID, o) {
 		return
 	}
 
@@ -260,10 +323,16 @@ func (t PSITableID) Type() string {
 	switch {
 	case t == PSITableIDBAT:
 		return PSITableTypeBAT
+	case t == PSITableIDCAT:
+		return PSITableTypeCAT
+	case t == PSITableIDCVCT:
+		return PSITableTypeCVCT
 	case t >= PSITableIDEITStart && t <= PSITableIDEITEnd:
 		return PSITableTypeEIT
 	case t == PSITableIDDIT:
 		return PSITableTypeDIT
+	case t == PSITableIDMGT:
+		return PSITableTypeMGT
 	case t == PSITableIDNITVariant1, t == PSITableIDNITVariant2:
 		return PSITableTypeNIT
 	case t == PSITableIDNull:
@@ -274,6 +343,8 @@ func (t PSITableID) Type() string {
 		return PSITableTypePMT
 	case t == PSITableIDRST:
 		return PSITableTypeRST
+	case t == PSITableIDSCTE35:
+		return PSITableTypeSCTE35
 	case t == PSITableIDSDTVariant1, t == PSITableIDSDTVariant2:
 		return PSITableTypeSDT
 	case t == PSITableIDSIT:
@@ -284,6 +355,10 @@ func (t PSITableID) Type() string {
 		return PSITableTypeTDT
 	case t == PSITableIDTOT:
 		return PSITableTypeTOT
+	case t == PSITableIDTSDT:
+		return PSITableTypeTSDT
+	case t == PSITableIDTVCT:
+		return PSITableTypeTVCT
 	default:
 		return PSITableTypeUnknown
 	}
@@ -292,36 +367,53 @@ func (t PSITableID) Type() string {
 // hasPSISyntaxHeader checks whether the section has a syntax header
 func (t PSITableID) hasPSISyntaxHeader() bool {
 	return t == PSITableIDPAT ||
+		t.isATSC() ||
+		t == PSITableIDCAT ||
 		t == PSITableIDPMT ||
 		t == PSITableIDNITVariant1 || t == PSITableIDNITVariant2 ||
 		t == PSITableIDSDTVariant1 || t == PSITableIDSDTVariant2 ||
+		t == PSITableIDSIT ||
+		t == PSITableIDTSDT ||
 		(t >= PSITableIDEITStart && t <= PSITableIDEITEnd)
 }
 
 // hasCRC32 checks whether the table has a CRC32
 func (t PSITableID) hasCRC32() bool {
 	return t == PSITableIDPAT ||
+		t.isATSC() ||
+		t == PSITableIDCAT ||
 		t == PSITableIDPMT ||
 		t == PSITableIDTOT ||
+		t == PSITableIDSCTE35 ||
 		t == PSITableIDNITVariant1 || t == PSITableIDNITVariant2 ||
 		t == PSITableIDSDTVariant1 || t == PSITableIDSDTVariant2 ||
+		t == PSITableIDSIT ||
+		t == PSITableIDTSDT ||
 		(t >= PSITableIDEITStart && t <= PSITableIDEITEnd)
 }
 
+// isATSC checks whether the table is an ATSC PSIP one
+func (t PSITableID) isATSC() bool {
+	return t == PSITableIDMGT || t == PSITableIDTVCT || t == PSITableIDCVCT
+}
+
 func (t PSITableID) isUnknown() bool {
 	switch t {
 	case PSITableIDBAT,
+		PSITableIDCAT,
 		PSITableIDDIT,
 		PSITableIDNITVariant1, PSITableIDNITVariant2,
 		PSITableIDNull,
 		PSITableIDPAT,
 		PSITableIDPMT,
 		PSITableIDRST,
+		PSITableIDSCTE35,
 		PSITableIDSDTVariant1, PSITableIDSDTVariant2,
 		PSITableIDSIT,
 		PSITableIDST,
 		PSITableIDTDT,
-		PSITableIDTOT:
+		PSITableIDTOT,
+		PSITableIDTSDT:
 		return false
 	}
 	if t >= PSITableIDEITStart && t <= PSITableIDEITEnd {
@@ -331,7 +423,9 @@ func (t PSITableID) isUnknown() bool {
 }
 
 // parsePSISectionSyntax parses a PSI section syntax
-func parsePSISectionSyntax(i *astikit.BytesIterator, h *PSISectionHeader, offsetSectionsEnd int) (s *PSISectionSyntax, err error) {
+// lengthMismatch indicates whether parsing the data didn't stop at the end of the sections as declared by the section
+// length
+func parsePSISectionSyntax(i *astikit.BytesIterator, h *PSISectionHeader, offsetSectionsEnd int) (s *PSISectionSyntax, lengthMismatch bool, err error) {
 	// Init
 	s = &PSISectionSyntax{}
 
@@ -344,10 +438,16 @@ func parsePSISectionSyntax(i *astikit.BytesIterator, h *PSISectionHeader, offset
 	}
 
 	// Parse data
+	offsetDataStart := i.Offset()
 	if s.Data, err = parsePSISectionSyntaxData(i, h, s.Header, offsetSectionsEnd); err != nil {
 		err = fmt.Errorf("astits: parsing PSI section syntax data failed: %w", err)
 		return
 	}
+
+	// Tables that are not parsed don't consume any data
+	if o := i.Offset(); o > offsetDataStart && o != offsetSectionsEnd {
+		lengthMismatch = true
+	}
 	return
 }
 
@@ -408,8 +508,18 @@ func parsePSISectionSyntaxData(i *astikit.BytesIterator, h *PSISectionHeader, sh
 	switch h.TableID {
 	case PSITableIDBAT:
 		// TODO Parse BAT
+	case PSITableIDCAT:
+		if d.CAT, err = parseCATSection(i, offsetSectionsEnd); err != nil {
+			err = fmt.Errorf("astits: parsing CAT section failed: %w", err)
+			return
+		}
 	case PSITableIDDIT:
 		// TODO Parse DIT
+	case PSITableIDMGT:
+		if d.MGT, err = parseMGTSection(i); err != nil {
+			err = fmt.Errorf("astits: parsing MGT section failed: %w", err)
+			return
+		}
 	case PSITableIDNITVariant1, PSITableIDNITVariant2:
 		if d.NIT, err = parseNITSection(i, sh.TableIDExtension); err != nil {
 			err = fmt.Errorf("astits: parsing NIT section failed: %w", err)
@@ -427,13 +537,21 @@ func parsePSISectionSyntaxData(i *astikit.BytesIterator, h *PSISectionHeader, sh
 		}
 	case PSITableIDRST:
 		// TODO Parse RST
+	case PSITableIDSCTE35:
+		if d.SCTE35, err = parseSCTE35Section(i); err != nil {
+			err = fmt.Errorf("astits: parsing SCTE35 section failed: %w", err)
+			return
+		}
 	case PSITableIDSDTVariant1, PSITableIDSDTVariant2:
 		if d.SDT, err = parseSDTSection(i, offsetSectionsEnd, sh.TableIDExtension); err != nil {
 			err = fmt.Errorf("astits: parsing PMT section failed: %w", err)
 			return
 		}
 	case PSITableIDSIT:
-		// TODO Parse SIT
+		if d.SIT, err = parseSITSection(i, offsetSectionsEnd); err != nil {
+			err = fmt.Errorf("astits: parsing SIT section failed: %w", err)
+			return
+		}
 	case PSITableIDST:
 		// TODO Parse ST
 	case PSITableIDTOT:
@@ -442,7 +560,20 @@ func parsePSISectionSyntaxData(i *astikit.BytesIterator, h *PSISectionHeader, sh
 			return
 		}
 	case PSITableIDTDT:
-		// TODO Parse TDT
+		if d.TDT, err = parseTDTSection(i); err != nil {
+			err = fmt.Errorf("astits: parsing TDT section failed: %w", err)
+			return
+		}
+	case PSITableIDTSDT:
+		if d.TSDT, err = parseTSDTSection(i, offsetSectionsEnd); err != nil {
+			err = fmt.Errorf("astits: parsing TSDT section failed: %w", err)
+			return
+		}
+	case PSITableIDTVCT, PSITableIDCVCT:
+		if d.VCT, err = parseVCTSection(i, sh.TableIDExtension, h.TableID == PSITableIDCVCT); err != nil {
+			err = fmt.Errorf("astits: parsing VCT section failed: %w", err)
+			return
+		}
 	}
 
 	if h.TableID >= PSITableIDEITStart && h.TableID <= PSITableIDEITEnd {
@@ -461,30 +592,67 @@ func (d *PSIData) toData(firstPacket *Packet, pid uint16) (ds []*DemuxerData) {
 	ds = make([]*DemuxerData, 0, len(d.Sections))
 	for _, s := range d.Sections {
 		// No data
+		n := len(ds)
 		if s.Syntax == nil || s.Syntax.Data == nil {
+			ds = s.appendRawData(ds, n, firstPacket, pid)
 			continue
 		}
 
 		// Switch on table type
 		switch s.Header.TableID {
+		case PSITableIDCAT:
+			ds = append(ds, &DemuxerData{CAT: s.Syntax.Data.CAT, FirstPacket: firstPacket, PID: pid})
+		case PSITableIDMGT:
+			ds = append(ds, &DemuxerData{FirstPacket: firstPacket, MGT: s.Syntax.Data.MGT, PID: pid})
 		case PSITableIDNITVariant1, PSITableIDNITVariant2:
 			ds = append(ds, &DemuxerData{FirstPacket: firstPacket, NIT: s.Syntax.Data.NIT, PID: pid})
 		case PSITableIDPAT:
 			ds = append(ds, &DemuxerData{FirstPacket: firstPacket, PAT: s.Syntax.Data.PAT, PID: pid})
 		case PSITableIDPMT:
 			ds = append(ds, &DemuxerData{FirstPacket: firstPacket, PID: pid, PMT: s.Syntax.Data.PMT})
+		case PSITableIDSCTE35:
+			ds = append(ds, &DemuxerData{FirstPacket: firstPacket, PID: pid, SCTE35: s.Syntax.Data.SCTE35})
 		case PSITableIDSDTVariant1, PSITableIDSDTVariant2:
 			ds = append(ds, &DemuxerData{FirstPacket: firstPacket, PID: pid, SDT: s.Syntax.Data.SDT})
+		case PSITableIDSIT:
+			ds = append(ds, &DemuxerData{FirstPacket: firstPacket, PID: pid, SIT: s.Syntax.Data.SIT})
+		case PSITableIDTDT:
+			ds = append(ds, &DemuxerData{FirstPacket: firstPacket, PID: pid, TDT: s.Syntax.Data.TDT})
 		case PSITableIDTOT:
 			ds = append(ds, &DemuxerData{FirstPacket: firstPacket, PID: pid, TOT: s.Syntax.Data.TOT})
+		case PSITableIDTSDT:
+			ds = append(ds, &DemuxerData{FirstPacket: firstPacket, PID: pid, TSDT: s.Syntax.Data.TSDT})
+		case PSITableIDTVCT, PSITableIDCVCT:
+			ds = append(ds, &DemuxerData{FirstPacket: firstPacket, PID: pid, VCT: s.Syntax.Data.VCT})
 		}
 		if s.Header.TableID >= PSITableIDEITStart && s.Header.TableID <= PSITableIDEITEnd {
 			ds = append(ds, &DemuxerData{EIT: s.Syntax.Data.EIT, FirstPacket: firstPacket, PID: pid})
 		}
+
+		// Raw section and CRC32 status
+		ds = s.appendRawData(ds, n, firstPacket, pid)
 	}
 	return
 }
 
+// appendRawData attaches the raw section and the CRC32 status to the data parsed from the section, which starts at
+// index n. Sections of tables that are not parsed are appended as data containing only their raw section.
+func (s *PSISection) appendRawData(ds []*DemuxerData, n int, firstPacket *Packet, pid uint16) []*DemuxerData {
+	if len(ds) == n {
+		if s.RawSection == nil {
+			return ds
+		}
+		ds = append(ds, &DemuxerData{FirstPacket: firstPacket, PID: pid})
+	}
+	d := ds[len(ds)-1]
+	d.CRCMismatch = !s.CRCValid
+	if s.RawSection != nil {
+		d.RawSection = s.RawSection
+		d.RawSectionHeader = s.Header
+	}
+	return ds
+}
+
 func writePSIData(w *astikit.BitsWriter, d *PSIData) (int, error) {
 	b := astikit.NewBitsWriterBatch(w)
 	b.Write(uint8(d.PointerField))
@@ -516,10 +684,21 @@ func calcPSISectionLength(s *PSISection) uint16 {
 	}
 
 	switch s.Header.TableID {
+	case PSITableIDNITVariant1, PSITableIDNITVariant2:
+		ret += calcNITSectionLength(s.Syntax.Data.NIT)
 	case PSITableIDPAT:
 		ret += calcPATSectionLength(s.Syntax.Data.PAT)
 	case PSITableIDPMT:
 		ret += calcPMTSectionLength(s.Syntax.Data.PMT)
+	case PSITableIDSDTVariant1, PSITableIDSDTVariant2:
+		ret += calcSDTSectionLength(s.Syntax.Data.SDT)
+	case PSITableIDTDT:
+		ret += calcTDTSectionLength(s.Syntax.Data.TDT)
+	case PSITableIDTOT:
+		ret += calcTOTSectionLength(s.Syntax.Data.TOT)
+	}
+	if s.Header.TableID >= PSITableIDEITStart && s.Header.TableID <= PSITableIDEITEnd {
+		ret += calcEITSectionLength(s.Syntax.Data.EIT)
 	}
 
 	if s.Header.TableID.hasCRC32() {
@@ -530,7 +709,11 @@ func calcPSISectionLength(s *PSISection) uint16 {
 }
 
 func writePSISection(w *astikit.BitsWriter, s *PSISection) (int, error) {
-	if s.Header.TableID != PSITableIDPAT && s.Header.TableID != PSITableIDPMT {
+	switch t := s.Header.TableID; {
+	case t == PSITableIDNITVariant1, t == PSITableIDNITVariant2, t == PSITableIDPAT, t == PSITableIDPMT,
+		t == PSITableIDSDTVariant1, t == PSITableIDSDTVariant2, t == PSITableIDTDT, t == PSITableIDTOT,
+		t >= PSITableIDEITStart && t <= PSITableIDEITEnd:
+	default:
 		return 0, fmt.Errorf("writePSISection: table %s is not implemented", s.Header.TableID.Type())
 	}
 
@@ -604,10 +787,21 @@ func writePSISectionSyntaxHeader(w *astikit.BitsWriter, h *PSISectionSyntaxHeade
 func writePSISectionSyntaxData(w *astikit.BitsWriter, d *PSISectionSyntaxData, tableID PSITableID) (int, error) {
 	switch tableID {
 	// TODO write other table types
+	case PSITableIDNITVariant1, PSITableIDNITVariant2:
+		return writeNITSection(w, d.NIT)
 	case PSITableIDPAT:
 		return writePATSection(w, d.PAT)
 	case PSITableIDPMT:
 		return writePMTSection(w, d.PMT)
+	case PSITableIDSDTVariant1, PSITableIDSDTVariant2:
+		return writeSDTSection(w, d.SDT)
+	case PSITableIDTDT:
+		return writeTDTSection(w, d.TDT)
+	case PSITableIDTOT:
+		return writeTOTSection(w, d.TOT)
+	}
+	if tableID >= PSITableIDEITStart && tableID <= PSITableIDEITEnd {
+		return writeEITSection(w, d.EIT)
 	}
 
 	return 0, nil
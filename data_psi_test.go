@@ -2,6 +2,7 @@ package astits
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/asticode/go-astikit"
@@ -12,7 +13,8 @@ var psi = &PSIData{
 	PointerField: 4,
 	Sections: []*PSISection{
 		{
-			CRC32: uint32(0x7ffc6102),
+			CRC32:    uint32(0x7ffc6102),
+			CRCValid: true,
 			Header: &PSISectionHeader{
 				PrivateBit:             true,
 				SectionLength:          30,
@@ -26,7 +28,8 @@ var psi = &PSIData{
 			},
 		},
 		{
-			CRC32: uint32(0xfebaa941),
+			CRC32:    uint32(0xfebaa941),
+			CRCValid: true,
 			Header: &PSISectionHeader{
 				PrivateBit:             true,
 				SectionLength:          25,
@@ -40,7 +43,8 @@ var psi = &PSIData{
 			},
 		},
 		{
-			CRC32: uint32(0x60739f61),
+			CRC32:    uint32(0x60739f61),
+			CRCValid: true,
 			Header: &PSISectionHeader{
 				PrivateBit:             true,
 				SectionLength:          17,
@@ -54,7 +58,8 @@ var psi = &PSIData{
 			},
 		},
 		{
-			CRC32: uint32(0xc68442e8),
+			CRC32:    uint32(0xc68442e8),
+			CRCValid: true,
 			Header: &PSISectionHeader{
 				PrivateBit:             true,
 				SectionLength:          24,
@@ -68,7 +73,8 @@ var psi = &PSIData{
 			},
 		},
 		{
-			CRC32: uint32(0xef3751d6),
+			CRC32:    uint32(0xef3751d6),
+			CRCValid: true,
 			Header: &PSISectionHeader{
 				PrivateBit:             true,
 				SectionLength:          20,
@@ -82,7 +88,8 @@ var psi = &PSIData{
 			},
 		},
 		{
-			CRC32: uint32(0x6969b13),
+			CRC32:    uint32(0x6969b13),
+			CRCValid: true,
 			Header: &PSISectionHeader{
 				PrivateBit:             true,
 				SectionLength:          14,
@@ -94,7 +101,7 @@ var psi = &PSIData{
 				Data: &PSISectionSyntaxData{TOT: tot},
 			},
 		},
-		{Header: &PSISectionHeader{TableID: 254, TableType: PSITableTypeUnknown}},
+		{CRCValid: true, Header: &PSISectionHeader{TableID: 254, TableType: PSITableTypeUnknown}},
 	},
 }
 
@@ -167,15 +174,144 @@ func TestParsePSIData(t *testing.T) {
 	w.Write("000000001110") // TOT section length
 	w.Write(totBytes())     // TOT data
 	w.Write(uint32(32))     // TOT CRC32
-	_, err := parsePSIData(astikit.NewBytesIterator(buf.Bytes()))
-	assert.EqualError(t, err, "astits: parsing PSI table failed: astits: Table CRC32 20 != computed CRC32 6969b13")
+	_, err := parsePSIData(astikit.NewBytesIterator(buf.Bytes()), dataParseOptions{})
+	assert.EqualError(t, err, "astits: parsing PSI table failed: astits: Table CRC32 20 != computed CRC32 6969b13: astits: PSI CRC32 mismatch")
+	assert.True(t, errors.Is(err, ErrPSICRCMismatch))
+
+	// Corrupted section whose syntax can't be parsed
+	buf.Reset()
+	w.Write(uint8(0))       // Pointer field
+	w.Write(uint8(115))     // TOT table ID
+	w.Write("1")            // TOT syntax section indicator
+	w.Write("1")            // TOT private bit
+	w.Write("11")           // TOT reserved
+	w.Write("000000001011") // TOT section length
+	w.Write(dvbTimeBytes)   // TOT UTC time
+	w.Write(uint16(0x0fff)) // TOT descriptors length
+	w.Write(uint32(32))     // TOT CRC32
+	w.Write(uint8(0xff))    // Stuffing
+	_, err = parsePSIData(astikit.NewBytesIterator(buf.Bytes()), dataParseOptions{})
+	assert.True(t, errors.Is(err, ErrPSICRCMismatch))
+	d, err := parsePSIData(astikit.NewBytesIterator(buf.Bytes()), dataParseOptions{ignoreCRCErrors: true})
+	assert.NoError(t, err)
+	assert.False(t, d.Sections[0].CRCValid)
+	assert.Nil(t, d.Sections[0].Syntax)
+	assert.Empty(t, d.toData(&Packet{}, PIDTDT))
 
 	// Valid
-	d, err := parsePSIData(astikit.NewBytesIterator(psiBytes()))
+	d, err = parsePSIData(astikit.NewBytesIterator(psiBytes()), dataParseOptions{})
 	assert.NoError(t, err)
 	assert.Equal(t, d, psi)
 }
 
+func TestParsePSIDataLengthMismatch(t *testing.T) {
+	for _, c := range []struct {
+		extra    []byte
+		mismatch bool
+		name     string
+	}{
+		{name: "valid"},
+		{
+			extra:    []byte{0xff},
+			mismatch: true,
+			name:     "wrong section length",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+			w.Write(uint8(115))                    // TOT table ID
+			w.Write("1")                           // TOT syntax section indicator
+			w.Write("1")                           // TOT private bit
+			w.Write("11")                          // TOT reserved
+			w.WriteN(uint16(14+len(c.extra)), 12)  // TOT section length
+			w.Write(totBytes())                    // TOT data
+			w.Write(c.extra)                       // Bytes not consumed by the TOT data
+			w.Write(computeCRC32(buf.Bytes()))     // TOT CRC32
+			b := append([]byte{0}, buf.Bytes()...) // Pointer field
+
+			d, err := parsePSIData(astikit.NewBytesIterator(b), dataParseOptions{})
+			assert.NoError(t, err)
+			assert.Len(t, d.Sections, 1)
+			assert.Equal(t, c.mismatch, d.Sections[0].LengthMismatch)
+			assert.Equal(t, tot, d.Sections[0].Syntax.Data.TOT)
+		})
+	}
+}
+
+func TestParsePSIDataKeepRawSections(t *testing.T) {
+	d, err := parsePSIData(astikit.NewBytesIterator(psiBytes()), dataParseOptions{keepRawSections: true})
+	assert.NoError(t, err)
+	assert.Len(t, d.Sections, len(psi.Sections))
+	for idx, s := range d.Sections {
+		// Stuffing section
+		if shouldStopPSIParsing(s.Header.TableID, dataParseOptions{}) {
+			assert.Nil(t, s.RawSection)
+			continue
+		}
+
+		// Raw section parses to the same structure when fed again
+		assert.NotEmpty(t, s.RawSection)
+		rd, err := parsePSIData(astikit.NewBytesIterator(append([]byte{0}, s.RawSection...)), dataParseOptions{keepRawSections: true})
+		assert.NoError(t, err, "section #%d", idx)
+		assert.Equal(t, s, rd.Sections[0], "section #%d", idx)
+	}
+
+	// Raw sections are attached to data
+	ds := d.toData(&Packet{}, uint16(1))
+	for _, v := range ds {
+		assert.NotEmpty(t, v.RawSection)
+		assert.NotNil(t, v.RawSectionHeader)
+	}
+}
+
+func TestParsePSIDataKeepRawSectionsUnparsedTables(t *testing.T) {
+	// BAT section, which is not parsed
+	bat := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: bat})
+	w.Write(uint8(PSITableIDBAT))          // Table ID
+	w.Write("1")                           // Syntax section indicator
+	w.Write("1")                           // Private bit
+	w.Write("11")                          // Reserved
+	w.Write("000000001101")                // Section length
+	w.Write(psiSectionSyntaxHeaderBytes()) // Syntax section header
+	w.Write(uint16(0xf000))                // Bouquet descriptors length
+	w.Write(uint16(0xf000))                // Transport stream loop length
+	w.Write(computeCRC32(bat.Bytes()))     // CRC32
+
+	// Custom table section
+	custom := []byte{0x90, 0x70, 0x03, 'a', 'b', 'c'}
+
+	// Payload
+	buf := &bytes.Buffer{}
+	w = astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	w.Write(uint8(0))    // Pointer field
+	w.Write(bat.Bytes()) // BAT section
+	w.Write(custom)      // Custom table section
+	w.Write(uint8(0xff)) // Stuffing
+
+	// Sections are dropped unless raw sections are kept
+	d, err := parsePSIData(astikit.NewBytesIterator(buf.Bytes()), dataParseOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, d.toData(&Packet{}, PIDSDT))
+
+	// Sections are returned raw along with their header
+	d, err = parsePSIData(astikit.NewBytesIterator(buf.Bytes()), dataParseOptions{keepRawSections: true})
+	assert.NoError(t, err)
+	ds := d.toData(&Packet{}, PIDSDT)
+	assert.Len(t, ds, 2)
+	for idx, v := range [][]byte{bat.Bytes(), custom} {
+		assert.Equal(t, v, ds[idx].RawSection)
+		assert.Equal(t, PSITableID(v[0]), ds[idx].RawSectionHeader.TableID)
+		assert.False(t, ds[idx].CRCMismatch)
+
+		// Raw section parses to the same structure when fed again
+		rd, err := parsePSIData(astikit.NewBytesIterator(append([]byte{0}, v...)), dataParseOptions{keepRawSections: true})
+		assert.NoError(t, err)
+		assert.Equal(t, d.Sections[idx], rd.Sections[0])
+	}
+}
+
 var psiSectionHeader = &PSISectionHeader{
 	PrivateBit:             true,
 	SectionLength:          2730,
@@ -202,7 +338,7 @@ func TestParsePSISectionHeader(t *testing.T) {
 	w.Write(uint8(254)) // Table ID
 	w.Write("1")        // Syntax section indicator
 	w.Write("0000000")  // Finish the byte
-	d, _, _, _, _, err := parsePSISectionHeader(astikit.NewBytesIterator(buf.Bytes()))
+	d, _, _, _, _, err := parsePSISectionHeader(astikit.NewBytesIterator(buf.Bytes()), dataParseOptions{})
 	assert.Equal(t, d, &PSISectionHeader{
 		TableID:   254,
 		TableType: PSITableTypeUnknown,
@@ -210,7 +346,7 @@ func TestParsePSISectionHeader(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Valid table type
-	d, offsetStart, offsetSectionsStart, offsetSectionsEnd, offsetEnd, err := parsePSISectionHeader(astikit.NewBytesIterator(psiSectionHeaderBytes()))
+	d, offsetStart, offsetSectionsStart, offsetSectionsEnd, offsetEnd, err := parsePSISectionHeader(astikit.NewBytesIterator(psiSectionHeaderBytes()), dataParseOptions{})
 	assert.Equal(t, d, psiSectionHeader)
 	assert.Equal(t, 0, offsetStart)
 	assert.Equal(t, 3, offsetSectionsStart)
@@ -234,11 +370,14 @@ func TestPSITableType(t *testing.T) {
 	assert.Equal(t, PSITableTypePAT, PSITableIDPAT.Type())
 	assert.Equal(t, PSITableTypePMT, PSITableIDPMT.Type())
 	assert.Equal(t, PSITableTypeRST, PSITableIDRST.Type())
+	assert.Equal(t, PSITableTypeSCTE35, PSITableIDSCTE35.Type())
 	assert.Equal(t, PSITableTypeSIT, PSITableIDSIT.Type())
 	assert.Equal(t, PSITableTypeST, PSITableIDST.Type())
 	assert.Equal(t, PSITableTypeTDT, PSITableIDTDT.Type())
 	assert.Equal(t, PSITableTypeTOT, PSITableIDTOT.Type())
-	assert.Equal(t, PSITableTypeUnknown, PSITableID(1).Type())
+	assert.Equal(t, PSITableTypeCAT, PSITableIDCAT.Type())
+	assert.Equal(t, PSITableTypeTSDT, PSITableIDTSDT.Type())
+	assert.Equal(t, PSITableTypeUnknown, PSITableID(4).Type())
 }
 
 var psiSectionSyntaxHeader = &PSISectionSyntaxHeader{
@@ -379,6 +518,6 @@ func BenchmarkParsePSIData(b *testing.B) {
 	pb := psiBytes()
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
-		parsePSIData(astikit.NewBytesIterator(pb))
+		parsePSIData(astikit.NewBytesIterator(pb), dataParseOptions{})
 	}
 }
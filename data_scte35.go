@@ -0,0 +1,319 @@
+package astits
+
+import (
+	"fmt"
+
+	"github.com/asticode/go-astikit"
+)
+
+// SCTE35 splice command types
+const (
+	SCTE35SpliceCommandTypeBandwidthReservation = 0x07
+	SCTE35SpliceCommandTypePrivateCommand       = 0xff
+	SCTE35SpliceCommandTypeSpliceInsert         = 0x05
+	SCTE35SpliceCommandTypeSpliceNull           = 0x00
+	SCTE35SpliceCommandTypeSpliceSchedule       = 0x04
+	SCTE35SpliceCommandTypeTimeSignal           = 0x06
+)
+
+// SCTE35Data represents a SCTE35 splice info section data
+// Chapter: 9.2 | Link: https://www.scte.org/standards/library/catalog/scte-35-digital-program-insertion-cueing-message/
+type SCTE35Data struct {
+	CWIndex             uint8
+	EncryptedPacket     bool
+	EncryptionAlgorithm uint8
+	ProtocolVersion     uint8
+	PTSAdjustment       *ClockReference
+	SpliceCommand       []byte // Raw splice command, only set when the command type is not parsed
+	SpliceCommandType   uint8
+	SpliceDescriptors   []byte // Raw splice descriptors loop
+	SpliceInsert        *SCTE35SpliceInsert
+	Tier                uint16
+	TimeSignal          *SCTE35SpliceTime
+}
+
+// SCTE35SpliceInsert represents a SCTE35 splice insert command
+// Chapter: 9.7.3 | Link: https://www.scte.org/standards/library/catalog/scte-35-digital-program-insertion-cueing-message/
+type SCTE35SpliceInsert struct {
+	AvailNum          uint8
+	AvailsExpected    uint8
+	BreakDuration     *SCTE35BreakDuration
+	Components        []*SCTE35SpliceInsertComponent
+	HasBreakDuration  bool
+	IsOutOfNetwork    bool
+	IsProgramSplice   bool
+	IsSpliceImmediate bool
+	SpliceEventCancel bool
+	SpliceEventID     uint32
+	SpliceTime        *SCTE35SpliceTime // Only set for program splices that are not immediate
+	UniqueProgramID   uint16
+}
+
+// SCTE35SpliceInsertComponent represents a SCTE35 splice insert component
+type SCTE35SpliceInsertComponent struct {
+	ComponentTag uint8
+	SpliceTime   *SCTE35SpliceTime // Only set for splices that are not immediate
+}
+
+// SCTE35SpliceTime represents a SCTE35 splice time
+// Chapter: 10.3.1 | Link: https://www.scte.org/standards/library/catalog/scte-35-digital-program-insertion-cueing-message/
+type SCTE35SpliceTime struct {
+	PTSTime *ClockReference // Only set when a time is specified
+}
+
+// SCTE35BreakDuration represents a SCTE35 break duration
+// Chapter: 10.3.2 | Link: https://www.scte.org/standards/library/catalog/scte-35-digital-program-insertion-cueing-message/
+type SCTE35BreakDuration struct {
+	AutoReturn bool
+	Duration   *ClockReference
+}
+
+// parseSCTE35Section parses a SCTE35 splice info section
+func parseSCTE35Section(i *astikit.BytesIterator) (d *SCTE35Data, err error) {
+	// Create data
+	d = &SCTE35Data{}
+
+	// Get next byte
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Protocol version
+	d.ProtocolVersion = uint8(b)
+
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(5); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Encryption
+	d.EncryptedPacket = bs[0]&0x80 > 0
+	d.EncryptionAlgorithm = uint8(bs[0]>>1) & 0x3f
+
+	// PTS adjustment
+	d.PTSAdjustment = newClockReference(parseSCTE35Timestamp(bs), 0)
+
+	// Get next byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// CW index
+	d.CWIndex = uint8(b)
+
+	// Get next bytes
+	if bs, err = i.NextBytesNoCopy(3); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Tier
+	d.Tier = uint16(bs[0])<<4 | uint16(bs[1]>>4)
+
+	// Splice command length
+	spliceCommandLength := int(uint16(bs[1]&0xf)<<8 | uint16(bs[2]))
+
+	// Get next byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Splice command type
+	d.SpliceCommandType = uint8(b)
+
+	// The rest of the section can't be parsed if it's encrypted
+	if d.EncryptedPacket {
+		return
+	}
+
+	// Splice command
+	offsetCommandStart := i.Offset()
+	switch d.SpliceCommandType {
+	case SCTE35SpliceCommandTypeSpliceInsert:
+		if d.SpliceInsert, err = parseSCTE35SpliceInsert(i); err != nil {
+			err = fmt.Errorf("astits: parsing SCTE35 splice insert failed: %w", err)
+			return
+		}
+	case SCTE35SpliceCommandTypeTimeSignal:
+		if d.TimeSignal, err = parseSCTE35SpliceTime(i); err != nil {
+			err = fmt.Errorf("astits: parsing SCTE35 splice time failed: %w", err)
+			return
+		}
+	case SCTE35SpliceCommandTypeSpliceNull:
+	default:
+		// Splice command length may be 0xfff in older versions of the spec, in which case we can't skip the command
+		if spliceCommandLength == 0xfff {
+			return
+		}
+		if d.SpliceCommand, err = i.NextBytes(spliceCommandLength); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+	}
+
+	// Make sure we're at the end of the splice command
+	if spliceCommandLength != 0xfff {
+		i.Seek(offsetCommandStart + spliceCommandLength)
+	}
+
+	// Get next bytes
+	if bs, err = i.NextBytesNoCopy(2); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Splice descriptors
+	if l := int(uint16(bs[0])<<8 | uint16(bs[1])); l > 0 {
+		if d.SpliceDescriptors, err = i.NextBytes(l); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+	}
+	return
+}
+
+// parseSCTE35SpliceInsert parses a SCTE35 splice insert command
+func parseSCTE35SpliceInsert(i *astikit.BytesIterator) (s *SCTE35SpliceInsert, err error) {
+	// Create command
+	s = &SCTE35SpliceInsert{}
+
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(5); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Splice event
+	s.SpliceEventID = uint32(bs[0])<<24 | uint32(bs[1])<<16 | uint32(bs[2])<<8 | uint32(bs[3])
+	s.SpliceEventCancel = bs[4]&0x80 > 0
+
+	// Event has been cancelled
+	if s.SpliceEventCancel {
+		return
+	}
+
+	// Get next byte
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Flags
+	s.IsOutOfNetwork = b&0x80 > 0
+	s.IsProgramSplice = b&0x40 > 0
+	s.HasBreakDuration = b&0x20 > 0
+	s.IsSpliceImmediate = b&0x10 > 0
+
+	// Splice time
+	if s.IsProgramSplice && !s.IsSpliceImmediate {
+		if s.SpliceTime, err = parseSCTE35SpliceTime(i); err != nil {
+			err = fmt.Errorf("astits: parsing SCTE35 splice time failed: %w", err)
+			return
+		}
+	}
+
+	// Components
+	if !s.IsProgramSplice {
+		// Get next byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+
+		// Loop through components
+		for idx := 0; idx < int(b); idx++ {
+			// Get next byte
+			var t byte
+			if t, err = i.NextByte(); err != nil {
+				err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+				return
+			}
+
+			// Create component
+			c := &SCTE35SpliceInsertComponent{ComponentTag: uint8(t)}
+
+			// Splice time
+			if !s.IsSpliceImmediate {
+				if c.SpliceTime, err = parseSCTE35SpliceTime(i); err != nil {
+					err = fmt.Errorf("astits: parsing SCTE35 splice time failed: %w", err)
+					return
+				}
+			}
+
+			// Append component
+			s.Components = append(s.Components, c)
+		}
+	}
+
+	// Break duration
+	if s.HasBreakDuration {
+		// Get next bytes
+		if bs, err = i.NextBytesNoCopy(5); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Create break duration
+		s.BreakDuration = &SCTE35BreakDuration{
+			AutoReturn: bs[0]&0x80 > 0,
+			Duration:   newClockReference(parseSCTE35Timestamp(bs), 0),
+		}
+	}
+
+	// Get next bytes
+	if bs, err = i.NextBytesNoCopy(4); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Unique program id and avails
+	s.UniqueProgramID = uint16(bs[0])<<8 | uint16(bs[1])
+	s.AvailNum = uint8(bs[2])
+	s.AvailsExpected = uint8(bs[3])
+	return
+}
+
+// parseSCTE35SpliceTime parses a SCTE35 splice time
+func parseSCTE35SpliceTime(i *astikit.BytesIterator) (t *SCTE35SpliceTime, err error) {
+	// Create splice time
+	t = &SCTE35SpliceTime{}
+
+	// Get next byte
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Time is not specified
+	if b&0x80 == 0 {
+		return
+	}
+
+	// We need to rewind since the current byte is used by the PTS time as well
+	i.Skip(-1)
+
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(5); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// PTS time
+	t.PTSTime = newClockReference(parseSCTE35Timestamp(bs), 0)
+	return
+}
+
+// parseSCTE35Timestamp parses the 33 last bits of the 5 provided bytes
+func parseSCTE35Timestamp(bs []byte) int64 {
+	return int64(bs[0]&0x1)<<32 | int64(bs[1])<<24 | int64(bs[2])<<16 | int64(bs[3])<<8 | int64(bs[4])
+}
@@ -0,0 +1,109 @@
+package astits
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+var scte35 = &SCTE35Data{
+	PTSAdjustment:     newClockReference(0, 0),
+	SpliceCommandType: SCTE35SpliceCommandTypeSpliceInsert,
+	SpliceDescriptors: []byte{0x0, 0x4, 0x43, 0x55},
+	SpliceInsert: &SCTE35SpliceInsert{
+		AvailNum:       1,
+		AvailsExpected: 2,
+		BreakDuration: &SCTE35BreakDuration{
+			AutoReturn: true,
+			Duration:   newClockReference(2700000, 0),
+		},
+		HasBreakDuration: true,
+		IsOutOfNetwork:   true,
+		IsProgramSplice:  true,
+		SpliceEventID:    0x4800008f,
+		SpliceTime:       &SCTE35SpliceTime{PTSTime: newClockReference(0x1a2b3c4d5, 0)},
+		UniqueProgramID:  3,
+	},
+	Tier: 0xfff,
+}
+
+func scte35Bytes() []byte {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	w.Write(uint8(0))                     // Protocol version
+	w.Write("0")                          // Encrypted packet
+	w.Write("000000")                     // Encryption algorithm
+	w.WriteN(uint64(0), 33)               // PTS adjustment
+	w.Write(uint8(0))                     // CW index
+	w.Write("111111111111")               // Tier
+	w.Write("000000010100")               // Splice command length
+	w.Write(uint8(0x05))                  // Splice command type
+	w.Write(uint32(0x4800008f))           // Splice event id
+	w.Write("0")                          // Splice event cancel indicator
+	w.Write("1111111")                    // Reserved
+	w.Write("1")                          // Out of network indicator
+	w.Write("1")                          // Program splice flag
+	w.Write("1")                          // Duration flag
+	w.Write("0")                          // Splice immediate flag
+	w.Write("1111")                       // Reserved
+	w.Write("1")                          // Time specified flag
+	w.Write("111111")                     // Reserved
+	w.WriteN(uint64(0x1a2b3c4d5), 33)     // PTS time
+	w.Write("1")                          // Auto return
+	w.Write("111111")                     // Reserved
+	w.WriteN(uint64(2700000), 33)         // Duration
+	w.Write(uint16(3))                    // Unique program id
+	w.Write(uint8(1))                     // Avail num
+	w.Write(uint8(2))                     // Avails expected
+	w.Write(uint16(4))                    // Descriptor loop length
+	w.Write([]byte{0x0, 0x4, 0x43, 0x55}) // Descriptors
+	return buf.Bytes()
+}
+
+func TestParseSCTE35Section(t *testing.T) {
+	var b = scte35Bytes()
+	d, err := parseSCTE35Section(astikit.NewBytesIterator(b))
+	assert.NoError(t, err)
+	assert.Equal(t, scte35, d)
+}
+
+func TestParseDataSCTE35(t *testing.T) {
+	// Build section
+	section := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: section})
+	b := scte35Bytes()
+	w.Write(uint8(PSITableIDSCTE35))       // Table ID
+	w.Write("0")                           // Section syntax indicator
+	w.Write("0")                           // Private indicator
+	w.Write("11")                          // SAP type
+	w.WriteN(uint16(len(b)+4), 12)         // Section length
+	w.Write(b)                             // Data
+	w.Write(computeCRC32(section.Bytes())) // CRC32
+
+	// Build packet
+	payload := append([]byte{0}, section.Bytes()...)
+	ps := []*Packet{{
+		Header: PacketHeader{
+			HasPayload:                true,
+			PayloadUnitStartIndicator: true,
+			PID:                       0x123,
+		},
+		Payload: append(payload, bytes.Repeat([]byte{0xff}, 184-len(payload))...),
+	}}
+
+	// PID is unknown
+	pm := newProgramMap()
+	ds, err := parseData(ps, nil, pm, nil, dataParseOptions{}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, ds)
+
+	// PID is a SCTE35 elementary stream
+	pm.setStreamTypeUnlocked(0x123, StreamTypeSCTE35)
+	ds, err = parseData(ps, nil, pm, nil, dataParseOptions{}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, ds, 1)
+	assert.Equal(t, uint16(0x123), ds[0].PID)
+	assert.Equal(t, scte35, ds[0].SCTE35)
+}
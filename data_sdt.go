@@ -106,3 +106,39 @@ func parseSDTSection(i *astikit.BytesIterator, offsetSectionsEnd int, tableIDExt
 	}
 	return
 }
+
+func calcSDTSectionLength(d *SDTData) uint16 {
+	ret := uint16(3) // original network ID and reserved
+	for _, s := range d.Services {
+		ret += 5 // service ID, flags and descriptors loop length
+		ret += calcDescriptorsLength(s.Descriptors)
+	}
+	return ret
+}
+
+func writeSDTSection(w *astikit.BitsWriter, d *SDTData) (int, error) {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.OriginalNetworkID)
+	b.Write(uint8(0xff)) // reserved for future use
+	bytesWritten := 3
+
+	for _, s := range d.Services {
+		b.Write(s.ServiceID)
+		b.WriteN(uint8(0xff), 6) // reserved for future use
+		b.Write(s.HasEITSchedule)
+		b.Write(s.HasEITPresentFollowing)
+		b.WriteN(s.RunningStatus, 3)
+		b.Write(s.HasFreeCSAMode)
+		b.WriteN(calcDescriptorsLength(s.Descriptors), 12)
+		bytesWritten += 5
+
+		n, err := writeDescriptors(w, s.Descriptors)
+		if err != nil {
+			return 0, err
+		}
+		bytesWritten += n
+	}
+
+	return bytesWritten, b.Err()
+}
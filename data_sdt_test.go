@@ -42,3 +42,79 @@ func TestParseSDTSection(t *testing.T) {
 	assert.Equal(t, d, sdt)
 	assert.NoError(t, err)
 }
+
+func TestParseSDTSectionRunningStatusAndFreeCAMode(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	w.Write(uint16(2))      // Original network ID
+	w.Write(uint8(0))       // Reserved for future use
+	w.Write(uint16(3))      // Service #1 id
+	w.Write("00000000")     // Service #1 reserved for future use and EIT flags
+	w.Write("100")          // Service #1 running status
+	w.Write("1")            // Service #1 free CA mode
+	w.Write("000000000000") // Service #1 descriptors length
+	b := buf.Bytes()
+
+	d, err := parseSDTSection(astikit.NewBytesIterator(b), len(b), uint16(1))
+	assert.NoError(t, err)
+	assert.Len(t, d.Services, 1)
+	assert.Equal(t, uint8(RunningStatusRunning), d.Services[0].RunningStatus)
+	assert.True(t, d.Services[0].HasFreeCSAMode)
+}
+
+func TestWriteSDTSection(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	n, err := writeSDTSection(w, sdt)
+	assert.NoError(t, err)
+	assert.Equal(t, n, buf.Len())
+	assert.Equal(t, int(calcSDTSectionLength(sdt)), n)
+	d, err := parseSDTSection(astikit.NewBytesIterator(buf.Bytes()), n, sdt.TransportStreamID)
+	assert.NoError(t, err)
+	assert.Equal(t, sdt, d)
+}
+
+func TestWritePSIDataSDT(t *testing.T) {
+	d := &SDTData{
+		OriginalNetworkID: 2,
+		Services: []*SDTDataService{{
+			Descriptors: []*Descriptor{{
+				Length: 15,
+				Service: &DescriptorService{
+					Name:     []byte("name"),
+					Provider: []byte("provider"),
+					Type:     ServiceTypeDigitalTelevisionService,
+				},
+				Tag: DescriptorTagService,
+			}},
+			HasEITPresentFollowing: true,
+			RunningStatus:          RunningStatusRunning,
+			ServiceID:              3,
+		}},
+		TransportStreamID: 1,
+	}
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	_, err := writePSIData(w, &PSIData{Sections: []*PSISection{{
+		Header: &PSISectionHeader{
+			PrivateBit:             true,
+			SectionLength:          calcSDTSectionLength(d),
+			SectionSyntaxIndicator: true,
+			TableID:                PSITableIDSDTVariant1,
+		},
+		Syntax: &PSISectionSyntax{
+			Data: &PSISectionSyntaxData{SDT: d},
+			Header: &PSISectionSyntaxHeader{
+				CurrentNextIndicator: true,
+				TableIDExtension:     d.TransportStreamID,
+			},
+		},
+	}}})
+	assert.NoError(t, err)
+
+	pd, err := parsePSIData(astikit.NewBytesIterator(buf.Bytes()), dataParseOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, pd.Sections, 1) {
+		assert.Equal(t, d, pd.Sections[0].Syntax.Data.SDT)
+	}
+}
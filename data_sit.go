@@ -0,0 +1,72 @@
+package astits
+
+import (
+	"fmt"
+
+	"github.com/asticode/go-astikit"
+)
+
+// SITData represents a SIT data
+// Chapter: 7.2.2 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type SITData struct {
+	Services                    []*SITDataService
+	TransmissionInfoDescriptors []*Descriptor
+}
+
+// SITDataService represents a SIT data service
+type SITDataService struct {
+	RunningStatus      uint8
+	ServiceDescriptors []*Descriptor
+	ServiceID          uint16
+}
+
+// parseSITSection parses a SIT section
+func parseSITSection(i *astikit.BytesIterator, offsetSectionsEnd int) (d *SITData, err error) {
+	// Create data
+	d = &SITData{}
+
+	// Transmission info descriptors
+	if d.TransmissionInfoDescriptors, err = parseDescriptors(i); err != nil {
+		err = fmt.Errorf("astits: parsing descriptors failed: %w", err)
+		return
+	}
+
+	// Loop until end of section data is reached
+	for i.Offset() < offsetSectionsEnd {
+		// Create service
+		s := &SITDataService{}
+
+		// Get next bytes
+		var bs []byte
+		if bs, err = i.NextBytesNoCopy(2); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Service ID
+		s.ServiceID = uint16(bs[0])<<8 | uint16(bs[1])
+
+		// Get next byte
+		var b byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+
+		// Running status
+		s.RunningStatus = uint8(b>>4) & 0x7
+
+		// We need to rewind since the current byte is used by the descriptor as well
+		i.Skip(-1)
+
+		// Service descriptors
+		if s.ServiceDescriptors, err = parseDescriptors(i); err != nil {
+			err = fmt.Errorf("astits: parsing descriptors failed: %w", err)
+			return
+		}
+
+		// Append service
+		d.Services = append(d.Services, s)
+	}
+	return
+}
@@ -0,0 +1,37 @@
+package astits
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+var sit = &SITData{
+	Services: []*SITDataService{{
+		RunningStatus:      RunningStatusRunning,
+		ServiceDescriptors: descriptors,
+		ServiceID:          3,
+	}},
+	TransmissionInfoDescriptors: descriptors,
+}
+
+func sitBytes() []byte {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	w.Write("1111")     // DVB reserved for future use
+	descriptorsBytes(w) // Transmission info descriptors
+	w.Write(uint16(3))  // Service #1 id
+	w.Write("1")        // Service #1 DVB reserved for future use
+	w.Write("100")      // Service #1 running status
+	descriptorsBytes(w) // Service #1 descriptors
+	return buf.Bytes()
+}
+
+func TestParseSITSection(t *testing.T) {
+	var b = sitBytes()
+	d, err := parseSITSection(astikit.NewBytesIterator(b), len(b))
+	assert.Equal(t, d, sit)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,24 @@
+package astits
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTDTSection(t *testing.T) {
+	d, err := parseTDTSection(astikit.NewBytesIterator(dvbTimeBytes))
+	assert.Equal(t, &TDTData{UTCTime: dvbTime}, d)
+	assert.NoError(t, err)
+}
+
+func TestWriteTDTSection(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	n, err := writeTDTSection(w, &TDTData{UTCTime: dvbTime})
+	assert.NoError(t, err)
+	assert.Equal(t, n, buf.Len())
+	assert.Equal(t, dvbTimeBytes, buf.Bytes())
+}
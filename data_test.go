@@ -20,15 +20,16 @@ func TestParseData(t *testing.T) {
 		skip = true
 		return
 	}
-	ds, err := parseData(ps, c, pm)
+	ds, err := parseData(ps, c, pm, nil, dataParseOptions{}, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, cds, ds)
 
-	// Do nothing for CAT
-	ps = []*Packet{{Header: PacketHeader{PID: PIDCAT}}}
-	ds, err = parseData(ps, nil, pm)
+	// CA PIDs payloads are returned as is
+	pm.setCAPIDUnlocked(0x101)
+	ps = []*Packet{{Header: PacketHeader{PID: 0x101}, Payload: pesWithHeaderBytes()}}
+	ds, err = parseData(ps, nil, pm, nil, dataParseOptions{}, nil)
 	assert.NoError(t, err)
-	assert.Empty(t, ds)
+	assert.Equal(t, []*DemuxerData{{CA: true, FirstPacket: &Packet{Header: ps[0].Header}, PID: 0x101, Raw: pesWithHeaderBytes()}}, ds)
 
 	// PES
 	p := pesWithHeaderBytes()
@@ -42,7 +43,7 @@ func TestParseData(t *testing.T) {
 			Payload: p[33:],
 		},
 	}
-	ds, err = parseData(ps, nil, pm)
+	ds, err = parseData(ps, nil, pm, nil, dataParseOptions{}, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, []*DemuxerData{
 		{
@@ -64,7 +65,7 @@ func TestParseData(t *testing.T) {
 			Payload: p[33:],
 		},
 	}
-	ds, err = parseData(ps, nil, pm)
+	ds, err = parseData(ps, nil, pm, nil, dataParseOptions{}, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, psi.toData(
 		&Packet{Header: ps[0].Header, AdaptationField: ps[0].AdaptationField},
@@ -72,17 +73,53 @@ func TestParseData(t *testing.T) {
 	), ds)
 }
 
+func TestParseDataScrambled(t *testing.T) {
+	pm := newProgramMap()
+
+	// Transport level scrambling
+	ps := []*Packet{
+		{
+			Header:  PacketHeader{PID: uint16(257), TransportScramblingControl: ScramblingControlScrambledWithEvenKey},
+			Payload: []byte{0x0, 0x0, 0x1, 0xe0, 0x12, 0x34},
+		},
+		{
+			Header:  PacketHeader{PID: uint16(257), TransportScramblingControl: ScramblingControlScrambledWithEvenKey},
+			Payload: []byte{0x56, 0x78},
+		},
+	}
+	ds, err := parseData(ps, nil, pm, nil, dataParseOptions{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []*DemuxerData{
+		{
+			FirstPacket: &Packet{Header: ps[0].Header, AdaptationField: ps[0].AdaptationField},
+			PID:         uint16(257),
+			Raw:         []byte{0x0, 0x0, 0x1, 0xe0, 0x12, 0x34, 0x56, 0x78},
+			Scrambled:   true,
+		}}, ds)
+
+	// PES level scrambling
+	p := pesWithHeaderBytes()
+	p[6] = p[6]&0xcf | 0x20 // Scrambling control
+	ps = []*Packet{{Header: PacketHeader{PID: uint16(256)}, Payload: p}}
+	ds, err = parseData(ps, nil, pm, nil, dataParseOptions{}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, ds, 1)
+	assert.True(t, ds[0].Scrambled)
+	assert.Equal(t, uint8(ScramblingControlScrambledWithEvenKey), ds[0].PES.Header.OptionalHeader.ScramblingControl)
+	assert.Equal(t, pesWithHeader().Data, ds[0].PES.Data)
+}
+
 func TestIsPSIPayload(t *testing.T) {
 	pm := newProgramMap()
 	var pids []int
 	for i := 0; i <= 255; i++ {
-		if isPSIPayload(uint16(i), pm) {
+		if isPSIPayload(uint16(i), pm, false) {
 			pids = append(pids, i)
 		}
 	}
-	assert.Equal(t, []int{0, 16, 17, 18, 19, 20, 30, 31}, pids)
+	assert.Equal(t, []int{0, 1, 2, 16, 17, 18, 19, 20, 30, 31}, pids)
 	pm.setUnlocked(uint16(1), uint16(0))
-	assert.True(t, isPSIPayload(uint16(1), pm))
+	assert.True(t, isPSIPayload(uint16(1), pm, false))
 }
 
 func TestIsPESPayload(t *testing.T) {
@@ -33,3 +33,22 @@ func parseTOTSection(i *astikit.BytesIterator) (d *TOTData, err error) {
 	}
 	return
 }
+
+func calcTOTSectionLength(d *TOTData) uint16 {
+	return 5 + 2 + calcDescriptorsLength(d.Descriptors)
+}
+
+func writeTOTSection(w *astikit.BitsWriter, d *TOTData) (int, error) {
+	bytesWritten, err := writeDVBTime(w, d.UTCTime)
+	if err != nil {
+		return bytesWritten, err
+	}
+
+	n, err := writeDescriptorsWithLength(w, d.Descriptors)
+	if err != nil {
+		return bytesWritten, err
+	}
+	bytesWritten += n
+
+	return bytesWritten, nil
+}
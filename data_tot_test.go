@@ -27,3 +27,15 @@ func TestParseTOTSection(t *testing.T) {
 	assert.Equal(t, d, tot)
 	assert.NoError(t, err)
 }
+
+func TestWriteTOTSection(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	n, err := writeTOTSection(w, tot)
+	assert.NoError(t, err)
+	assert.Equal(t, n, buf.Len())
+	assert.Equal(t, int(calcTOTSectionLength(tot)), n)
+	d, err := parseTOTSection(astikit.NewBytesIterator(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, tot, d)
+}
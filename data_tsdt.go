@@ -0,0 +1,26 @@
+package astits
+
+import (
+	"fmt"
+
+	"github.com/asticode/go-astikit"
+)
+
+// TSDTData represents a TSDT data
+// Chapter: 2.4.4.12 | Link: https://www.itu.int/rec/T-REC-H.222.0
+type TSDTData struct {
+	Descriptors []*Descriptor
+}
+
+// parseTSDTSection parses a TSDT section
+func parseTSDTSection(i *astikit.BytesIterator, offsetSectionsEnd int) (d *TSDTData, err error) {
+	// Create data
+	d = &TSDTData{}
+
+	// Descriptors
+	if d.Descriptors, err = parseDescriptorsUntil(i, offsetSectionsEnd); err != nil {
+		err = fmt.Errorf("astits: parsing descriptors failed: %w", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,26 @@
+package astits
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTSDTSection(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	w.Write(uint8(DescriptorTagRegistration)) // Descriptor #1 tag
+	w.Write(uint8(4))                         // Descriptor #1 length
+	w.Write([]byte("HDMV"))                   // Descriptor #1 format identifier
+	b := buf.Bytes()
+
+	d, err := parseTSDTSection(astikit.NewBytesIterator(b), len(b))
+	assert.NoError(t, err)
+	assert.Equal(t, &TSDTData{Descriptors: []*Descriptor{{
+		Length:       4,
+		Registration: &DescriptorRegistration{FormatIdentifier: 0x48444d56},
+		Tag:          DescriptorTagRegistration,
+	}}}, d)
+}
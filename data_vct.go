@@ -0,0 +1,137 @@
+package astits
+
+import (
+	"fmt"
+	"unicode/utf16"
+
+	"github.com/asticode/go-astikit"
+)
+
+// VCTData represents an ATSC Terrestrial (TVCT) or Cable (CVCT) Virtual Channel Table data
+// Chapter: 6.3 | Link: https://www.atsc.org/wp-content/uploads/2021/04/A65_2013.pdf
+type VCTData struct {
+	AdditionalDescriptors []*Descriptor
+	Channels              []*VCTDataChannel
+	ProtocolVersion       uint8
+	TransportStreamID     uint16
+}
+
+// VCTDataChannel represents a VCT data channel
+type VCTDataChannel struct {
+	AccessControlled   bool
+	CarrierFrequency   uint32 // Deprecated, should be 0
+	ChannelTSID        uint16
+	Descriptors        []*Descriptor
+	ETMLocation        uint8
+	Hidden             bool
+	HideGuide          bool
+	MajorChannelNumber uint16
+	MinorChannelNumber uint16
+	ModulationMode     uint8
+	OutOfBand          bool // Only set in CVCT
+	PathSelect         bool // Only set in CVCT
+	ProgramNumber      uint16
+	ServiceType        uint8
+	ShortName          string
+	SourceID           uint16
+}
+
+// parseVCTSection parses a VCT section
+// cable indicates whether the section belongs to a CVCT
+func parseVCTSection(i *astikit.BytesIterator, tableIDExtension uint16, cable bool) (d *VCTData, err error) {
+	// Create data
+	d = &VCTData{TransportStreamID: tableIDExtension}
+
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(2); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Protocol version
+	d.ProtocolVersion = uint8(bs[0])
+
+	// Loop through channels
+	for idx := 0; idx < int(bs[1]); idx++ {
+		// Get next bytes
+		var cbs []byte
+		if cbs, err = i.NextBytesNoCopy(32); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Create channel
+		c := &VCTDataChannel{
+			AccessControlled:   cbs[26]&0x20 > 0,
+			CarrierFrequency:   uint32(cbs[18])<<24 | uint32(cbs[19])<<16 | uint32(cbs[20])<<8 | uint32(cbs[21]),
+			ChannelTSID:        uint16(cbs[22])<<8 | uint16(cbs[23]),
+			ETMLocation:        uint8(cbs[26] >> 6),
+			Hidden:             cbs[26]&0x10 > 0,
+			HideGuide:          cbs[26]&0x2 > 0,
+			MajorChannelNumber: uint16(cbs[14]&0xf)<<6 | uint16(cbs[15]>>2),
+			MinorChannelNumber: uint16(cbs[15]&0x3)<<8 | uint16(cbs[16]),
+			ModulationMode:     uint8(cbs[17]),
+			ProgramNumber:      uint16(cbs[24])<<8 | uint16(cbs[25]),
+			ServiceType:        uint8(cbs[27] & 0x3f),
+			SourceID:           uint16(cbs[28])<<8 | uint16(cbs[29]),
+		}
+
+		if cable {
+			c.OutOfBand = cbs[26]&0x4 > 0
+			c.PathSelect = cbs[26]&0x8 > 0
+		}
+
+		// Short name is 7 UTF-16 code units padded with 0s
+		var n []uint16
+		for o := 0; o < 14; o += 2 {
+			if u := uint16(cbs[o])<<8 | uint16(cbs[o+1]); u > 0 {
+				n = append(n, u)
+			}
+		}
+		c.ShortName = string(utf16.Decode(n))
+
+		// Descriptors
+		if c.Descriptors, err = parseVCTDescriptors(i, int(cbs[30]&0x3)<<8|int(cbs[31])); err != nil {
+			err = fmt.Errorf("astits: parsing descriptors failed: %w", err)
+			return
+		}
+
+		// Append channel
+		d.Channels = append(d.Channels, c)
+	}
+
+	// Get next bytes
+	if bs, err = i.NextBytesNoCopy(2); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Additional descriptors
+	if d.AdditionalDescriptors, err = parseVCTDescriptors(i, int(bs[0]&0x3)<<8|int(bs[1])); err != nil {
+		err = fmt.Errorf("astits: parsing descriptors failed: %w", err)
+		return
+	}
+	return
+}
+
+// parseVCTDescriptors parses VCT descriptors, whose length is coded on 10 bits, and interprets ATSC ones
+func parseVCTDescriptors(i *astikit.BytesIterator, length int) (o []*Descriptor, err error) {
+	// No descriptors
+	if length == 0 {
+		return
+	}
+
+	// Parse descriptors
+	if o, err = parseDescriptorsUntil(i, i.Offset()+length); err != nil {
+		err = fmt.Errorf("astits: parsing descriptors until offset failed: %w", err)
+		return
+	}
+
+	// Interpret ATSC descriptors
+	if err = parseATSCDescriptors(o); err != nil {
+		err = fmt.Errorf("astits: parsing ATSC descriptors failed: %w", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package astits
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+var vct = &VCTData{
+	Channels: []*VCTDataChannel{{
+		ChannelTSID: 0x1234,
+		Descriptors: []*Descriptor{{
+			Length:          9,
+			ServiceLocation: serviceLocation,
+			Tag:             DescriptorTagATSCServiceLocation,
+			UserDefined:     serviceLocationBytes(),
+		}},
+		MajorChannelNumber: 9,
+		MinorChannelNumber: 1,
+		ModulationMode:     4,
+		ProgramNumber:      3,
+		ServiceType:        2,
+		ShortName:          "KQED",
+		SourceID:           1,
+	}},
+	TransportStreamID: 0x1234,
+}
+
+var serviceLocation = &DescriptorServiceLocation{
+	Elements: []*DescriptorServiceLocationElement{{
+		ElementaryPID: 0x101,
+		Language:      []byte("eng"),
+		StreamType:    StreamTypeAC3Audio,
+	}},
+	PCRPID: 0x100,
+}
+
+func serviceLocationBytes() []byte {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	w.Write("111")              // Reserved
+	w.WriteN(uint16(0x100), 13) // PCR PID
+	w.Write(uint8(1))           // Number of elements
+	w.Write(uint8(0x81))        // Element #1 stream type
+	w.Write("111")              // Element #1 reserved
+	w.WriteN(uint16(0x101), 13) // Element #1 elementary PID
+	w.Write([]byte("eng"))      // Element #1 language
+	return buf.Bytes()
+}
+
+func vctBytes() []byte {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	w.Write(uint8(0))                                // Protocol version
+	w.Write(uint8(1))                                // Number of channels
+	w.Write([]byte{0, 'K', 0, 'Q', 0, 'E', 0, 'D'})  // Channel #1 short name
+	w.Write(make([]byte, 6))                         // Channel #1 short name padding
+	w.Write("1111")                                  // Channel #1 reserved
+	w.WriteN(uint16(9), 10)                          // Channel #1 major channel number
+	w.WriteN(uint16(1), 10)                          // Channel #1 minor channel number
+	w.Write(uint8(4))                                // Channel #1 modulation mode
+	w.Write(uint32(0))                               // Channel #1 carrier frequency
+	w.Write(uint16(0x1234))                          // Channel #1 channel TSID
+	w.Write(uint16(3))                               // Channel #1 program number
+	w.Write("00")                                    // Channel #1 ETM location
+	w.Write("0")                                     // Channel #1 access controlled
+	w.Write("0")                                     // Channel #1 hidden
+	w.Write("11")                                    // Channel #1 reserved
+	w.Write("0")                                     // Channel #1 hide guide
+	w.Write("111")                                   // Channel #1 reserved
+	w.WriteN(uint8(2), 6)                            // Channel #1 service type
+	w.Write(uint16(1))                               // Channel #1 source ID
+	w.Write("111111")                                // Channel #1 reserved
+	w.WriteN(uint16(11), 10)                         // Channel #1 descriptors length
+	w.Write(uint8(DescriptorTagATSCServiceLocation)) // Channel #1 descriptor #1 tag
+	w.Write(uint8(9))                                // Channel #1 descriptor #1 length
+	w.Write(serviceLocationBytes())                  // Channel #1 descriptor #1
+	w.Write("111111")                                // Reserved
+	w.WriteN(uint16(0), 10)                          // Additional descriptors length
+	return buf.Bytes()
+}
+
+func TestParseVCTSection(t *testing.T) {
+	d, err := parseVCTSection(astikit.NewBytesIterator(vctBytes()), uint16(0x1234), false)
+	assert.NoError(t, err)
+	assert.Equal(t, vct, d)
+}
+
+func TestParsePSIDataATSC(t *testing.T) {
+	b := vctBytes()
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	w.Write(uint8(PSITableIDTVCT))          // Table ID
+	w.Write("1")                            // Syntax section indicator
+	w.Write("1")                            // Private bit
+	w.Write("11")                           // Reserved
+	w.WriteN(uint16(len(b)+9), 12)          // Section length
+	w.Write(uint16(0x1234))                 // Table ID extension
+	w.Write("11")                           // Reserved
+	w.WriteN(uint8(1), 5)                   // Version number
+	w.Write("1")                            // Current/next indicator
+	w.Write(uint8(0))                       // Section number
+	w.Write(uint8(0))                       // Last section number
+	w.Write(b)                              // Section
+	w.Write(computeCRC32(buf.Bytes()))      // CRC32
+	w.Write(uint8(PSITableIDNull))          // Stuffing
+	pb := append([]byte{0}, buf.Bytes()...) // Pointer field
+
+	// ATSC tables are ignored by default
+	d, err := parsePSIData(astikit.NewBytesIterator(pb), dataParseOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, d.Sections, 1)
+	assert.Nil(t, d.Sections[0].Syntax)
+
+	// ATSC tables are parsed when enabled
+	d, err = parsePSIData(astikit.NewBytesIterator(pb), dataParseOptions{atsc: true})
+	assert.NoError(t, err)
+	assert.Len(t, d.Sections, 2)
+	assert.Equal(t, PSITableTypeTVCT, d.Sections[0].Header.TableType)
+	assert.Equal(t, vct, d.Sections[0].Syntax.Data.VCT)
+
+	ds := d.toData(nil, PIDATSCBase)
+	assert.Equal(t, []*DemuxerData{{PID: PIDATSCBase, VCT: vct}}, ds)
+}
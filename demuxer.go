@@ -1,10 +1,12 @@
 package astits
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/asticode/go-astikit"
 )
@@ -14,8 +16,15 @@ const syncByte = '\x47'
 
 // Errors
 var (
+	ErrDemuxerNotSeekable           = errors.New("astits: demuxer is not seekable")
+	ErrInvalidPacketSize            = errors.New("astits: invalid packet size")
 	ErrNoMorePackets                = errors.New("astits: no more packets")
+	ErrNoPCR                        = errors.New("astits: no PCR found")
+	ErrPESTimestampInvalid          = errors.New("astits: PES PTS or DTS is invalid")
+	ErrPESTruncated                 = errors.New("astits: PES data is truncated")
+	ErrPSICRCMismatch               = errors.New("astits: PSI CRC32 mismatch")
 	ErrPacketMustStartWithASyncByte = errors.New("astits: packet must start with a sync byte")
+	ErrTruncatedPacket              = errors.New("astits: packet is truncated")
 )
 
 // Demuxer represents a demuxer
@@ -23,20 +32,57 @@ var (
 // http://seidl.cs.vsb.cz/download/dvb/DVB_Poster.pdf
 // http://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.13.01_40/en_300468v011301o.pdf
 type Demuxer struct {
+	bitrates   *bitrateEstimator
 	ctx        context.Context
 	dataBuffer []*DemuxerData
 	l          astikit.CompleteLogger
 
-	optPacketSize    int
-	optPacketsParser PacketsParser
-	optPacketSkipper PacketSkipper
+	optATSC            bool
+	optDropTEI         bool
+	optIgnoreCRCErrors bool
+	optKeepPackets     bool
+	optKeepRawSections bool
+	optOnTableUpdate   TableUpdateCallback
+	optPacketSize      int
+	optPacketsParser   PacketsParser
+	optPacketSkipper   PacketSkipper
+	optPESParseStrict  bool
+	optReuseData       bool
+	optZeroCopyPES     bool
 
 	packetBuffer *packetBuffer
 	packetPool   *packetPool
-	programMap   *programMap
-	r            io.Reader
+	// We use map[uint32] instead map[uint16] for the PID indexed maps below as go runtime provide optimized hash
+	// functions for (u)int32/64 keys
+	pcrCallbacks  map[uint32]PCRCallback // Indexed by PID
+	pesCallbacks  map[uint32]PESCallback // Indexed by PID
+	pids          map[uint32]bool        // PIDs seen so far
+	programMap    *programMap
+	pmts          map[uint32]*PMTData // Indexed by PMT PID
+	r             io.Reader
+	rb            *bufio.Reader // Buffers rc
+	rc            *ctxReader    // Only set when the context can be cancelled
+	reuser        *dataReuser
+	tableVersions map[psiTableKey]uint8
 }
 
+// psiTableKey identifies a PSI table in the stream
+type psiTableKey struct {
+	pid              uint16
+	tableID          PSITableID
+	tableIDExtension uint16
+}
+
+// Program represents a program discovered by the demuxer
+type Program struct {
+	PMT           *PMTData // Only set once the PMT has been parsed
+	PMTPID        uint16
+	ProgramNumber uint16
+}
+
+// TableUpdateCallback represents an object capable of handling a PSI table version change
+type TableUpdateCallback func(pid uint16, tableID PSITableID, oldVer, newVer uint8)
+
 // PacketsParser represents an object capable of parsing a set of packets containing a unique payload spanning over those packets
 // Use the skip returned argument to indicate whether the default process should still be executed on the set of packets
 type PacketsParser func(ps []*Packet) (ds []*DemuxerData, skip bool, err error)
@@ -45,25 +91,128 @@ type PacketsParser func(ps []*Packet) (ds []*DemuxerData, skip bool, err error)
 // Use this option if you need to filter out unwanted packets from your pipeline. NextPacket() will return the next unskipped packet if any.
 type PacketSkipper func(p *Packet) (skip bool)
 
+// PESCallback represents an object capable of handling a PES data as soon as its last packet has been received.
+// The provided packet is the first packet of the PES data.
+type PESCallback func(d *PESData, p *Packet)
+
+// PCRSample represents a PCR or an OPCR found in the adaptation field of a packet
+type PCRSample struct {
+	ByteOffset  int64 // Offset of the packet in the stream
+	IsOPCR      bool
+	PCR         *ClockReference
+	PacketIndex int // Index of the packet in the stream
+}
+
+// PCRCallback represents an object capable of handling PCR samples as soon as their packet has been read
+type PCRCallback func(s PCRSample)
+
 // NewDemuxer creates a new transport stream based on a reader
 func NewDemuxer(ctx context.Context, r io.Reader, opts ...func(*Demuxer)) (d *Demuxer) {
 	// Init
 	d = &Demuxer{
-		ctx:        ctx,
-		l:          astikit.AdaptStdLogger(nil),
-		programMap: newProgramMap(),
-		r:          r,
+		bitrates:      newBitrateEstimator(),
+		ctx:           ctx,
+		l:             astikit.AdaptStdLogger(nil),
+		optDropTEI:    true,
+		pcrCallbacks:  make(map[uint32]PCRCallback),
+		pesCallbacks:  make(map[uint32]PESCallback),
+		pids:          make(map[uint32]bool),
+		pmts:          make(map[uint32]*PMTData),
+		programMap:    newProgramMap(),
+		r:             r,
+		tableVersions: make(map[psiTableKey]uint8),
 	}
-	d.packetPool = newPacketPool(d.programMap)
 
 	// Apply options
 	for _, opt := range opts {
 		opt(d)
 	}
 
+	// Create packet pool
+	d.packetPool = newPacketPool(d.programMap, d.optDropTEI)
+
+	// Create data reuser
+	if d.optReuseData || d.optZeroCopyPES {
+		d.reuser = &dataReuser{
+			reuse:    d.optReuseData,
+			zeroCopy: d.optZeroCopyPES,
+		}
+	}
+
 	return
 }
 
+// NewDemuxerFromReaderAt creates a new transport stream based on a random-access reader of the provided size
+// Unlike NewDemuxer, the demuxer created this way can always be repositioned with SeekToByte
+func NewDemuxerFromReaderAt(ctx context.Context, ra io.ReaderAt, size int64, opts ...func(*Demuxer)) *Demuxer {
+	return NewDemuxer(ctx, io.NewSectionReader(ra, 0, size), opts...)
+}
+
+// NewDemuxerFromReaders creates a new transport stream based on readers read one after the other, such as the
+// segments of a segmented recording
+// Segments don't need to be packet-aligned to each other: packet alignment is re-established at the beginning of each
+// of them and partial packets at their end are dropped. Empty segments are skipped and the packet size is detected
+// once, from the first non-empty segment. The demuxer created this way can't be rewinded.
+func NewDemuxerFromReaders(ctx context.Context, rs []io.Reader, opts ...func(*Demuxer)) *Demuxer {
+	return NewDemuxer(ctx, bufio.NewReader(newSegmentsReader(rs)), opts...)
+}
+
+// DemuxerOptATSC returns the option to set whether ATSC specific descriptors, whose tags belong to the DVB user
+// defined range, are interpreted. When enabled, caption service descriptors found in PMT and EIT data are parsed in
+// addition to being kept as user defined bytes, and the MGT and VCT PSIP tables carried on PID 0x1FFB are parsed.
+func DemuxerOptATSC(enabled bool) func(*Demuxer) {
+	return func(d *Demuxer) {
+		d.optATSC = enabled
+	}
+}
+
+// DemuxerOptDropTEI returns the option to set whether packets flagged with the transport error indicator
+// are dropped before being accumulated. Default is true.
+func DemuxerOptDropTEI(drop bool) func(*Demuxer) {
+	return func(d *Demuxer) {
+		d.optDropTEI = drop
+	}
+}
+
+// DemuxerOptForcePES returns the option to force the provided PIDs to be considered as carrying PES data, even
+// though no PMT references them yet or they belong to a range reserved to PSI tables. This is useful when joining a
+// stream mid-flow, since PES data of known PIDs is then returned without waiting for the PMT.
+func DemuxerOptForcePES(pids ...uint16) func(*Demuxer) {
+	return func(d *Demuxer) {
+		for _, pid := range pids {
+			d.programMap.setPESUnlocked(pid)
+		}
+	}
+}
+
+// DemuxerOptIgnoreCRCErrors returns the option to set whether PSI sections whose CRC32 doesn't match are returned,
+// with DemuxerData.CRCMismatch set, instead of failing with an error wrapping ErrPSICRCMismatch. Such sections are
+// not used to update the demuxer state, such as its program map.
+func DemuxerOptIgnoreCRCErrors(ignore bool) func(*Demuxer) {
+	return func(d *Demuxer) {
+		d.optIgnoreCRCErrors = ignore
+	}
+}
+
+// DemuxerOptKeepPackets returns the option to set whether all the packets a data has been parsed from are attached
+// to DemuxerData.Packets. Beware that packets, payloads included, are then retained for as long as the data is,
+// which roughly doubles the memory used by each data.
+func DemuxerOptKeepPackets(keep bool) func(*Demuxer) {
+	return func(d *Demuxer) {
+		d.optKeepPackets = keep
+	}
+}
+
+// DemuxerOptKeepRawSections returns the option to set whether raw PSI section bytes are kept. When enabled, they are
+// attached to DemuxerData.RawSection, and sections of tables that are not parsed, such as BATs or custom tables with
+// unknown table IDs, are returned as data containing only their raw bytes and their header, in
+// DemuxerData.RawSectionHeader, from which the table ID can be read.
+func DemuxerOptKeepRawSections(keep bool) func(*Demuxer) {
+	return func(d *Demuxer) {
+		d.optKeepRawSections = keep
+	}
+}
+
 // DemuxerOptLogger returns the option to set the logger
 func DemuxerOptLogger(l astikit.StdLogger) func(*Demuxer) {
 	return func(d *Demuxer) {
@@ -71,6 +220,15 @@ func DemuxerOptLogger(l astikit.StdLogger) func(*Demuxer) {
 	}
 }
 
+// DemuxerOptOnTableUpdate returns the option to set the callback executed when the version of a previously seen
+// PSI table changes. Tables are identified by their PID, table ID and table ID extension, so that, for instance,
+// EIT tables of different services don't interfere with each other. Only current sections are taken into account.
+func DemuxerOptOnTableUpdate(fn TableUpdateCallback) func(*Demuxer) {
+	return func(d *Demuxer) {
+		d.optOnTableUpdate = fn
+	}
+}
+
 // DemuxerOptPacketSize returns the option to set the packet size
 func DemuxerOptPacketSize(packetSize int) func(*Demuxer) {
 	return func(d *Demuxer) {
@@ -92,18 +250,152 @@ func DemuxerOptPacketSkipper(s PacketSkipper) func(*Demuxer) {
 	}
 }
 
+// DemuxerOptPESParseStrict returns the option to set whether PES data whose packet length exceeds the bytes
+// actually received is rejected with an error wrapping ErrPESTruncated. By default, such data is clamped to the
+// available bytes.
+// It also sets whether PTS and DTS whose prefix or marker bits are invalid, which usually means the PES header is
+// misaligned, are rejected with an error wrapping ErrPESTimestampInvalid. By default, they're not checked.
+func DemuxerOptPESParseStrict(strict bool) func(*Demuxer) {
+	return func(d *Demuxer) {
+		d.optPESParseStrict = strict
+	}
+}
+
+// DemuxerOptReuseData returns the option to set whether NextData reuses the same DemuxerData, PESData and PES data
+// bytes for every PES data it returns instead of allocating new ones, which reduces GC pressure when processing high
+// throughput streams.
+// When enabled, PES data returned by NextData, as well as the one provided to OnPES callbacks, is only valid until
+// the next call to NextData, which overwrites it: it must be copied if it needs to be retained. PSI data is not
+// affected.
+func DemuxerOptReuseData(reuse bool) func(*Demuxer) {
+	return func(d *Demuxer) {
+		d.optReuseData = reuse
+	}
+}
+
+// DemuxerOptZeroCopyPES returns the option to set whether PESData.Data aliases the demuxer internal buffer instead of
+// being copied out of it, which avoids a copy per PES data and meaningfully reduces allocations for large video PES
+// data. It's meant for read-only consumers.
+// When enabled, PESData.Data returned by NextData, as well as the one provided to OnPES callbacks, is only valid until
+// the next call to NextData, after which its bytes may be overwritten: it must neither be modified nor retained, and
+// must be copied if it needs to outlive the call.
+func DemuxerOptZeroCopyPES(zeroCopy bool) func(*Demuxer) {
+	return func(d *Demuxer) {
+		d.optZeroCopyPES = zeroCopy
+	}
+}
+
+// OnPES sets the callback executed each time a PES data is complete for the provided PID. Use a nil callback
+// to remove it.
+// Callbacks are executed synchronously while NextData is processing packets, as soon as the PES data is parsed and
+// before it is returned by NextData. PES data is still returned by NextData as usual.
+func (dmx *Demuxer) OnPES(pid uint16, fn PESCallback) {
+	if fn == nil {
+		delete(dmx.pesCallbacks, uint32(pid))
+		return
+	}
+	dmx.pesCallbacks[uint32(pid)] = fn
+}
+
+// OnPCR sets the callback executed for each PCR and OPCR of the provided PID. Use a nil callback to remove it.
+// Callbacks are executed synchronously while NextPacket or NextData is reading packets. Packets skipped by the
+// packet skipper are not taken into account, even though they're counted in packet indexes and byte offsets.
+func (dmx *Demuxer) OnPCR(pid uint16, fn PCRCallback) {
+	if fn == nil {
+		delete(dmx.pcrCallbacks, uint32(pid))
+		return
+	}
+	dmx.pcrCallbacks[uint32(pid)] = fn
+}
+
+// SetPESPID registers pid as carrying PES data of the provided stream type, so that its PES data is returned without
+// waiting for a PMT referencing it, as with DemuxerOptForcePES. Once a PMT referencing pid is parsed, its stream type
+// supersedes the provided one.
+func (dmx *Demuxer) SetPESPID(pid uint16, streamType StreamType) {
+	dmx.programMap.setPESUnlocked(pid)
+	dmx.programMap.setStreamTypeUnlocked(pid, streamType)
+}
+
+// StreamType returns the stream type of the provided elementary PID, as found in the last PMT referencing it or as
+// registered with SetPESPID
+func (dmx *Demuxer) StreamType(pid uint16) (t StreamType, ok bool) {
+	return dmx.programMap.streamTypeUnlocked(pid)
+}
+
 // NextPacket retrieves the next packet
+// If the context can be cancelled, a blocking read (e.g. an idle UDP socket) doesn't prevent NextPacket from returning
+// as soon as the context is cancelled. Once it has been cancelled, the demuxer shouldn't be used anymore since the
+// underlying read may still be pending.
 func (dmx *Demuxer) NextPacket() (p *Packet, err error) {
 	// Check ctx error
-	// TODO Handle ctx error another way since if the read blocks, everything blocks
-	// Maybe execute everything in a goroutine and listen the ctx channel in the same for loop
 	if err = dmx.ctx.Err(); err != nil {
 		return
 	}
 
+	// Get next packet
+	p, err = dmx.nextPacket()
+
+	// Keep track of PIDs and bitrates
+	if err == nil {
+		dmx.bitrates.add(p, dmx.packetBuffer.packetSize)
+		dmx.pids[uint32(p.Header.PID)] = true
+		dmx.onPCR(p)
+	}
+	return
+}
+
+// onPCR executes the PCR callback of the packet's PID with the PCR and the OPCR it contains, if any
+func (dmx *Demuxer) onPCR(p *Packet) {
+	// Nothing to do
+	fn, ok := dmx.pcrCallbacks[uint32(p.Header.PID)]
+	if !ok || p.AdaptationField == nil {
+		return
+	}
+
+	// Execute callback
+	idx, off := dmx.packetBuffer.lastPosition()
+	if p.AdaptationField.HasPCR {
+		fn(PCRSample{ByteOffset: off, PCR: p.AdaptationField.PCR, PacketIndex: idx})
+	}
+	if p.AdaptationField.HasOPCR {
+		fn(PCRSample{ByteOffset: off, IsOPCR: true, PCR: p.AdaptationField.OPCR, PacketIndex: idx})
+	}
+}
+
+// packetReader returns the reader packets are read from
+// When the context can be cancelled, reads go through a buffered context reader, which is reset so that it follows
+// the position of the underlying reader
+func (dmx *Demuxer) packetReader() io.Reader {
+	// Context can't be cancelled
+	if dmx.ctx.Done() == nil {
+		return dmx.r
+	}
+
+	// Create or reset reader
+	if dmx.rc == nil {
+		dmx.rc = newCtxReader(dmx.ctx, dmx.r)
+		dmx.rb = bufio.NewReader(dmx.rc)
+	} else {
+		dmx.rb.Reset(dmx.rc)
+	}
+	return dmx.rb
+}
+
+// closePacketReader stops the context reader, if any, so that the next packet reader starts from scratch
+func (dmx *Demuxer) closePacketReader() {
+	if dmx.rc == nil {
+		return
+	}
+	dmx.rc.close()
+	dmx.rc = nil
+	dmx.rb = nil
+}
+
+// nextPacket reads the next packet from the packet buffer
+func (dmx *Demuxer) nextPacket() (p *Packet, err error) {
 	// Create packet buffer if not exists
 	if dmx.packetBuffer == nil {
-		if dmx.packetBuffer, err = newPacketBuffer(dmx.r, dmx.optPacketSize, dmx.optPacketSkipper); err != nil {
+		if dmx.packetBuffer, err = newPacketBuffer(dmx.packetReader(), dmx.optPacketSize, dmx.optPacketSkipper); err != nil {
 			err = fmt.Errorf("astits: creating packet buffer failed: %w", err)
 			return
 		}
@@ -136,6 +428,8 @@ func (dmx *Demuxer) NextData() (d *DemuxerData, err error) {
 		// Get next packet
 		if p, err = dmx.NextPacket(); err != nil {
 			// If the end of the stream has been reached, we dump the packet pool
+			// This is where PES data with an unspecified length (packet length of 0), which can only be considered
+			// complete once the next payload unit starts, is delivered for the last payload unit of each PID
 			if err == ErrNoMorePackets {
 				for {
 					// Dump packet pool
@@ -145,7 +439,7 @@ func (dmx *Demuxer) NextData() (d *DemuxerData, err error) {
 
 					// Parse data
 					var errParseData error
-					if ds, errParseData = parseData(ps, dmx.optPacketsParser, dmx.programMap); errParseData != nil {
+					if ds, errParseData = parseData(ps, dmx.optPacketsParser, dmx.programMap, dmx.onPSIData, dmx.dataParseOptions(), dmx.reuser); errParseData != nil {
 						// Log error as there may be some incomplete data here
 						// We still want to try to parse all packets, in case final data is complete
 						dmx.l.Error(fmt.Errorf("astits: parsing data failed: %w", errParseData))
@@ -153,7 +447,7 @@ func (dmx *Demuxer) NextData() (d *DemuxerData, err error) {
 					}
 
 					// Update data
-					if d = dmx.updateData(ds); d != nil {
+					if d = dmx.updateData(ds, ps); d != nil {
 						err = nil
 						return
 					}
@@ -170,27 +464,116 @@ func (dmx *Demuxer) NextData() (d *DemuxerData, err error) {
 		}
 
 		// Parse data
-		if ds, err = parseData(ps, dmx.optPacketsParser, dmx.programMap); err != nil {
+		if ds, err = parseData(ps, dmx.optPacketsParser, dmx.programMap, dmx.onPSIData, dmx.dataParseOptions(), dmx.reuser); err != nil {
 			err = fmt.Errorf("astits: building new data failed: %w", err)
 			return
 		}
 
 		// Update data
-		if d = dmx.updateData(ds); d != nil {
+		if d = dmx.updateData(ds, ps); d != nil {
 			return
 		}
 	}
 }
 
-func (dmx *Demuxer) updateData(ds []*DemuxerData) (d *DemuxerData) {
+// dataParseOptions returns the options data is parsed with
+func (dmx *Demuxer) dataParseOptions() dataParseOptions {
+	return dataParseOptions{
+		atsc:            dmx.optATSC,
+		ignoreCRCErrors: dmx.optIgnoreCRCErrors,
+		keepRawSections: dmx.optKeepRawSections,
+		strictPES:       dmx.optPESParseStrict,
+	}
+}
+
+// parseATSCDescriptors interprets the ATSC descriptors of the data
+func (dmx *Demuxer) parseATSCDescriptors(d *DemuxerData) {
+	// Get descriptors
+	var dss [][]*Descriptor
+	if d.PMT != nil {
+		dss = append(dss, d.PMT.ProgramDescriptors)
+		for _, es := range d.PMT.ElementaryStreams {
+			dss = append(dss, es.ElementaryStreamDescriptors)
+		}
+	}
+	if d.EIT != nil {
+		for _, e := range d.EIT.Events {
+			dss = append(dss, e.Descriptors)
+		}
+	}
+
+	// Parse descriptors
+	for _, ds := range dss {
+		if err := parseATSCDescriptors(ds); err != nil {
+			dmx.l.Error(fmt.Errorf("astits: parsing ATSC descriptors failed: %w", err))
+		}
+	}
+}
+
+// onPSIData keeps track of PSI tables versions and executes the table update callback when they change
+func (dmx *Demuxer) onPSIData(pid uint16, d *PSIData) {
+	// Nothing to do
+	if dmx.optOnTableUpdate == nil {
+		return
+	}
+
+	// Loop through sections
+	for _, s := range d.Sections {
+		// Only current sections with a syntax header and a valid CRC32 are versioned
+		if !s.CRCValid || s.Syntax == nil || s.Syntax.Header == nil || !s.Syntax.Header.CurrentNextIndicator {
+			continue
+		}
+
+		// Update version
+		k := psiTableKey{
+			pid:              pid,
+			tableID:          s.Header.TableID,
+			tableIDExtension: s.Syntax.Header.TableIDExtension,
+		}
+		oldVer, ok := dmx.tableVersions[k]
+		dmx.tableVersions[k] = s.Syntax.Header.VersionNumber
+
+		// Version has changed
+		if ok && oldVer != s.Syntax.Header.VersionNumber {
+			dmx.optOnTableUpdate(pid, s.Header.TableID, oldVer, s.Syntax.Header.VersionNumber)
+		}
+	}
+}
+
+// updateData processes data parsed from the provided packets and returns the first one
+func (dmx *Demuxer) updateData(ds []*DemuxerData, ps []*Packet) (d *DemuxerData) {
 	// Check whether there is data to be processed
 	if len(ds) > 0 {
+		// Keep packets
+		if dmx.optKeepPackets {
+			for _, v := range ds {
+				v.Packets = ps
+			}
+		}
+
 		// Process data
 		d = ds[0]
 		dmx.dataBuffer = append(dmx.dataBuffer, ds[1:]...)
 
 		// Update program map
 		for _, v := range ds {
+			// Interpret ATSC descriptors
+			if dmx.optATSC {
+				dmx.parseATSCDescriptors(v)
+			}
+
+			// Execute PES callback
+			if v.PES != nil {
+				if fn, ok := dmx.pesCallbacks[uint32(v.PID)]; ok {
+					fn(v.PES, v.FirstPacket)
+				}
+			}
+
+			// Tables whose CRC32 doesn't match are not trusted
+			if v.CRCMismatch {
+				continue
+			}
+
 			if v.PAT != nil {
 				for _, pgm := range v.PAT.Programs {
 					// Program number 0 is reserved to NIT
@@ -199,19 +582,314 @@ func (dmx *Demuxer) updateData(ds []*DemuxerData) (d *DemuxerData) {
 					}
 				}
 			}
+			if v.PMT != nil {
+				dmx.pmts[uint32(v.PID)] = v.PMT
+				for _, es := range v.PMT.ElementaryStreams {
+					dmx.programMap.setStreamTypeUnlocked(es.ElementaryPID, es.StreamType)
+				}
+
+				// CA descriptors found in the PMT indicate ECM PIDs
+				for _, pid := range v.PMT.ECMPIDs() {
+					dmx.programMap.setCAPIDUnlocked(pid)
+				}
+			}
+
+			// CA descriptors found in the CAT indicate EMM PIDs
+			if v.CAT != nil {
+				for _, d := range v.CAT.Descriptors {
+					if d.CA != nil {
+						dmx.programMap.setCAPIDUnlocked(d.CA.CAPID)
+					}
+				}
+			}
 		}
 	}
 	return
 }
 
+// Programs returns the programs discovered so far, sorted by program number
+func (dmx *Demuxer) Programs() (ps []*Program) {
+	for pid, number := range dmx.programMap.p {
+		ps = append(ps, &Program{
+			PMT:           dmx.pmts[pid],
+			PMTPID:        uint16(pid),
+			ProgramNumber: number,
+		})
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i].ProgramNumber < ps[j].ProgramNumber })
+	return
+}
+
+// Bitrate returns the bitrate, in bits per second, of the provided PID, estimated from the bytes read between the PCRs
+// of a sliding window of the last 10 PCRs. Only PCRs of the first PID carrying them are used. Use PIDNull to get the
+// total bitrate of all PIDs but the null packets one. 0 is returned until at least 2 PCRs have been read.
+func (dmx *Demuxer) Bitrate(pid uint16) float64 {
+	return dmx.bitrates.bitrate(pid)
+}
+
+// PIDs returns every PID seen so far, sorted, including elementary PIDs whose PMT hasn't been parsed yet
+func (dmx *Demuxer) PIDs() (pids []uint16) {
+	for pid := range dmx.pids {
+		pids = append(pids, uint16(pid))
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+	return
+}
+
+// IsAllProgramsParsed checks whether programs have been discovered and all their PMTs have been parsed
+func (dmx *Demuxer) IsAllProgramsParsed() bool {
+	if len(dmx.programMap.p) == 0 {
+		return false
+	}
+	for pid := range dmx.programMap.p {
+		if _, ok := dmx.pmts[pid]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Close releases the goroutine reading packets when the context can be cancelled
+// It doesn't close the underlying reader
+func (dmx *Demuxer) Close() error {
+	dmx.closePacketReader()
+	return nil
+}
+
 // Rewind rewinds the demuxer reader
 func (dmx *Demuxer) Rewind() (n int64, err error) {
+	dmx.closePacketReader()
+	dmx.bitrates.reset()
 	dmx.dataBuffer = []*DemuxerData{}
 	dmx.packetBuffer = nil
-	dmx.packetPool = newPacketPool(dmx.programMap)
+	dmx.packetPool = newPacketPool(dmx.programMap, dmx.optDropTEI)
 	if n, err = rewind(dmx.r); err != nil {
 		err = fmt.Errorf("astits: rewinding reader failed: %w", err)
 		return
 	}
 	return
 }
+
+// SeekToByte repositions the demuxer so that the next packet is read at the packet-aligned offset
+// closest to, but not after, the provided offset
+func (dmx *Demuxer) SeekToByte(off int64) (err error) {
+	// Reader must be seekable
+	sk, ok := dmx.r.(io.Seeker)
+	if !ok {
+		err = ErrDemuxerNotSeekable
+		return
+	}
+
+	// Check offset
+	if off < 0 {
+		err = fmt.Errorf("astits: invalid offset %d", off)
+		return
+	}
+
+	// Create packet buffer if not exists so that the packet size is known
+	if dmx.packetBuffer == nil {
+		if dmx.packetBuffer, err = newPacketBuffer(dmx.packetReader(), dmx.optPacketSize, dmx.optPacketSkipper); err != nil {
+			err = fmt.Errorf("astits: creating packet buffer failed: %w", err)
+			return
+		}
+	}
+
+	// Align offset on packet boundaries, taking skipped leading bytes into account
+	if o := int64(dmx.packetBuffer.offset); off < o {
+		off = o
+	} else {
+		off -= (off - o) % int64(dmx.packetBuffer.packetSize)
+	}
+
+	// Seek
+	if _, err = sk.Seek(off, io.SeekStart); err != nil {
+		err = fmt.Errorf("astits: seeking to %d failed: %w", off, err)
+		return
+	}
+
+	// Reset buffers since previously buffered packets don't follow the new position
+	dmx.packetBuffer.r = dmx.packetReader()
+	dmx.packetBuffer.index = int((off - int64(dmx.packetBuffer.offset)) / int64(dmx.packetBuffer.packetSize))
+	dmx.bitrates.reset()
+	dmx.dataBuffer = []*DemuxerData{}
+	dmx.packetPool = newPacketPool(dmx.programMap, dmx.optDropTEI)
+	return
+}
+
+// sizedReaderAt represents a random-access reader whose size is known
+type sizedReaderAt interface {
+	io.ReaderAt
+	Size() int64
+}
+
+// seekToPTSWindow is the max number of packets read by SeekToPTS when looking for a PCR or a random access point
+// from a given packet
+var seekToPTSWindow int64 = 1 << 14
+
+// SeekToPTS repositions the demuxer on the closest random access point of the provided PID at or before the provided PTS
+// The reader is binary-searched by sampling the PCRs of the program the PID belongs to, which are monotonic unlike
+// PTSs, therefore ErrNoPCR is returned if the stream doesn't contain any. The PCR PID is taken from the PMTs parsed so
+// far and defaults to the provided PID otherwise. PCRs are only a proxy for PTSs: the PTS of the data found at a PCR is
+// usually slightly after it, depending on the encoder delay. PCRs are compared to the PTS modulo their 33 bits wrap
+// around, which requires the stream to span less than half the wrap around period (about 13 hours). Scans are bounded
+// to a window of packets: PCRs must be closer than that, and when no random access point is found within the window
+// before the target, the demuxer lands on the closest payload unit start instead. The reader must provide random
+// access, which is always the case for demuxers created with NewDemuxerFromReaderAt.
+func (dmx *Demuxer) SeekToPTS(pid uint16, pts *ClockReference) (err error) {
+	// Reader must provide random access
+	ra, ok := dmx.r.(sizedReaderAt)
+	if !ok {
+		err = ErrDemuxerNotSeekable
+		return
+	}
+
+	// Create packet buffer if not exists so that the packet size is known
+	if dmx.packetBuffer == nil {
+		if dmx.packetBuffer, err = newPacketBuffer(dmx.packetReader(), dmx.optPacketSize, dmx.optPacketSkipper); err != nil {
+			err = fmt.Errorf("astits: creating packet buffer failed: %w", err)
+			return
+		}
+	}
+	// Packets start after the leading bytes skipped by the packet buffer
+	offset, packetSize := int64(dmx.packetBuffer.offset), int64(dmx.packetBuffer.packetSize)
+	count := (ra.Size() - offset) / packetSize
+
+	// Get PCR PID
+	pcrPID := dmx.pcrPID(pid)
+
+	// Binary search the last packet whose PCR is at or before the target
+	best, foundPCR := int64(-1), false
+	lo, hi := int64(0), count
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		// Get next PCR
+		var idx int64
+		var pcr *ClockReference
+		to := hi
+		if mid+seekToPTSWindow < to {
+			to = mid + seekToPTSWindow
+		}
+		if idx, pcr, err = nextPacketPCR(ra, offset, packetSize, pcrPID, mid, to); err != nil {
+			err = fmt.Errorf("astits: fetching next PCR failed: %w", err)
+			return
+		}
+
+		// No PCR in the window
+		if pcr == nil {
+			hi = mid
+			continue
+		}
+		foundPCR = true
+
+		// Update bounds
+		if pcr.Sub(pts) > 0 {
+			hi = mid
+		} else {
+			best = idx
+			lo = idx + 1
+		}
+	}
+
+	// No PCR
+	if !foundPCR {
+		err = ErrNoPCR
+		return
+	}
+
+	// Target is before the first PCR
+	if best < 0 {
+		best = 0
+	}
+
+	// Look backwards for a payload unit start of the PID, preferably flagged as a random access point
+	target := best
+	var fallback = int64(-1)
+	for idx := best; idx >= 0 && best-idx < seekToPTSWindow; idx-- {
+		// Read packet
+		var p *Packet
+		if p, err = readPacketAt(ra, offset, packetSize, idx); err != nil {
+			err = fmt.Errorf("astits: reading packet %d failed: %w", idx, err)
+			return
+		}
+
+		// Check packet
+		if p.Header.PID != pid || !p.Header.PayloadUnitStartIndicator {
+			continue
+		}
+
+		// Random access point
+		if p.AdaptationField != nil && p.AdaptationField.RandomAccessIndicator {
+			target = idx
+			fallback = -1
+			break
+		}
+
+		// Store first payload unit start as a fallback
+		if fallback < 0 {
+			fallback = idx
+		}
+	}
+	if fallback >= 0 {
+		target = fallback
+	}
+
+	// Seek
+	if err = dmx.SeekToByte(offset + target*packetSize); err != nil {
+		err = fmt.Errorf("astits: seeking to byte failed: %w", err)
+		return
+	}
+	return
+}
+
+// pcrPID returns the PCR PID of the program the provided PID belongs to, or the PID itself if its PMT is unknown
+func (dmx *Demuxer) pcrPID(pid uint16) uint16 {
+	for _, pmt := range dmx.pmts {
+		if pmt.PCRPID == pid {
+			return pid
+		}
+		for _, es := range pmt.ElementaryStreams {
+			if es.ElementaryPID == pid {
+				return pmt.PCRPID
+			}
+		}
+	}
+	return pid
+}
+
+// nextPacketPCR returns the index and the PCR of the first packet of the PID in [from, to) that has a PCR, if any
+// Packets start at the provided offset
+func nextPacketPCR(ra io.ReaderAt, offset, packetSize int64, pid uint16, from, to int64) (idx int64, pcr *ClockReference, err error) {
+	for idx = from; idx < to; idx++ {
+		// Read packet
+		var p *Packet
+		if p, err = readPacketAt(ra, offset, packetSize, idx); err != nil {
+			err = fmt.Errorf("astits: reading packet %d failed: %w", idx, err)
+			return
+		}
+
+		// Check PCR
+		if p.Header.PID == pid && p.AdaptationField != nil && p.AdaptationField.HasPCR {
+			pcr = p.AdaptationField.PCR
+			return
+		}
+	}
+	return
+}
+
+// readPacketAt reads and parses the packet at the provided index, packets starting at the provided offset
+func readPacketAt(ra io.ReaderAt, offset, packetSize, idx int64) (p *Packet, err error) {
+	// Read
+	b := make([]byte, packetSize)
+	if _, err = ra.ReadAt(b, offset+idx*packetSize); err != nil {
+		err = fmt.Errorf("astits: reading %d bytes at %d failed: %w", packetSize, offset+idx*packetSize, err)
+		return
+	}
+
+	// Parse packet
+	if p, err = parsePacket(astikit.NewBytesIterator(b), nil); err != nil {
+		err = fmt.Errorf("astits: parsing packet failed: %w", err)
+		return
+	}
+	return
+}
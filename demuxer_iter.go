@@ -0,0 +1,31 @@
+//go:build go1.23
+
+package astits
+
+import "iter"
+
+// All returns an iterator over the demuxer data, so that it can be used with range-over-func:
+//
+//	for d, err := range dmx.All() {}
+//
+// It wraps NextData and stops once ErrNoMorePackets is returned. Other errors are yielded, and iteration stops
+// right after them.
+func (dmx *Demuxer) All() iter.Seq2[*DemuxerData, error] {
+	return func(yield func(*DemuxerData, error) bool) {
+		for {
+			// Get next data
+			d, err := dmx.NextData()
+			if err != nil {
+				if err != ErrNoMorePackets {
+					yield(nil, err)
+				}
+				return
+			}
+
+			// Yield
+			if !yield(d, nil) {
+				return
+			}
+		}
+	}
+}
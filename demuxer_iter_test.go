@@ -0,0 +1,32 @@
+//go:build go1.23
+
+package astits
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDemuxerAll(t *testing.T) {
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(patPMTBytes()), DemuxerOptPacketSize(188))
+	var ds []*DemuxerData
+	for d, err := range dmx.All() {
+		assert.NoError(t, err)
+		ds = append(ds, d)
+	}
+	assert.Len(t, ds, 2)
+	assert.NotNil(t, ds[0].PAT)
+	assert.NotNil(t, ds[1].PMT)
+
+	// Iteration can be stopped early
+	dmx = NewDemuxer(context.Background(), bytes.NewReader(patPMTBytes()), DemuxerOptPacketSize(188))
+	var count int
+	for range dmx.All() {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
@@ -1,13 +1,17 @@
 package astits
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"strings"
 	"testing"
+	"time"
 	"unicode"
 
 	"github.com/asticode/go-astikit"
@@ -71,6 +75,69 @@ func TestDemuxerNextPacket(t *testing.T) {
 	assert.EqualError(t, err, ErrNoMorePackets.Error())
 }
 
+type blockingReader struct {
+	c chan struct{}
+}
+
+func (r *blockingReader) Read(p []byte) (int, error) {
+	<-r.c
+	return 0, io.EOF
+}
+
+func TestDemuxerNextDataContextCancelled(t *testing.T) {
+	r := &blockingReader{c: make(chan struct{})}
+	defer close(r.c)
+	ctx, cancel := context.WithCancel(context.Background())
+	dmx := NewDemuxer(ctx, r, DemuxerOptPacketSize(188))
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	c := make(chan error)
+	go func() {
+		_, err := dmx.NextData()
+		c <- err
+	}()
+	select {
+	case err := <-c:
+		assert.True(t, errors.Is(err, context.Canceled))
+	case <-time.After(time.Second):
+		t.Fatal("NextData didn't return after the context has been cancelled")
+	}
+}
+
+func TestDemuxerClose(t *testing.T) {
+	// Goroutine exits on EOF
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dmx := NewDemuxer(ctx, bytes.NewReader([]byte{}), DemuxerOptPacketSize(188))
+	_, err := dmx.NextData()
+	assert.Equal(t, ErrNoMorePackets, err)
+	select {
+	case <-dmx.rc.done:
+	case <-time.After(time.Second):
+		t.Fatal("goroutine didn't exit after EOF")
+	}
+
+	// Goroutine exits on close
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	b, _ := packet(packetHeader, *packetAdaptationField, []byte("1"), true)
+	w.Write(b)
+	w.Write(b)
+	dmx = NewDemuxer(ctx, bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(192))
+	_, err = dmx.NextPacket()
+	assert.NoError(t, err)
+	done := dmx.rc.done
+	assert.NoError(t, dmx.Close())
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("goroutine didn't exit after Close")
+	}
+}
+
 func TestDemuxerNextData(t *testing.T) {
 	// Init
 	buf := &bytes.Buffer{}
@@ -127,7 +194,8 @@ func TestDemuxerNextDataUnknownDataPackets(t *testing.T) {
 	assert.EqualError(t, err, ErrNoMorePackets.Error())
 }
 
-func TestDemuxerNextDataPATPMT(t *testing.T) {
+// patPMTBytes returns a PAT packet followed by a PMT packet
+func patPMTBytes() []byte {
 	pat := hexToBytes(`474000100000b00d0001c100000001f0002ab104b2ffffffffffffffff
 	ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff
 	ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff
@@ -142,7 +210,11 @@ func TestDemuxerNextDataPATPMT(t *testing.T) {
 	ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff
 	ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff
 	ffffffffffffffffff`)
-	r := bytes.NewReader(append(pat, pmt...))
+	return append(pat, pmt...)
+}
+
+func TestDemuxerNextDataPATPMT(t *testing.T) {
+	r := bytes.NewReader(patPMTBytes())
 	dmx := NewDemuxer(context.Background(), r, DemuxerOptPacketSize(188))
 	assert.Equal(t, 188*2, r.Len())
 
@@ -151,11 +223,898 @@ func TestDemuxerNextDataPATPMT(t *testing.T) {
 	assert.Equal(t, uint16(0), d.FirstPacket.Header.PID)
 	assert.NotNil(t, d.PAT)
 	assert.Equal(t, 188, r.Len())
+	assert.Equal(t, []*Program{{PMTPID: 0x1000, ProgramNumber: 1}}, dmx.Programs())
+	assert.False(t, dmx.IsAllProgramsParsed())
 
 	d, err = dmx.NextData()
 	assert.NoError(t, err)
 	assert.Equal(t, uint16(0x1000), d.FirstPacket.Header.PID)
 	assert.NotNil(t, d.PMT)
+	assert.Equal(t, []*Program{{PMT: d.PMT, PMTPID: 0x1000, ProgramNumber: 1}}, dmx.Programs())
+	assert.True(t, dmx.IsAllProgramsParsed())
+}
+
+func TestDemuxerBitrate(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	writePCR := func(base int64) {
+		_, err := writePacket(w, &Packet{
+			AdaptationField: &PacketAdaptationField{HasPCR: true, PCR: &ClockReference{Base: base}},
+			Header:          PacketHeader{HasAdaptationField: true, PID: 0x100},
+		}, MpegTsPacketSize)
+		assert.NoError(t, err)
+	}
+	writePayloads := func(pid uint16, count int) {
+		for idx := 0; idx < count; idx++ {
+			_, err := writePacket(w, &Packet{
+				Header:  PacketHeader{HasPayload: true, PID: pid},
+				Payload: []byte{0x1},
+			}, MpegTsPacketSize)
+			assert.NoError(t, err)
+		}
+	}
+	writePCR(0)
+	writePayloads(0x101, 4)
+	writePayloads(PIDNull, 3)
+	writePayloads(0x100, 2)
+	writePCR(9000) // 100ms later
+
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(188))
+	for idx := 0; idx < 11; idx++ {
+		// No bitrate until 2 PCRs have been read
+		assert.Equal(t, float64(0), dmx.Bitrate(0x101))
+		_, err := dmx.NextPacket()
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, float64(4*188*8*10), dmx.Bitrate(0x101))
+	assert.Equal(t, float64(3*188*8*10), dmx.Bitrate(0x100))
+	assert.Equal(t, float64(0), dmx.Bitrate(0x102))
+	assert.Equal(t, float64(7*188*8*10), dmx.Bitrate(PIDNull))
+}
+
+func TestDemuxerPIDs(t *testing.T) {
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(patPMTBytes()), DemuxerOptPacketSize(188))
+	assert.Empty(t, dmx.PIDs())
+
+	_, err := dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{PIDPAT}, dmx.PIDs())
+
+	_, err = dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{PIDPAT, 0x1000}, dmx.PIDs())
+}
+
+func TestDemuxerNextDataInterleavedPMTs(t *testing.T) {
+	// Build a PAT listing two programs and, for each program, a PMT spanning several packets
+	pat := &PATData{Programs: []*PATProgram{{ProgramMapID: 0x1000, ProgramNumber: 1}, {ProgramMapID: 0x1001, ProgramNumber: 2}}}
+	pmts := map[uint16]*PMTData{}
+	for idx, pid := range []uint16{0x1000, 0x1001} {
+		pmt := &PMTData{PCRPID: 0x100, ProgramNumber: uint16(idx + 1)}
+		for esIdx := 0; esIdx < 50; esIdx++ {
+			pmt.ElementaryStreams = append(pmt.ElementaryStreams, &PMTElementaryStream{
+				ElementaryPID: 0x100 + uint16(idx*100+esIdx),
+				StreamType:    StreamTypeAACAudio,
+			})
+		}
+		pmts[pid] = pmt
+	}
+	sectionBytes := func(h *PSISectionHeader, sh *PSISectionSyntaxHeader, d *PSISectionSyntaxData) []byte {
+		buf := &bytes.Buffer{}
+		_, err := writePSIData(astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf}), &PSIData{Sections: []*PSISection{{
+			Header: h,
+			Syntax: &PSISectionSyntax{Data: d, Header: sh},
+		}}})
+		assert.NoError(t, err)
+		return buf.Bytes()
+	}
+	payloads := map[uint16][]byte{PIDPAT: sectionBytes(
+		&PSISectionHeader{SectionLength: calcPATSectionLength(pat), SectionSyntaxIndicator: true, TableID: PSITableIDPAT},
+		&PSISectionSyntaxHeader{CurrentNextIndicator: true},
+		&PSISectionSyntaxData{PAT: pat},
+	)}
+	for pid, pmt := range pmts {
+		payloads[pid] = sectionBytes(
+			&PSISectionHeader{SectionLength: calcPMTSectionLength(pmt), SectionSyntaxIndicator: true, TableID: PSITableIDPMT},
+			&PSISectionSyntaxHeader{CurrentNextIndicator: true, TableIDExtension: pmt.ProgramNumber},
+			&PSISectionSyntaxData{PMT: pmt},
+		)
+		assert.Greater(t, len(payloads[pid]), 184)
+	}
+
+	// Write PAT then interleave PMT packets packet-by-packet
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	ccs := map[uint16]uint8{}
+	writeNextPacket := func(pid uint16) {
+		p := payloads[pid]
+		l := len(p)
+		if l > 184 {
+			l = 184
+		}
+		_, err := writePacket(w, &Packet{
+			Header: PacketHeader{
+				ContinuityCounter:         ccs[pid],
+				HasPayload:                true,
+				PayloadUnitStartIndicator: ccs[pid] == 0,
+				PID:                       pid,
+			},
+			Payload: p[:l],
+		}, MpegTsPacketSize)
+		assert.NoError(t, err)
+		payloads[pid] = p[l:]
+		ccs[pid]++
+	}
+	writeNextPacket(PIDPAT)
+	for len(payloads[0x1000]) > 0 || len(payloads[0x1001]) > 0 {
+		for _, pid := range []uint16{0x1000, 0x1001} {
+			if len(payloads[pid]) > 0 {
+				writeNextPacket(pid)
+			}
+		}
+	}
+
+	// Both PMTs should be parsed completely
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(188))
+	parsed := map[uint16]*PMTData{}
+	for {
+		d, err := dmx.NextData()
+		if err == ErrNoMorePackets {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+		if d.PMT != nil {
+			parsed[d.PID] = d.PMT
+		}
+	}
+	assert.Equal(t, pmts, parsed)
+	assert.True(t, dmx.IsAllProgramsParsed())
+}
+
+func TestDemuxerNextDataLeadingGarbage(t *testing.T) {
+	// Random leading bytes that don't contain any sync byte
+	g := make([]byte, 50)
+	rand.New(rand.NewSource(1)).Read(g)
+	for idx := range g {
+		if g[idx] == syncByte {
+			g[idx] = 0
+		}
+	}
+	b := append(g, patPMTBytes()...)
+
+	for _, r := range []io.Reader{bytes.NewReader(b), bufio.NewReader(bytes.NewReader(b))} {
+		dmx := NewDemuxer(context.Background(), r)
+		d, err := dmx.NextData()
+		assert.NoError(t, err)
+		if assert.NotNil(t, d) {
+			assert.NotNil(t, d.PAT)
+		}
+		d, err = dmx.NextData()
+		assert.NoError(t, err)
+		if assert.NotNil(t, d) {
+			assert.NotNil(t, d.PMT)
+		}
+	}
+}
+
+func TestDemuxerOptATSC(t *testing.T) {
+	buf := &bytes.Buffer{}
+	m := NewMuxer(context.Background(), buf)
+	assert.NoError(t, m.AddElementaryStream(PMTElementaryStream{
+		ElementaryPID:               0x100,
+		ElementaryStreamDescriptors: []*Descriptor{{Length: 13, Tag: DescriptorTagATSCCaptionService, UserDefined: captionServiceBytes()}},
+		StreamType:                  StreamTypeH264Video,
+	}))
+	m.SetPCRPID(0x100)
+	_, err := m.WriteTables()
+	assert.NoError(t, err)
+
+	for _, atsc := range []bool{false, true} {
+		dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptATSC(atsc))
+		var pmt *PMTData
+		for pmt == nil {
+			d, err := dmx.NextData()
+			if !assert.NoError(t, err) {
+				return
+			}
+			pmt = d.PMT
+		}
+		d := pmt.ElementaryStreams[0].ElementaryStreamDescriptors[0]
+		assert.Equal(t, captionServiceBytes(), d.UserDefined)
+		if atsc {
+			assert.Equal(t, captionService, d.CaptionService)
+		} else {
+			assert.Nil(t, d.CaptionService)
+		}
+	}
+}
+
+func TestDemuxerOnTableUpdate(t *testing.T) {
+	// Write tables twice with the same version, then once more after the PMT has been updated
+	buf := &bytes.Buffer{}
+	m := NewMuxer(context.Background(), buf)
+	assert.NoError(t, m.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video}))
+	m.SetPCRPID(0x100)
+	for idx := 0; idx < 3; idx++ {
+		if idx == 2 {
+			assert.NoError(t, m.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x101, StreamType: StreamTypeAACAudio}))
+		}
+		_, err := m.WriteTables()
+		assert.NoError(t, err)
+	}
+
+	type update struct {
+		pid            uint16
+		tableID        PSITableID
+		oldVer, newVer uint8
+	}
+	var us []update
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptOnTableUpdate(func(pid uint16, tableID PSITableID, oldVer, newVer uint8) {
+		us = append(us, update{pid: pid, tableID: tableID, oldVer: oldVer, newVer: newVer})
+	}))
+	var pmts []*PMTData
+	for {
+		d, err := dmx.NextData()
+		if err == ErrNoMorePackets {
+			break
+		}
+		assert.NoError(t, err)
+		if d.PMT != nil {
+			pmts = append(pmts, d.PMT)
+		}
+	}
+	assert.Len(t, pmts, 3)
+	assert.Len(t, pmts[2].ElementaryStreams, 2)
+	assert.Equal(t, []update{{pid: pmtStartPID, tableID: PSITableIDPMT, oldVer: 0, newVer: 1}}, us)
+}
+
+func TestDemuxerIgnoreCRCErrors(t *testing.T) {
+	// Write PATs, the second one having an invalid CRC32
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	for idx := 0; idx < 3; idx++ {
+		pat := &PATData{Programs: []*PATProgram{{ProgramMapID: 0x1000 + uint16(idx), ProgramNumber: uint16(idx + 1)}}}
+		b := &bytes.Buffer{}
+		_, err := writePSIData(astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: b}), &PSIData{Sections: []*PSISection{{
+			Header: &PSISectionHeader{SectionLength: calcPATSectionLength(pat), SectionSyntaxIndicator: true, TableID: PSITableIDPAT},
+			Syntax: &PSISectionSyntax{Data: &PSISectionSyntaxData{PAT: pat}, Header: &PSISectionSyntaxHeader{CurrentNextIndicator: true}},
+		}}})
+		assert.NoError(t, err)
+		payload := b.Bytes()
+		if idx == 1 {
+			payload[len(payload)-1] ^= 0xff
+		}
+		_, err = writePacket(w, &Packet{
+			Header:  PacketHeader{ContinuityCounter: uint8(idx), HasPayload: true, PayloadUnitStartIndicator: true, PID: PIDPAT},
+			Payload: payload,
+		}, MpegTsPacketSize)
+		assert.NoError(t, err)
+	}
+
+	// CRC32 mismatch fails by default
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(188))
+	_, err := dmx.NextData()
+	assert.NoError(t, err)
+	_, err = dmx.NextData()
+	assert.True(t, errors.Is(err, ErrPSICRCMismatch))
+
+	// CRC32 mismatch is flagged
+	dmx = NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(188), DemuxerOptIgnoreCRCErrors(true))
+	var mismatches []bool
+	for {
+		d, err := dmx.NextData()
+		if err == ErrNoMorePackets {
+			break
+		}
+		assert.NoError(t, err)
+		mismatches = append(mismatches, d.CRCMismatch)
+	}
+	assert.Equal(t, []bool{false, true, false}, mismatches)
+
+	// Program map is not updated with untrusted tables
+	var pids []uint16
+	for _, p := range dmx.Programs() {
+		pids = append(pids, p.PMTPID)
+	}
+	assert.Equal(t, []uint16{0x1000, 0x1002}, pids)
+}
+
+func TestDemuxerNextDataCAT(t *testing.T) {
+	// CAT section
+	section := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: section})
+	w.Write(uint8(PSITableIDCAT))          // Table ID
+	w.Write("1")                           // Syntax section indicator
+	w.Write("0")                           // Private bit
+	w.Write("11")                          // Reserved
+	w.Write("000000001111")                // Section length
+	w.Write(psiSectionSyntaxHeaderBytes()) // Syntax section header
+	w.Write(uint8(DescriptorTagCA))        // CA descriptor tag
+	w.Write(uint8(4))                      // CA descriptor length
+	w.Write(uint16(0x0500))                // CA system ID
+	w.Write("111")                         // Reserved
+	w.Write("0000100000001")               // CA PID
+	w.Write(computeCRC32(section.Bytes())) // CRC32
+
+	// Packets
+	buf := &bytes.Buffer{}
+	w = astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	_, err := writePacket(w, &Packet{
+		Header:  PacketHeader{HasPayload: true, PayloadUnitStartIndicator: true, PID: PIDCAT},
+		Payload: append([]byte{0}, section.Bytes()...),
+	}, MpegTsPacketSize)
+	assert.NoError(t, err)
+	for cc := uint8(0); cc < 2; cc++ {
+		_, err = writePacket(w, &Packet{
+			Header:  PacketHeader{ContinuityCounter: cc, HasPayload: true, PayloadUnitStartIndicator: true, PID: 0x101},
+			Payload: pesWithHeaderBytes(),
+		}, MpegTsPacketSize)
+		assert.NoError(t, err)
+	}
+
+	// EMM PID is learnt from the CAT and its payloads are returned as is instead of being parsed as PES
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(188))
+	d, err := dmx.NextData()
+	assert.NoError(t, err)
+	assert.Equal(t, PIDCAT, d.PID)
+	assert.Equal(t, uint16(0x101), d.CAT.Descriptors[0].CA.CAPID)
+	for idx := 0; idx < 2; idx++ {
+		d, err = dmx.NextData()
+		assert.NoError(t, err)
+		assert.True(t, d.CA)
+		assert.Nil(t, d.PES)
+		assert.Equal(t, uint16(0x101), d.PID)
+		assert.Equal(t, pesWithHeaderBytes(), d.Raw[:len(pesWithHeaderBytes())])
+	}
+	_, err = dmx.NextData()
+	assert.Equal(t, ErrNoMorePackets, err)
+
+	// Without the CAT, the same packets are parsed as PES
+	dmx = NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()[188:]), DemuxerOptPacketSize(188))
+	d, err = dmx.NextData()
+	assert.NoError(t, err)
+	assert.NotNil(t, d.PES)
+}
+
+func TestDemuxerNextDataTSDT(t *testing.T) {
+	// TSDT section
+	section := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: section})
+	w.Write(uint8(PSITableIDTSDT))            // Table ID
+	w.Write("1")                              // Syntax section indicator
+	w.Write("0")                              // Private bit
+	w.Write("11")                             // Reserved
+	w.Write("000000001111")                   // Section length
+	w.Write(psiSectionSyntaxHeaderBytes())    // Syntax section header
+	w.Write(uint8(DescriptorTagRegistration)) // Registration descriptor tag
+	w.Write(uint8(4))                         // Registration descriptor length
+	w.Write([]byte("HDMV"))                   // Format identifier
+	w.Write(computeCRC32(section.Bytes()))    // CRC32
+
+	// Packets
+	buf := &bytes.Buffer{}
+	w = astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	_, err := writePacket(w, &Packet{
+		Header:  PacketHeader{HasPayload: true, PayloadUnitStartIndicator: true, PID: PIDTSDT},
+		Payload: append([]byte{0}, section.Bytes()...),
+	}, MpegTsPacketSize)
+	assert.NoError(t, err)
+
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(188))
+	d, err := dmx.NextData()
+	assert.NoError(t, err)
+	assert.Equal(t, PIDTSDT, d.PID)
+	assert.Equal(t, uint32(0x48444d56), d.TSDT.Descriptors[0].Registration.FormatIdentifier)
+}
+
+func TestDemuxerNextDataTEI(t *testing.T) {
+	// Packets
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	for _, p := range []*Packet{
+		{Header: PacketHeader{ContinuityCounter: 0, HasPayload: true, PayloadUnitStartIndicator: true, PID: 0x100}, Payload: pesWithHeaderBytes()},
+		{Header: PacketHeader{ContinuityCounter: 1, HasPayload: true, PayloadUnitStartIndicator: true, PID: 0x100, TransportErrorIndicator: true}, Payload: bytes.Repeat([]byte{0xaa}, 20)},
+		{Header: PacketHeader{ContinuityCounter: 1, HasPayload: true, PayloadUnitStartIndicator: true, PID: 0x100}, Payload: pesWithHeaderBytes()},
+	} {
+		_, err := writePacket(w, p, MpegTsPacketSize)
+		assert.NoError(t, err)
+	}
+
+	// TEI packet is dropped by default
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(188))
+	for idx := 0; idx < 2; idx++ {
+		d, err := dmx.NextData()
+		assert.NoError(t, err)
+		assert.NotNil(t, d.PES)
+	}
+	_, err := dmx.NextData()
+	assert.Equal(t, ErrNoMorePackets, err)
+
+}
+
+func TestDemuxerPESParseStrict(t *testing.T) {
+	// Single packet holding a PES whose packet length exceeds the data available at the end of the stream
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	pl := &bytes.Buffer{}
+	plw := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: pl})
+	plw.Write("000000000000000000000001")   // Prefix
+	plw.Write(uint8(StreamIDPaddingStream)) // Stream ID
+	plw.Write(uint16(300))                  // Packet length
+	plw.Write(bytes.Repeat([]byte{0x1}, 178))
+	_, err := writePacket(w, &Packet{
+		Header:  PacketHeader{HasPayload: true, PayloadUnitStartIndicator: true, PID: 0x100},
+		Payload: pl.Bytes(),
+	}, MpegTsPacketSize)
+	assert.NoError(t, err)
+
+	// Default mode clamps data
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(188))
+	d, err := dmx.NextData()
+	assert.NoError(t, err)
+	if assert.NotNil(t, d) && assert.NotNil(t, d.PES) {
+		assert.Equal(t, uint16(300), d.PES.Header.PacketLength)
+		assert.Len(t, d.PES.Data, 178)
+	}
+
+	// Strict mode drops truncated data
+	dmx = NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(188), DemuxerOptPESParseStrict(true))
+	_, err = dmx.NextData()
+	assert.Equal(t, ErrNoMorePackets, err)
+}
+
+func TestDemuxerNextDataUnboundedPESAtEOF(t *testing.T) {
+	// Two video PES with a packet length of 0, the second one only ending with the stream
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	var cc uint8
+	for idx := 0; idx < 2; idx++ {
+		pl := &bytes.Buffer{}
+		plw := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: pl})
+		plw.Write("000000000000000000000001") // Prefix
+		plw.Write(uint8(0xe0))                // Stream ID
+		plw.Write(uint16(0))                  // Packet length
+		plw.Write("10000000")                 // Optional header flags
+		plw.Write("00000000")                 // Optional header flags
+		plw.Write(uint8(0))                   // Header length
+		plw.Write(bytes.Repeat([]byte{uint8(idx + 1)}, 2*MpegTsPacketSize-4*2-9))
+		bs := pl.Bytes()
+		for o := 0; o < len(bs); o += MpegTsPacketSize - 4 {
+			_, err := writePacket(w, &Packet{
+				Header:  PacketHeader{ContinuityCounter: cc, HasPayload: true, PayloadUnitStartIndicator: o == 0, PID: 0x100},
+				Payload: bs[o : o+MpegTsPacketSize-4],
+			}, MpegTsPacketSize)
+			assert.NoError(t, err)
+			cc++
+		}
+	}
+
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(188))
+	for idx := 0; idx < 2; idx++ {
+		d, err := dmx.NextData()
+		assert.NoError(t, err)
+		if assert.NotNil(t, d) && assert.NotNil(t, d.PES) {
+			assert.Equal(t, bytes.Repeat([]byte{uint8(idx + 1)}, 2*MpegTsPacketSize-4*2-9), d.PES.Data)
+		}
+	}
+	_, err := dmx.NextData()
+	assert.Equal(t, ErrNoMorePackets, err)
+}
+
+func TestDemuxerOnPES(t *testing.T) {
+	// Packets
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	for cc := uint8(0); cc < 3; cc++ {
+		for _, pid := range []uint16{0x100, 0x101} {
+			_, err := writePacket(w, &Packet{
+				Header:  PacketHeader{ContinuityCounter: cc, HasPayload: true, PayloadUnitStartIndicator: true, PID: pid},
+				Payload: pesWithHeaderBytes(),
+			}, MpegTsPacketSize)
+			assert.NoError(t, err)
+		}
+	}
+
+	// Callback is executed once per complete PES of the PID, before NextData returns it
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(188))
+	var ccs []uint8
+	dmx.OnPES(0x100, func(d *PESData, p *Packet) {
+		assert.Equal(t, uint16(0x100), p.Header.PID)
+		assert.NotNil(t, d)
+		ccs = append(ccs, p.Header.ContinuityCounter)
+	})
+	var count int
+	for {
+		d, err := dmx.NextData()
+		if err == ErrNoMorePackets {
+			break
+		}
+		assert.NoError(t, err)
+		if d.PID == 0x100 {
+			count++
+			assert.Len(t, ccs, count)
+		}
+	}
+	assert.Equal(t, []uint8{0, 1, 2}, ccs)
+
+	// Callback can be removed
+	dmx = NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(188))
+	dmx.OnPES(0x100, func(d *PESData, p *Packet) { t.Error("callback should have been removed") })
+	dmx.OnPES(0x100, nil)
+	for {
+		if _, err := dmx.NextData(); err != nil {
+			break
+		}
+	}
+}
+
+// demuxerPESBytes returns a stream containing PES data of the provided lengths on PID 0x100
+func demuxerPESBytes(t testing.TB, lengths ...int) (b []byte, datas [][]byte) {
+	buf := &bytes.Buffer{}
+	m := NewMuxer(context.Background(), buf)
+	if err := m.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video}); err != nil {
+		t.Fatal(err)
+	}
+	m.SetPCRPID(0x100)
+	for idx, l := range lengths {
+		data := bytes.Repeat([]byte{byte(idx + 1)}, l)
+		if _, err := m.WriteElementaryStreamData(0x100, data, nil, nil, false); err != nil {
+			t.Fatal(err)
+		}
+		datas = append(datas, data)
+	}
+	return buf.Bytes(), datas
+}
+
+func TestDemuxerReuseData(t *testing.T) {
+	b, datas := demuxerPESBytes(t, 300, 100, 500)
+
+	var reused *DemuxerData
+	var got [][]byte
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(b), DemuxerOptReuseData(true))
+	for {
+		d, err := dmx.NextData()
+		if errors.Is(err, ErrNoMorePackets) {
+			break
+		}
+		assert.NoError(t, err)
+		if d.PES == nil {
+			continue
+		}
+
+		// Data is reused
+		if reused == nil {
+			reused = d
+		}
+		assert.Same(t, reused, d)
+		assert.Equal(t, uint16(0x100), d.PID)
+		assert.Nil(t, d.PMT)
+
+		// Data is only valid until the next call
+		got = append(got, append([]byte(nil), d.PES.Data...))
+	}
+	assert.Equal(t, datas, got)
+}
+
+func TestDemuxerZeroCopyPES(t *testing.T) {
+	b, datas := demuxerPESBytes(t, 300, 100, 500)
+
+	var got [][]byte
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(b), DemuxerOptZeroCopyPES(true))
+	for {
+		d, err := dmx.NextData()
+		if errors.Is(err, ErrNoMorePackets) {
+			break
+		}
+		assert.NoError(t, err)
+		if d.PES == nil {
+			continue
+		}
+
+		// Data is only valid until the next call
+		got = append(got, append([]byte(nil), d.PES.Data...))
+	}
+	assert.Equal(t, datas, got)
+}
+
+func TestDemuxerForcePES(t *testing.T) {
+	// Write PES data on a PID belonging to the DVB range, without tables
+	buf := &bytes.Buffer{}
+	m := NewMuxer(context.Background(), buf)
+	assert.NoError(t, m.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x12, StreamType: StreamTypeH264Video}))
+	m.SetPCRPID(0x12)
+	var datas [][]byte
+	for idx, l := range []int{300, 100} {
+		data := bytes.Repeat([]byte{byte(idx + 1)}, l)
+		_, err := m.WriteElementaryStreamData(0x12, data, nil, nil, false)
+		assert.NoError(t, err)
+		datas = append(datas, data)
+	}
+	var b []byte
+	for bs := buf.Bytes(); len(bs) > 0; bs = bs[MpegTsPacketSize:] {
+		if pid := uint16(bs[1]&0x1f)<<8 | uint16(bs[2]); pid == 0x12 {
+			b = append(b, bs[:MpegTsPacketSize]...)
+		}
+	}
+
+	for _, c := range []struct {
+		datas [][]byte
+		name  string
+		opts  []func(*Demuxer)
+	}{
+		{name: "not forced"},
+		{
+			datas: datas,
+			name:  "forced",
+			opts:  []func(*Demuxer){DemuxerOptForcePES(0x12)},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			var got [][]byte
+			dmx := NewDemuxer(context.Background(), bytes.NewReader(b), c.opts...)
+			for {
+				d, err := dmx.NextData()
+				if errors.Is(err, ErrNoMorePackets) {
+					break
+				} else if err != nil {
+					continue
+				}
+				if d.PES != nil {
+					got = append(got, d.PES.Data)
+				}
+			}
+			assert.Equal(t, c.datas, got)
+		})
+	}
+}
+
+func TestDemuxerSetPESPID(t *testing.T) {
+	// Write PES data followed by tables and PES data again
+	buf := &bytes.Buffer{}
+	m := NewMuxer(context.Background(), buf)
+	assert.NoError(t, m.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video}))
+	m.SetPCRPID(0x100)
+	_, err := m.WriteElementaryStreamData(0x100, bytes.Repeat([]byte{1}, 300), nil, nil, false)
+	assert.NoError(t, err)
+	var b []byte
+	for bs := buf.Bytes(); len(bs) > 0; bs = bs[MpegTsPacketSize:] {
+		if pid := uint16(bs[1]&0x1f)<<8 | uint16(bs[2]); pid == 0x100 {
+			b = append(b, bs[:MpegTsPacketSize]...)
+		}
+	}
+	buf.Reset()
+	_, err = m.WriteTables()
+	assert.NoError(t, err)
+	_, err = m.WriteElementaryStreamData(0x100, bytes.Repeat([]byte{2}, 100), nil, nil, false)
+	assert.NoError(t, err)
+	b = append(b, buf.Bytes()...)
+
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(b))
+	dmx.SetPESPID(0x100, StreamTypeH265Video)
+	st, ok := dmx.StreamType(0x100)
+	assert.True(t, ok)
+	assert.Equal(t, StreamTypeH265Video, st)
+	var pmts []*PMTData
+	var got [][]byte
+	for {
+		d, err := dmx.NextData()
+		if errors.Is(err, ErrNoMorePackets) {
+			break
+		}
+		assert.NoError(t, err)
+		if d.PMT != nil {
+			pmts = append(pmts, d.PMT)
+		}
+		if d.PES != nil {
+			got = append(got, d.PES.Data)
+		}
+	}
+	assert.Len(t, pmts, 1)
+	assert.Equal(t, [][]byte{bytes.Repeat([]byte{1}, 300), bytes.Repeat([]byte{2}, 100)}, got)
+
+	// PMT stream type supersedes the registered one
+	st, ok = dmx.StreamType(0x100)
+	assert.True(t, ok)
+	assert.Equal(t, StreamTypeH264Video, st)
+}
+
+func TestNewDemuxerFromReaders(t *testing.T) {
+	// Write segments
+	buf := &bytes.Buffer{}
+	m := NewMuxer(context.Background(), buf)
+	assert.NoError(t, m.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video}))
+	m.SetPCRPID(0x100)
+	var datas [][]byte
+	var segments [][]byte
+	for idx, l := range []int{300, 100, 500, 200} {
+		data := bytes.Repeat([]byte{byte(idx + 1)}, l)
+		_, err := m.WriteElementaryStreamData(0x100, data, nil, nil, false)
+		assert.NoError(t, err)
+		datas = append(datas, data)
+		if idx%2 == 1 {
+			segments = append(segments, append([]byte(nil), buf.Bytes()...))
+			buf.Reset()
+		}
+	}
+
+	// Segments are not packet-aligned to each other: first segment ends with the beginning of a null packet and
+	// second segment starts with the end of it
+	null := append([]byte{syncByte, 0x1f, 0xff, 0x10}, bytes.Repeat([]byte{0xff}, MpegTsPacketSize-4)...)
+	segments[0] = append(segments[0], null[:100]...)
+	segments[1] = append(null[100:], segments[1]...)
+
+	for _, c := range []struct {
+		name     string
+		segments [][]byte
+	}{
+		{
+			name:     "misaligned",
+			segments: segments,
+		},
+		{
+			name:     "empty segments",
+			segments: [][]byte{{}, segments[0], {}, segments[1], {}},
+		},
+		{
+			name:     "single packet segments",
+			segments: [][]byte{null, segments[0], null, null[50:], segments[1]},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			var rs []io.Reader
+			for _, s := range c.segments {
+				rs = append(rs, bytes.NewReader(s))
+			}
+
+			var got [][]byte
+			dmx := NewDemuxerFromReaders(context.Background(), rs)
+			for {
+				d, err := dmx.NextData()
+				if errors.Is(err, ErrNoMorePackets) {
+					break
+				}
+				assert.NoError(t, err)
+				if d.PES != nil {
+					got = append(got, d.PES.Data)
+				}
+			}
+			assert.Equal(t, datas, got)
+		})
+	}
+}
+
+func TestDemuxerOnPCR(t *testing.T) {
+	// Write packets
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	for idx, af := range []*PacketAdaptationField{
+		{HasPCR: true, PCR: &ClockReference{Base: 1}},
+		nil,
+		{HasOPCR: true, HasPCR: true, OPCR: &ClockReference{Base: 2}, PCR: &ClockReference{Base: 3}},
+		{HasPCR: true, PCR: &ClockReference{Base: 4}},
+	} {
+		p := &Packet{
+			AdaptationField: af,
+			Header:          PacketHeader{ContinuityCounter: uint8(idx), HasAdaptationField: af != nil, HasPayload: true, PID: 0x100},
+			Payload:         []byte{0},
+		}
+		if idx == 3 {
+			p.Header.PID = 0x101
+		}
+		_, err := writePacket(w, p, MpegTsPacketSize)
+		assert.NoError(t, err)
+	}
+
+	// Collect samples
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptPacketSize(MpegTsPacketSize))
+	var ss []PCRSample
+	dmx.OnPCR(0x100, func(s PCRSample) { ss = append(ss, s) })
+	for {
+		if _, err := dmx.NextPacket(); err == ErrNoMorePackets {
+			break
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+	assert.Equal(t, []PCRSample{
+		{PCR: &ClockReference{Base: 1}},
+		{ByteOffset: 2 * MpegTsPacketSize, PCR: &ClockReference{Base: 3}, PacketIndex: 2},
+		{ByteOffset: 2 * MpegTsPacketSize, IsOPCR: true, PCR: &ClockReference{Base: 2}, PacketIndex: 2},
+	}, ss)
+
+	// Positions follow seeks
+	ss = nil
+	assert.NoError(t, dmx.SeekToByte(2*MpegTsPacketSize))
+	_, err := dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Len(t, ss, 2)
+	assert.Equal(t, 2, ss[0].PacketIndex)
+
+	// Callback can be removed
+	ss = nil
+	dmx.OnPCR(0x100, nil)
+	_, err = dmx.Rewind()
+	assert.NoError(t, err)
+	_, err = dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Empty(t, ss)
+
+	// Packets discarded while detecting the packet size of a reader that can neither be peeked nor seeked are taken
+	// into account
+	buf.Reset()
+	for idx := 0; idx < 5; idx++ {
+		_, err = writePacket(w, &Packet{
+			AdaptationField: &PacketAdaptationField{HasPCR: true, PCR: &ClockReference{Base: int64(idx)}},
+			Header:          PacketHeader{ContinuityCounter: uint8(idx), HasAdaptationField: true, HasPayload: true, PID: 0x100},
+			Payload:         []byte{0},
+		}, MpegTsPacketSize)
+		assert.NoError(t, err)
+	}
+	dmx = NewDemuxer(context.Background(), struct{ io.Reader }{bytes.NewReader(buf.Bytes())})
+	ss = nil
+	dmx.OnPCR(0x100, func(s PCRSample) { ss = append(ss, s) })
+	for {
+		if _, err := dmx.NextPacket(); err == ErrNoMorePackets {
+			break
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+	assert.Equal(t, []PCRSample{
+		{ByteOffset: 3 * MpegTsPacketSize, PCR: &ClockReference{Base: 3}, PacketIndex: 3},
+		{ByteOffset: 4 * MpegTsPacketSize, PCR: &ClockReference{Base: 4}, PacketIndex: 4},
+	}, ss)
+}
+
+func TestDemuxerKeepPackets(t *testing.T) {
+	// Mux a PES spanning over several packets
+	buf := &bytes.Buffer{}
+	m := NewMuxer(context.Background(), buf)
+	assert.NoError(t, m.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video}))
+	m.SetPCRPID(0x100)
+	_, err := m.WriteElementaryStreamData(0x100, bytes.Repeat([]byte{0x1}, 500), &ClockReference{Base: 90000}, nil, true)
+	assert.NoError(t, err)
+
+	// Count packets of the PES
+	var ccs []uint8
+	for idx := 0; idx < buf.Len(); idx += MpegTsPacketSize {
+		p, err := ParsePacketAt(buf.Bytes(), idx)
+		assert.NoError(t, err)
+		if p.Header.PID == 0x100 {
+			ccs = append(ccs, p.Header.ContinuityCounter)
+		}
+	}
+	assert.Len(t, ccs, 3)
+
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptKeepPackets(true))
+	var count int
+	for {
+		d, err := dmx.NextData()
+		if err == ErrNoMorePackets {
+			break
+		}
+		assert.NoError(t, err)
+		if d.PES == nil {
+			continue
+		}
+		count++
+		var dccs []uint8
+		for _, p := range d.Packets {
+			assert.Equal(t, uint16(0x100), p.Header.PID)
+			dccs = append(dccs, p.Header.ContinuityCounter)
+		}
+		assert.Equal(t, ccs, dccs)
+		assert.True(t, d.Packets[0].AdaptationField.RandomAccessIndicator)
+	}
+	assert.Equal(t, 1, count)
+
+	// Packets are not kept by default
+	dmx = NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()))
+	d, err := dmx.NextData()
+	assert.NoError(t, err)
+	assert.Nil(t, d.Packets)
 }
 
 func TestDemuxerRewind(t *testing.T) {
@@ -175,6 +1134,206 @@ func TestDemuxerRewind(t *testing.T) {
 	assert.Nil(t, dmx.packetBuffer)
 }
 
+func TestDemuxerSeekToByte(t *testing.T) {
+	// Not seekable
+	dmx := NewDemuxer(context.Background(), &bytes.Buffer{})
+	assert.Equal(t, ErrDemuxerNotSeekable, dmx.SeekToByte(0))
+
+	// Valid
+	buf := &bytes.Buffer{}
+	b1, _ := packet(packetHeader, *packetAdaptationField, []byte("1"), true)
+	buf.Write(b1)
+	b2, p2 := packet(packetHeader, *packetAdaptationField, []byte("2"), true)
+	buf.Write(b2)
+	b3, p3 := packet(packetHeader, *packetAdaptationField, []byte("3"), true)
+	buf.Write(b3)
+	dmx = NewDemuxerFromReaderAt(context.Background(), bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	// Seek to second packet
+	err := dmx.SeekToByte(int64(len(b1)))
+	assert.NoError(t, err)
+	p, err := dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, p2, p)
+
+	// Offset is aligned on packet boundaries
+	err = dmx.SeekToByte(int64(len(b1) + len(b2) + 10))
+	assert.NoError(t, err)
+	p, err = dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, p3, p)
+
+	// Invalid offset
+	assert.Error(t, dmx.SeekToByte(-1))
+}
+
+func TestDemuxerSeekToPTS(t *testing.T) {
+	// Not seekable
+	dmx := NewDemuxer(context.Background(), &bytes.Buffer{})
+	assert.Equal(t, ErrDemuxerNotSeekable, dmx.SeekToPTS(0x100, newClockReference(0, 0)))
+
+	// No PCR
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	for idx := 0; idx < 4; idx++ {
+		_, err := writePacket(w, &Packet{
+			Header: PacketHeader{
+				HasPayload:                true,
+				PayloadUnitStartIndicator: true,
+				PID:                       0x100,
+			},
+			Payload: []byte("payload"),
+		}, MpegTsPacketSize)
+		assert.NoError(t, err)
+	}
+	dmx = NewDemuxerFromReaderAt(context.Background(), bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Equal(t, ErrNoPCR, dmx.SeekToPTS(0x100, newClockReference(0, 0)))
+
+	// Increasing PCRs with a random access point every 10 packets
+	buf.Reset()
+	for idx := 0; idx < 40; idx++ {
+		_, err := writePacket(w, &Packet{
+			AdaptationField: &PacketAdaptationField{
+				HasPCR:                true,
+				PCR:                   newClockReference(int64(idx*1000), 0),
+				RandomAccessIndicator: idx%10 == 0,
+			},
+			Header: PacketHeader{
+				HasAdaptationField:        true,
+				HasPayload:                true,
+				PayloadUnitStartIndicator: true,
+				PID:                       0x100,
+			},
+			Payload: []byte("payload"),
+		}, MpegTsPacketSize)
+		assert.NoError(t, err)
+	}
+	dmx = NewDemuxerFromReaderAt(context.Background(), bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	// Lands on the random access point before the target
+	err := dmx.SeekToPTS(0x100, newClockReference(25500, 0))
+	assert.NoError(t, err)
+	p, err := dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20000), p.AdaptationField.PCR.Base)
+
+	// Exact match on a random access point
+	err = dmx.SeekToPTS(0x100, newClockReference(30000, 0))
+	assert.NoError(t, err)
+	p, err = dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(30000), p.AdaptationField.PCR.Base)
+
+	// Target before the first PCR
+	err = dmx.SeekToPTS(0x100, newClockReference(0, 0))
+	assert.NoError(t, err)
+	p, err = dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), p.AdaptationField.PCR.Base)
+
+	// Leading junk bytes
+	b := append(bytes.Repeat([]byte{0xff}, 50), buf.Bytes()...)
+	dmx = NewDemuxerFromReaderAt(context.Background(), bytes.NewReader(b), int64(len(b)))
+	err = dmx.SeekToPTS(0x100, newClockReference(25500, 0))
+	assert.NoError(t, err)
+	p, err = dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20000), p.AdaptationField.PCR.Base)
+
+	// PCRs wrapping around
+	buf.Reset()
+	for idx := 0; idx < 40; idx++ {
+		_, err := writePacket(w, &Packet{
+			AdaptationField: &PacketAdaptationField{
+				HasPCR:                true,
+				PCR:                   newClockReference((clockReferenceBaseModulo-20000+int64(idx*1000))%clockReferenceBaseModulo, 0),
+				RandomAccessIndicator: idx%10 == 0,
+			},
+			Header: PacketHeader{
+				HasAdaptationField:        true,
+				HasPayload:                true,
+				PayloadUnitStartIndicator: true,
+				PID:                       0x100,
+			},
+			Payload: []byte("payload"),
+		}, MpegTsPacketSize)
+		assert.NoError(t, err)
+	}
+	dmx = NewDemuxerFromReaderAt(context.Background(), bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	err = dmx.SeekToPTS(0x100, newClockReference(5500, 0))
+	assert.NoError(t, err)
+	p, err = dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), p.AdaptationField.PCR.Base)
+	err = dmx.SeekToPTS(0x100, newClockReference(clockReferenceBaseModulo-5500, 0))
+	assert.NoError(t, err)
+	p, err = dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, clockReferenceBaseModulo-20000+int64(10000), p.AdaptationField.PCR.Base)
+
+	// PCRs of other programs are ignored
+	buf.Reset()
+	for idx := 0; idx < 40; idx++ {
+		pid, pcr := uint16(0x100), newClockReference(int64(idx*1000), 0)
+		if idx%2 == 1 {
+			pid, pcr = 0x200, newClockReference(1e9, 0)
+		}
+		_, err := writePacket(w, &Packet{
+			AdaptationField: &PacketAdaptationField{
+				HasPCR:                true,
+				PCR:                   pcr,
+				RandomAccessIndicator: true,
+			},
+			Header: PacketHeader{
+				HasAdaptationField:        true,
+				HasPayload:                true,
+				PayloadUnitStartIndicator: true,
+				PID:                       pid,
+			},
+			Payload: []byte("payload"),
+		}, MpegTsPacketSize)
+		assert.NoError(t, err)
+	}
+	dmx = NewDemuxerFromReaderAt(context.Background(), bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	dmx.pmts[0x1000] = &PMTData{
+		ElementaryStreams: []*PMTElementaryStream{{ElementaryPID: 0x100}, {ElementaryPID: 0x101}},
+		PCRPID:            0x100,
+	}
+	err = dmx.SeekToPTS(0x101, newClockReference(25500, 0))
+	assert.NoError(t, err)
+	p, err = dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(24000), p.AdaptationField.PCR.Base)
+
+	// Random access points are looked for within a window
+	defer func(w int64) { seekToPTSWindow = w }(seekToPTSWindow)
+	seekToPTSWindow = 5
+	buf.Reset()
+	for idx := 0; idx < 40; idx++ {
+		_, err := writePacket(w, &Packet{
+			AdaptationField: &PacketAdaptationField{
+				HasPCR:                true,
+				PCR:                   newClockReference(int64(idx*1000), 0),
+				RandomAccessIndicator: idx == 0,
+			},
+			Header: PacketHeader{
+				HasAdaptationField:        true,
+				HasPayload:                true,
+				PayloadUnitStartIndicator: idx%4 == 0,
+				PID:                       0x100,
+			},
+			Payload: []byte("payload"),
+		}, MpegTsPacketSize)
+		assert.NoError(t, err)
+	}
+	dmx = NewDemuxerFromReaderAt(context.Background(), bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	err = dmx.SeekToPTS(0x100, newClockReference(25500, 0))
+	assert.NoError(t, err)
+	p, err = dmx.NextPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(24000), p.AdaptationField.PCR.Base)
+}
+
 func BenchmarkDemuxer_NextData(b *testing.B) {
 	b.ReportAllocs()
 
@@ -199,6 +1358,40 @@ func BenchmarkDemuxer_NextData(b *testing.B) {
 	}
 }
 
+func BenchmarkDemuxer_NextDataPES(b *testing.B) {
+	bs, _ := demuxerPESBytes(b, 50000, 50000, 50000, 50000)
+	for _, c := range []struct {
+		cancellable bool
+		name        string
+		opts        []func(*Demuxer)
+	}{
+		{name: "default"},
+		{cancellable: true, name: "cancellable"},
+		{name: "reuse", opts: []func(*Demuxer){DemuxerOptReuseData(true)}},
+		{name: "zero-copy", opts: []func(*Demuxer){DemuxerOptZeroCopyPES(true)}},
+	} {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			ctx := context.Background()
+			if c.cancellable {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				defer cancel()
+			}
+			r := bytes.NewReader(bs)
+			for i := 0; i < b.N; i++ {
+				r.Seek(0, io.SeekStart)
+				dmx := NewDemuxer(ctx, r, c.opts...)
+				for {
+					if _, err := dmx.NextData(); err != nil {
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
 func FuzzDemuxer(f *testing.F) {
 	f.Fuzz(func(t *testing.T, b []byte) {
 		r := bytes.NewReader(b)
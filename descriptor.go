@@ -28,35 +28,84 @@ const (
 // Descriptor tags
 // Chapter: 6.1 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
 const (
+	DescriptorTagAAC                        = 0x7c
 	DescriptorTagAC3                        = 0x6a
 	DescriptorTagAVCVideo                   = 0x28
+	DescriptorTagAdaptationFieldData        = 0x70
+	DescriptorTagAnnouncementSupport        = 0x6e
+	DescriptorTagCA                         = 0x9
 	DescriptorTagComponent                  = 0x50
 	DescriptorTagContent                    = 0x54
+	DescriptorTagContentIdentifier          = 0x76
+	DescriptorTagDataBroadcast              = 0x64
+	DescriptorTagDataBroadcastID            = 0x66
 	DescriptorTagDataStreamAlignment        = 0x6
+	DescriptorTagDefaultAuthority           = 0x73
 	DescriptorTagEnhancedAC3                = 0x7a
 	DescriptorTagExtendedEvent              = 0x4e
 	DescriptorTagExtension                  = 0x7f
+	DescriptorTagFrequencyList              = 0x62
+	DescriptorTagHEVCVideo                  = 0x38
+	DescriptorTagHierarchy                  = 0x4
 	DescriptorTagISO639LanguageAndAudioType = 0xa
 	DescriptorTagLocalTimeOffset            = 0x58
 	DescriptorTagMaximumBitrate             = 0xe
+	DescriptorTagMultiplexBufferUtilization = 0xc
+	DescriptorTagNVODReference              = 0x4b
 	DescriptorTagNetworkName                = 0x40
+	DescriptorTagPDC                        = 0x69
 	DescriptorTagParentalRating             = 0x55
+	DescriptorTagPartialTransportStream     = 0x63
 	DescriptorTagPrivateDataIndicator       = 0xf
 	DescriptorTagPrivateDataSpecifier       = 0x5f
 	DescriptorTagRegistration               = 0x5
+	DescriptorTagSTD                        = 0x11
 	DescriptorTagService                    = 0x48
 	DescriptorTagShortEvent                 = 0x4d
+	DescriptorTagSmoothingBuffer            = 0x10
 	DescriptorTagStreamIdentifier           = 0x52
+	DescriptorTagStuffing                   = 0x42
 	DescriptorTagSubtitling                 = 0x59
+	DescriptorTagTargetBackgroundGrid       = 0x7
+	DescriptorTagTelephone                  = 0x57
 	DescriptorTagTeletext                   = 0x56
+	DescriptorTagTimeShiftedEvent           = 0x4f
+	DescriptorTagTimeShiftedService         = 0x4c
 	DescriptorTagVBIData                    = 0x45
 	DescriptorTagVBITeletext                = 0x46
+	DescriptorTagVideoWindow                = 0x8
+)
+
+// ATSC descriptor tags
+// Those tags belong to the DVB user defined range, therefore they are only interpreted when DemuxerOptATSC is enabled
+const (
+	DescriptorTagATSCCaptionService  = 0x86
+	DescriptorTagATSCServiceLocation = 0xa1
 )
 
 // Descriptor extension tags
 // Chapter: 6.3 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
 const (
+	DescriptorTagExtensionAC4                = 0x15
+	DescriptorTagExtensionImageIcon          = 0x0
+	DescriptorTagExtensionMessage            = 0x8
 	DescriptorTagExtensionSupplementaryAudio = 0x6
+	DescriptorTagExtensionT2DeliverySystem   = 0x4
+)
+
+// Hierarchy types
+// Chapter: 2.6.7 | Link: https://www.itu.int/rec/T-REC-H.222.0
+const (
+	HierarchyTypeBaseLayer           = 0xf
+	HierarchyTypeCombinedScalability = 0x8
+	HierarchyTypeDataPartitioning    = 0x4
+	HierarchyTypeExtensionBitstream  = 0x5
+	HierarchyTypeMVCSubBitstream     = 0x9
+	HierarchyTypeMultiViewProfile    = 0x7
+	HierarchyTypePrivateStream       = 0x6
+	HierarchyTypeSNRScalability      = 0x2
+	HierarchyTypeSpatialScalability  = 0x1
+	HierarchyTypeTemporalScalability = 0x3
 )
 
 // Service types
@@ -89,33 +138,111 @@ const (
 // Descriptor represents a descriptor
 // TODO Handle UTF8
 type Descriptor struct {
+	AAC                        *DescriptorAAC
 	AC3                        *DescriptorAC3
 	AVCVideo                   *DescriptorAVCVideo
+	AdaptationFieldData        *DescriptorAdaptationFieldData
+	AnnouncementSupport        *DescriptorAnnouncementSupport
+	CA                         *DescriptorCA
+	CaptionService             *DescriptorCaptionService // Only set when DemuxerOptATSC is enabled
 	Component                  *DescriptorComponent
 	Content                    *DescriptorContent
+	ContentIdentifier          *DescriptorContentIdentifier
+	DataBroadcast              *DescriptorDataBroadcast
+	DataBroadcastID            *DescriptorDataBroadcastID
 	DataStreamAlignment        *DescriptorDataStreamAlignment
+	DefaultAuthority           *DescriptorDefaultAuthority
 	EnhancedAC3                *DescriptorEnhancedAC3
 	ExtendedEvent              *DescriptorExtendedEvent
 	Extension                  *DescriptorExtension
+	FrequencyList              *DescriptorFrequencyList
+	HEVCVideo                  *DescriptorHEVCVideo
+	Hierarchy                  *DescriptorHierarchy
 	ISO639LanguageAndAudioType *DescriptorISO639LanguageAndAudioType
 	Length                     uint8
 	LocalTimeOffset            *DescriptorLocalTimeOffset
 	MaximumBitrate             *DescriptorMaximumBitrate
+	MultiplexBufferUtilization *DescriptorMultiplexBufferUtilization
+	NVODReference              *DescriptorNVODReference
 	NetworkName                *DescriptorNetworkName
+	PDC                        *DescriptorPDC
 	ParentalRating             *DescriptorParentalRating
+	PartialTransportStream     *DescriptorPartialTransportStream
 	PrivateDataIndicator       *DescriptorPrivateDataIndicator
 	PrivateDataSpecifier       *DescriptorPrivateDataSpecifier
 	Registration               *DescriptorRegistration
+	STD                        *DescriptorSTD
 	Service                    *DescriptorService
+	ServiceLocation            *DescriptorServiceLocation // Only set when DemuxerOptATSC is enabled
 	ShortEvent                 *DescriptorShortEvent
+	SmoothingBuffer            *DescriptorSmoothingBuffer
 	StreamIdentifier           *DescriptorStreamIdentifier
+	Stuffing                   *DescriptorStuffing
 	Subtitling                 *DescriptorSubtitling
 	Tag                        uint8 // the tag defines the structure of the contained data following the descriptor length.
+	TargetBackgroundGrid       *DescriptorTargetBackgroundGrid
+	Telephone                  *DescriptorTelephone
 	Teletext                   *DescriptorTeletext
+	TimeShiftedEvent           *DescriptorTimeShiftedEvent
+	TimeShiftedService         *DescriptorTimeShiftedService
 	Unknown                    *DescriptorUnknown
 	UserDefined                []byte
 	VBIData                    *DescriptorVBIData
 	VBITeletext                *DescriptorTeletext
+	VideoWindow                *DescriptorVideoWindow
+}
+
+// DescriptorAAC represents an AAC descriptor
+// Chapter: Annex H.2.1 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorAAC struct {
+	AACType         uint8
+	AdditionalInfo  []byte
+	HasAACType      bool
+	ProfileAndLevel uint8
+}
+
+func newDescriptorAAC(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorAAC, err error) {
+	// Get next byte
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorAAC{ProfileAndLevel: uint8(b)}
+
+	// Flags are optional
+	if i.Offset() >= offsetEnd {
+		return
+	}
+
+	// Get next byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// AAC type flag
+	d.HasAACType = b&0x80 > 0
+
+	// AAC type
+	if d.HasAACType {
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+		d.AACType = uint8(b)
+	}
+
+	// Additional info
+	if i.Offset() < offsetEnd {
+		if d.AdditionalInfo, err = i.NextBytes(offsetEnd - i.Offset()); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+	}
+	return
 }
 
 // DescriptorAC3 represents an AC3 descriptor
@@ -256,6 +383,202 @@ func newDescriptorAVCVideo(i *astikit.BytesIterator) (d *DescriptorAVCVideo, err
 	return
 }
 
+// DescriptorAdaptationFieldData represents an adaptation field data descriptor
+// Each bit of Flags indicates which kind of data field may be present in the adaptation field private data
+// Chapter: 6.2.1 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorAdaptationFieldData struct {
+	Flags uint8
+}
+
+func newDescriptorAdaptationFieldData(i *astikit.BytesIterator) (d *DescriptorAdaptationFieldData, err error) {
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+	d = &DescriptorAdaptationFieldData{Flags: uint8(b)}
+	return
+}
+
+// DescriptorAnnouncementSupport represents an announcement support descriptor
+// Chapter: 6.2.3 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorAnnouncementSupport struct {
+	AnnouncementSupportIndicator uint16 // Each bit flags a supported announcement type
+	Announcements                []*DescriptorAnnouncementSupportAnnouncement
+}
+
+// DescriptorAnnouncementSupportAnnouncement represents an announcement support descriptor announcement
+// Original network ID, transport stream ID, service ID and component tag are only set when the announcement is carried
+// by another service
+// Chapter: 6.2.3 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorAnnouncementSupportAnnouncement struct {
+	AnnouncementType  uint8
+	ComponentTag      uint8
+	OriginalNetworkID uint16
+	ReferenceType     uint8
+	ServiceID         uint16
+	TransportStreamID uint16
+}
+
+// hasReference checks whether the announcement is carried by another service, which is then referenced
+func (a DescriptorAnnouncementSupportAnnouncement) hasReference() bool {
+	return a.ReferenceType >= 0x1 && a.ReferenceType <= 0x3
+}
+
+func newDescriptorAnnouncementSupport(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorAnnouncementSupport, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(2); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorAnnouncementSupport{AnnouncementSupportIndicator: uint16(bs[0])<<8 | uint16(bs[1])}
+
+	// Add announcements
+	for i.Offset() < offsetEnd {
+		// Get next byte
+		var b byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+
+		// Create announcement
+		a := &DescriptorAnnouncementSupportAnnouncement{
+			AnnouncementType: uint8(b >> 4),
+			ReferenceType:    uint8(b & 0x7),
+		}
+
+		// Reference
+		if a.hasReference() {
+			// Get next bytes
+			if bs, err = i.NextBytesNoCopy(7); err != nil {
+				err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+				return
+			}
+			a.OriginalNetworkID = uint16(bs[0])<<8 | uint16(bs[1])
+			a.TransportStreamID = uint16(bs[2])<<8 | uint16(bs[3])
+			a.ServiceID = uint16(bs[4])<<8 | uint16(bs[5])
+			a.ComponentTag = uint8(bs[6])
+		}
+
+		// Append announcement
+		d.Announcements = append(d.Announcements, a)
+	}
+	return
+}
+
+// DescriptorCaptionService represents an ATSC caption service descriptor
+// Chapter: 6.9.2 | Link: https://www.atsc.org/wp-content/uploads/2021/04/A65_2013.pdf
+type DescriptorCaptionService struct {
+	Services []*DescriptorCaptionServiceItem
+}
+
+// DescriptorCaptionServiceItem represents an ATSC caption service descriptor service
+type DescriptorCaptionServiceItem struct {
+	CaptionServiceNumber uint8 // Only set when DigitalCC is true
+	DigitalCC            bool
+	EasyReader           bool
+	Language             []byte
+	Line21Field          bool // Only set when DigitalCC is false
+	WideAspectRatio      bool
+}
+
+func newDescriptorCaptionService(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorCaptionService, err error) {
+	// Get next byte
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorCaptionService{}
+
+	// Loop through services
+	for idx := 0; idx < int(b&0x1f) && i.Offset() < offsetEnd; idx++ {
+		// Get next bytes
+		var bs []byte
+		if bs, err = i.NextBytes(6); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Create service
+		s := &DescriptorCaptionServiceItem{
+			DigitalCC:       bs[3]&0x80 > 0,
+			EasyReader:      bs[4]&0x80 > 0,
+			Language:        bs[0:3],
+			WideAspectRatio: bs[4]&0x40 > 0,
+		}
+		if s.DigitalCC {
+			s.CaptionServiceNumber = uint8(bs[3] & 0x3f)
+		} else {
+			s.Line21Field = bs[3]&0x1 > 0
+		}
+
+		// Append service
+		d.Services = append(d.Services, s)
+	}
+	return
+}
+
+// parseATSCDescriptors interprets user defined descriptors whose tag is an ATSC one
+func parseATSCDescriptors(ds []*Descriptor) (err error) {
+	for _, d := range ds {
+		if d.UserDefined == nil {
+			continue
+		}
+		switch d.Tag {
+		case DescriptorTagATSCCaptionService:
+			if d.CaptionService, err = newDescriptorCaptionService(astikit.NewBytesIterator(d.UserDefined), len(d.UserDefined)); err != nil {
+				err = fmt.Errorf("astits: parsing caption service descriptor failed: %w", err)
+				return
+			}
+		case DescriptorTagATSCServiceLocation:
+			if d.ServiceLocation, err = newDescriptorServiceLocation(astikit.NewBytesIterator(d.UserDefined)); err != nil {
+				err = fmt.Errorf("astits: parsing service location descriptor failed: %w", err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// DescriptorCA represents a conditional access descriptor
+// Chapter: 2.6.16 | Link: https://www.itu.int/rec/T-REC-H.222.0
+type DescriptorCA struct {
+	CAPID       uint16 // ECM PID when found in a PMT, EMM PID when found in a CAT
+	CASystemID  uint16
+	PrivateData []byte
+}
+
+func newDescriptorCA(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorCA, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(4); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorCA{
+		CAPID:      uint16(bs[2]&0x1f)<<8 | uint16(bs[3]),
+		CASystemID: uint16(bs[0])<<8 | uint16(bs[1]),
+	}
+
+	// Private data
+	if i.Offset() < offsetEnd {
+		if d.PrivateData, err = i.NextBytes(offsetEnd - i.Offset()); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+	}
+	return
+}
+
 // DescriptorComponent represents a component descriptor
 // Chapter: 6.2.8 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
 type DescriptorComponent struct {
@@ -267,6 +590,155 @@ type DescriptorComponent struct {
 	Text               []byte
 }
 
+// componentKey identifies a stream content, stream content ext and component type combination
+type componentKey struct {
+	componentType    uint8
+	streamContent    uint8
+	streamContentExt uint8
+}
+
+// componentDescriptions maps combinations to their description. Stream content ext is only meaningful for stream
+// contents 0x9 and above, and is set to 0xf otherwise.
+// Chapter: 6.2.8 (table 26) | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+var componentDescriptions = map[componentKey]string{
+	// MPEG-2 video
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x01}: "MPEG-2 video, 4:3 aspect ratio, 25 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x02}: "MPEG-2 video, 16:9 aspect ratio with pan vectors, 25 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x03}: "MPEG-2 video, 16:9 aspect ratio without pan vectors, 25 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x04}: "MPEG-2 video, > 16:9 aspect ratio, 25 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x05}: "MPEG-2 video, 4:3 aspect ratio, 30 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x06}: "MPEG-2 video, 16:9 aspect ratio with pan vectors, 30 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x07}: "MPEG-2 video, 16:9 aspect ratio without pan vectors, 30 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x08}: "MPEG-2 video, > 16:9 aspect ratio, 30 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x09}: "MPEG-2 high definition video, 4:3 aspect ratio, 25 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x0a}: "MPEG-2 high definition video, 16:9 aspect ratio with pan vectors, 25 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x0b}: "MPEG-2 high definition video, 16:9 aspect ratio without pan vectors, 25 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x0c}: "MPEG-2 high definition video, > 16:9 aspect ratio, 25 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x0d}: "MPEG-2 high definition video, 4:3 aspect ratio, 30 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x0e}: "MPEG-2 high definition video, 16:9 aspect ratio with pan vectors, 30 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x0f}: "MPEG-2 high definition video, 16:9 aspect ratio without pan vectors, 30 Hz",
+	{streamContent: 0x1, streamContentExt: 0xf, componentType: 0x10}: "MPEG-2 high definition video, > 16:9 aspect ratio, 30 Hz",
+
+	// MPEG-1 Layer 2 audio
+	{streamContent: 0x2, streamContentExt: 0xf, componentType: 0x01}: "MPEG-1 Layer 2 audio, single mono channel",
+	{streamContent: 0x2, streamContentExt: 0xf, componentType: 0x02}: "MPEG-1 Layer 2 audio, dual mono channel",
+	{streamContent: 0x2, streamContentExt: 0xf, componentType: 0x03}: "MPEG-1 Layer 2 audio, stereo (2 channel)",
+	{streamContent: 0x2, streamContentExt: 0xf, componentType: 0x04}: "MPEG-1 Layer 2 audio, multi-lingual, multi-channel",
+	{streamContent: 0x2, streamContentExt: 0xf, componentType: 0x05}: "MPEG-1 Layer 2 audio, surround sound",
+	{streamContent: 0x2, streamContentExt: 0xf, componentType: 0x40}: "MPEG-1 Layer 2 audio description for the visually impaired",
+	{streamContent: 0x2, streamContentExt: 0xf, componentType: 0x41}: "MPEG-1 Layer 2 audio for the hard of hearing",
+	{streamContent: 0x2, streamContentExt: 0xf, componentType: 0x42}: "receiver-mix supplementary audio as per annex E of TS 101 154",
+	{streamContent: 0x2, streamContentExt: 0xf, componentType: 0x47}: "MPEG-1 Layer 2 audio, receiver-mix audio description",
+	{streamContent: 0x2, streamContentExt: 0xf, componentType: 0x48}: "MPEG-1 Layer 2 audio, broadcast-mix audio description",
+
+	// Subtitles and teletext
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x01}: "EBU Teletext subtitles",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x02}: "associated EBU Teletext",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x03}: "VBI data",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x10}: "DVB subtitles (normal) with no monitor aspect ratio criticality",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x11}: "DVB subtitles (normal) for display on 4:3 aspect ratio monitor",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x12}: "DVB subtitles (normal) for display on 16:9 aspect ratio monitor",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x13}: "DVB subtitles (normal) for display on 2.21:1 aspect ratio monitor",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x14}: "DVB subtitles (normal) for display on a high definition monitor",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x15}: "DVB subtitles (normal) with plano-stereoscopic disparity for display on a high definition monitor",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x16}: "DVB subtitles (normal) for display on an ultra high definition monitor",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x20}: "DVB subtitles (for the hard of hearing) with no monitor aspect ratio criticality",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x21}: "DVB subtitles (for the hard of hearing) for display on 4:3 aspect ratio monitor",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x22}: "DVB subtitles (for the hard of hearing) for display on 16:9 aspect ratio monitor",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x23}: "DVB subtitles (for the hard of hearing) for display on 2.21:1 aspect ratio monitor",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x24}: "DVB subtitles (for the hard of hearing) for display on a high definition monitor",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x25}: "DVB subtitles (for the hard of hearing) with plano-stereoscopic disparity for display on a high definition monitor",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x26}: "DVB subtitles (for the hard of hearing) for display on an ultra high definition monitor",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x30}: "open (in-vision) sign language interpretation for the deaf",
+	{streamContent: 0x3, streamContentExt: 0xf, componentType: 0x31}: "closed sign language interpretation for the deaf",
+
+	// H.264/AVC video
+	{streamContent: 0x5, streamContentExt: 0xf, componentType: 0x01}: "H.264/AVC standard definition video, 4:3 aspect ratio, 25 Hz",
+	{streamContent: 0x5, streamContentExt: 0xf, componentType: 0x03}: "H.264/AVC standard definition video, 16:9 aspect ratio, 25 Hz",
+	{streamContent: 0x5, streamContentExt: 0xf, componentType: 0x04}: "H.264/AVC standard definition video, > 16:9 aspect ratio, 25 Hz",
+	{streamContent: 0x5, streamContentExt: 0xf, componentType: 0x05}: "H.264/AVC standard definition video, 4:3 aspect ratio, 30 Hz",
+	{streamContent: 0x5, streamContentExt: 0xf, componentType: 0x07}: "H.264/AVC standard definition video, 16:9 aspect ratio, 30 Hz",
+	{streamContent: 0x5, streamContentExt: 0xf, componentType: 0x08}: "H.264/AVC standard definition video, > 16:9 aspect ratio, 30 Hz",
+	{streamContent: 0x5, streamContentExt: 0xf, componentType: 0x0b}: "H.264/AVC high definition video, 16:9 aspect ratio, 25 Hz",
+	{streamContent: 0x5, streamContentExt: 0xf, componentType: 0x0c}: "H.264/AVC high definition video, > 16:9 aspect ratio, 25 Hz",
+	{streamContent: 0x5, streamContentExt: 0xf, componentType: 0x0f}: "H.264/AVC high definition video, 16:9 aspect ratio, 30 Hz",
+	{streamContent: 0x5, streamContentExt: 0xf, componentType: 0x10}: "H.264/AVC high definition video, > 16:9 aspect ratio, 30 Hz",
+
+	// HE-AAC audio
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x01}: "HE-AAC audio, single mono channel",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x03}: "HE-AAC audio, stereo",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x05}: "HE-AAC audio, surround sound",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x40}: "HE-AAC audio description for the visually impaired",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x41}: "HE-AAC audio for the hard of hearing",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x42}: "HE-AAC receiver-mix supplementary audio as per annex E of TS 101 154",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x43}: "HE-AAC v2 audio, stereo",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x44}: "HE-AAC v2 audio description for the visually impaired",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x45}: "HE-AAC v2 audio for the hard of hearing",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x46}: "HE-AAC v2 receiver-mix supplementary audio as per annex E of TS 101 154",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x47}: "HE-AAC receiver-mix audio description for the visually impaired",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x48}: "HE-AAC broadcast-mix audio description for the visually impaired",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x49}: "HE-AAC v2 receiver-mix audio description for the visually impaired",
+	{streamContent: 0x6, streamContentExt: 0xf, componentType: 0x4a}: "HE-AAC v2 broadcast-mix audio description for the visually impaired",
+
+	// DVB SRM data
+	{streamContent: 0x8, streamContentExt: 0xf, componentType: 0x01}: "DVB SRM data",
+
+	// HEVC video
+	{streamContent: 0x9, streamContentExt: 0x0, componentType: 0x00}: "HEVC Main Profile high definition video, 50 Hz",
+	{streamContent: 0x9, streamContentExt: 0x0, componentType: 0x01}: "HEVC Main 10 Profile high definition video, 50 Hz",
+	{streamContent: 0x9, streamContentExt: 0x0, componentType: 0x02}: "HEVC Main Profile high definition video, 60 Hz",
+	{streamContent: 0x9, streamContentExt: 0x0, componentType: 0x03}: "HEVC Main 10 Profile high definition video, 60 Hz",
+	{streamContent: 0x9, streamContentExt: 0x0, componentType: 0x04}: "HEVC ultra high definition video",
+	{streamContent: 0x9, streamContentExt: 0x0, componentType: 0x05}: "HEVC ultra high definition video with PQ10 HDR with a frame rate lower than or equal to 60 Hz",
+	{streamContent: 0x9, streamContentExt: 0x0, componentType: 0x06}: "HEVC ultra high definition video, frame rate of 100 Hz, 120 000/1 001 Hz, or 120 Hz without a half frame rate HEVC temporal video sub-bit-stream",
+	{streamContent: 0x9, streamContentExt: 0x0, componentType: 0x07}: "HEVC ultra high definition video with PQ10 HDR, frame rate of 100 Hz, 120 000/1 001 Hz, or 120 Hz without a half frame rate HEVC temporal video sub-bit-stream",
+
+	// AC-4 audio
+	{streamContent: 0x9, streamContentExt: 0x1, componentType: 0x00}: "AC-4 main audio, mono",
+	{streamContent: 0x9, streamContentExt: 0x1, componentType: 0x01}: "AC-4 main audio, mono, dialogue enhancement enabled",
+	{streamContent: 0x9, streamContentExt: 0x1, componentType: 0x02}: "AC-4 main audio, stereo",
+	{streamContent: 0x9, streamContentExt: 0x1, componentType: 0x03}: "AC-4 main audio, stereo, dialogue enhancement enabled",
+	{streamContent: 0x9, streamContentExt: 0x1, componentType: 0x04}: "AC-4 main audio, multichannel",
+	{streamContent: 0x9, streamContentExt: 0x1, componentType: 0x05}: "AC-4 main audio, multichannel, dialogue enhancement enabled",
+
+	// Video properties
+	{streamContent: 0xb, streamContentExt: 0xf, componentType: 0x00}: "less than 16:9 aspect ratio",
+	{streamContent: 0xb, streamContentExt: 0xf, componentType: 0x01}: "16:9 aspect ratio",
+	{streamContent: 0xb, streamContentExt: 0xf, componentType: 0x02}: "greater than 16:9 aspect ratio",
+	{streamContent: 0xb, streamContentExt: 0xf, componentType: 0x03}: "plano-stereoscopic top and bottom (TaB) frame-packing",
+	{streamContent: 0xb, streamContentExt: 0xf, componentType: 0x04}: "HLG10 HDR",
+}
+
+// Describe returns a human readable description of the component based on its stream content, stream content ext
+// and component type, or "Unknown" if the combination is not known
+func (d *DescriptorComponent) Describe() string {
+	switch d.StreamContent {
+	case 0x4:
+		// Component type is a bit field whose most significant bit indicates Enhanced AC-3
+		if d.ComponentType&0x80 > 0 {
+			return "Enhanced AC-3 audio"
+		}
+		return "AC-3 audio"
+	case 0x7:
+		// Component type is a bit field
+		return "DTS audio"
+	}
+
+	// Stream content ext is not meaningful for stream contents below 0x9
+	k := componentKey{
+		componentType:    d.ComponentType,
+		streamContent:    d.StreamContent,
+		streamContentExt: d.StreamContentExt,
+	}
+	if k.streamContent < 0x9 {
+		k.streamContentExt = 0xf
+	}
+	if s, ok := componentDescriptions[k]; ok {
+		return s
+	}
+	return "Unknown"
+}
+
 func newDescriptorComponent(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorComponent, err error) {
 	// Init
 	d = &DescriptorComponent{}
@@ -355,94 +827,281 @@ func newDescriptorContent(i *astikit.BytesIterator, offsetEnd int) (d *Descripto
 	return
 }
 
-// DescriptorDataStreamAlignment represents a data stream alignment descriptor
-type DescriptorDataStreamAlignment struct {
-	Type uint8
-}
+// CRID locations
+const (
+	CRIDLocationCIT        = 0x1
+	CRIDLocationDescriptor = 0x0
+)
 
-func newDescriptorDataStreamAlignment(i *astikit.BytesIterator) (d *DescriptorDataStreamAlignment, err error) {
-	var b byte
-	if b, err = i.NextByte(); err != nil {
-		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
-		return
-	}
-	d = &DescriptorDataStreamAlignment{Type: uint8(b)}
-	return
+// DescriptorContentIdentifier represents a content identifier descriptor
+// Chapter: 12.1 | Link: https://www.etsi.org/deliver/etsi_ts/102300_102399/102323/01.07.01_60/ts_102323v010701p.pdf
+type DescriptorContentIdentifier struct {
+	Items []*DescriptorContentIdentifierItem
 }
 
-// DescriptorEnhancedAC3 represents an enhanced AC3 descriptor
-// Chapter: Annex D | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
-type DescriptorEnhancedAC3 struct {
-	AdditionalInfo   []byte
-	ASVC             uint8
-	BSID             uint8
-	ComponentType    uint8
-	HasASVC          bool
-	HasBSID          bool
-	HasComponentType bool
-	HasMainID        bool
-	HasSubStream1    bool
-	HasSubStream2    bool
-	HasSubStream3    bool
-	MainID           uint8
-	MixInfoExists    bool
-	SubStream1       uint8
-	SubStream2       uint8
-	SubStream3       uint8
+// DescriptorContentIdentifierItem represents a content identifier item descriptor
+// Chapter: 12.1 | Link: https://www.etsi.org/deliver/etsi_ts/102300_102399/102323/01.07.01_60/ts_102323v010701p.pdf
+type DescriptorContentIdentifierItem struct {
+	CRID          []byte // Only set when the CRID is carried in the descriptor
+	CRIDLocation  uint8
+	CRIDReference uint16 // Only set when the CRID is carried in the CIT
+	CRIDType      uint8
 }
 
-func newDescriptorEnhancedAC3(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorEnhancedAC3, err error) {
-	// Get next byte
-	var b byte
-	if b, err = i.NextByte(); err != nil {
-		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
-		return
-	}
-
+func newDescriptorContentIdentifier(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorContentIdentifier, err error) {
 	// Create descriptor
-	d = &DescriptorEnhancedAC3{
-		HasASVC:          uint8(b&0x10) > 0,
-		HasBSID:          uint8(b&0x40) > 0,
-		HasComponentType: uint8(b&0x80) > 0,
-		HasMainID:        uint8(b&0x20) > 0,
-		HasSubStream1:    uint8(b&0x4) > 0,
-		HasSubStream2:    uint8(b&0x2) > 0,
-		HasSubStream3:    uint8(b&0x1) > 0,
-		MixInfoExists:    uint8(b&0x8) > 0,
-	}
+	d = &DescriptorContentIdentifier{}
 
-	// Component type
-	if d.HasComponentType {
+	// Add items
+	for i.Offset() < offsetEnd {
 		// Get next byte
+		var b byte
 		if b, err = i.NextByte(); err != nil {
 			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
 			return
 		}
-		d.ComponentType = uint8(b)
-	}
 
-	// BSID
-	if d.HasBSID {
-		// Get next byte
-		if b, err = i.NextByte(); err != nil {
-			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
-			return
+		// Create item
+		itm := &DescriptorContentIdentifierItem{
+			CRIDLocation: uint8(b & 0x3),
+			CRIDType:     uint8(b >> 2),
 		}
-		d.BSID = uint8(b)
-	}
 
-	// Main ID
-	if d.HasMainID {
-		// Get next byte
-		if b, err = i.NextByte(); err != nil {
-			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
-			return
+		// Switch on CRID location
+		switch itm.CRIDLocation {
+		case CRIDLocationDescriptor:
+			// Get next byte
+			if b, err = i.NextByte(); err != nil {
+				err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+				return
+			}
+
+			// CRID
+			if itm.CRID, err = i.NextBytes(int(b)); err != nil {
+				err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+				return
+			}
+		case CRIDLocationCIT:
+			// Get next bytes
+			var bs []byte
+			if bs, err = i.NextBytesNoCopy(2); err != nil {
+				err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+				return
+			}
+
+			// CRID reference
+			itm.CRIDReference = uint16(bs[0])<<8 | uint16(bs[1])
 		}
-		d.MainID = uint8(b)
+
+		// Append item
+		d.Items = append(d.Items, itm)
 	}
+	return
+}
 
-	// ASVC
-	if d.HasASVC {
+// DescriptorDataBroadcast represents a data broadcast descriptor
+// Chapter: 6.2.11 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorDataBroadcast struct {
+	ComponentTag       uint8
+	DataBroadcastID    uint16
+	ISO639LanguageCode []byte
+	Selector           []byte
+	Text               []byte
+}
+
+func newDescriptorDataBroadcast(i *astikit.BytesIterator) (d *DescriptorDataBroadcast, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(2); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorDataBroadcast{DataBroadcastID: uint16(bs[0])<<8 | uint16(bs[1])}
+
+	// Get next byte
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Component tag
+	d.ComponentTag = uint8(b)
+
+	// Get next byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Selector length
+	selectorLength := int(b)
+
+	// Selector
+	if d.Selector, err = i.NextBytes(selectorLength); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// ISO639 language code
+	if d.ISO639LanguageCode, err = i.NextBytes(3); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Get next byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Text length
+	textLength := int(b)
+
+	// Text
+	if d.Text, err = i.NextBytes(textLength); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+	return
+}
+
+// DescriptorDataBroadcastID represents a data broadcast id descriptor
+// Chapter: 6.2.12 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorDataBroadcastID struct {
+	DataBroadcastID uint16
+	IDSelector      []byte
+}
+
+func newDescriptorDataBroadcastID(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorDataBroadcastID, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(2); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorDataBroadcastID{DataBroadcastID: uint16(bs[0])<<8 | uint16(bs[1])}
+
+	// ID selector
+	if i.Offset() < offsetEnd {
+		if d.IDSelector, err = i.NextBytes(offsetEnd - i.Offset()); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+	}
+	return
+}
+
+// DescriptorDataStreamAlignment represents a data stream alignment descriptor
+type DescriptorDataStreamAlignment struct {
+	Type uint8
+}
+
+func newDescriptorDataStreamAlignment(i *astikit.BytesIterator) (d *DescriptorDataStreamAlignment, err error) {
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+	d = &DescriptorDataStreamAlignment{Type: uint8(b)}
+	return
+}
+
+// DescriptorDefaultAuthority represents a default authority descriptor
+// Chapter: 6.2.10 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorDefaultAuthority struct {
+	Authority []byte
+}
+
+func newDescriptorDefaultAuthority(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorDefaultAuthority, err error) {
+	// Create descriptor
+	d = &DescriptorDefaultAuthority{}
+
+	// Authority
+	if d.Authority, err = i.NextBytes(offsetEnd - i.Offset()); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+	return
+}
+
+// DescriptorEnhancedAC3 represents an enhanced AC3 descriptor
+// Chapter: Annex D | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorEnhancedAC3 struct {
+	AdditionalInfo   []byte
+	ASVC             uint8
+	BSID             uint8
+	ComponentType    uint8
+	HasASVC          bool
+	HasBSID          bool
+	HasComponentType bool
+	HasMainID        bool
+	HasSubStream1    bool
+	HasSubStream2    bool
+	HasSubStream3    bool
+	MainID           uint8
+	MixInfoExists    bool
+	SubStream1       uint8
+	SubStream2       uint8
+	SubStream3       uint8
+}
+
+func newDescriptorEnhancedAC3(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorEnhancedAC3, err error) {
+	// Get next byte
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorEnhancedAC3{
+		HasASVC:          uint8(b&0x10) > 0,
+		HasBSID:          uint8(b&0x40) > 0,
+		HasComponentType: uint8(b&0x80) > 0,
+		HasMainID:        uint8(b&0x20) > 0,
+		HasSubStream1:    uint8(b&0x4) > 0,
+		HasSubStream2:    uint8(b&0x2) > 0,
+		HasSubStream3:    uint8(b&0x1) > 0,
+		MixInfoExists:    uint8(b&0x8) > 0,
+	}
+
+	// Component type
+	if d.HasComponentType {
+		// Get next byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+		d.ComponentType = uint8(b)
+	}
+
+	// BSID
+	if d.HasBSID {
+		// Get next byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+		d.BSID = uint8(b)
+	}
+
+	// Main ID
+	if d.HasMainID {
+		// Get next byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+		d.MainID = uint8(b)
+	}
+
+	// ASVC
+	if d.HasASVC {
 		// Get next byte
 		if b, err = i.NextByte(); err != nil {
 			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
@@ -508,7 +1167,7 @@ type DescriptorExtendedEventItem struct {
 	Description []byte
 }
 
-func newDescriptorExtendedEvent(i *astikit.BytesIterator) (d *DescriptorExtendedEvent, err error) {
+func newDescriptorExtendedEvent(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorExtendedEvent, err error) {
 	// Init
 	d = &DescriptorExtendedEvent{}
 
@@ -540,12 +1199,17 @@ func newDescriptorExtendedEvent(i *astikit.BytesIterator) (d *DescriptorExtended
 	// Items length
 	itemsLength := int(b)
 
+	// Items can't go past the end of the descriptor
+	offsetItemsEnd := i.Offset() + itemsLength
+	if offsetItemsEnd > offsetEnd {
+		offsetItemsEnd = offsetEnd
+	}
+
 	// Items
-	offsetEnd := i.Offset() + itemsLength
-	for i.Offset() < offsetEnd {
+	for i.Offset() < offsetItemsEnd {
 		// Create item
 		var item *DescriptorExtendedEventItem
-		if item, err = newDescriptorExtendedEventItem(i); err != nil {
+		if item, err = newDescriptorExtendedEventItem(i, offsetItemsEnd); err != nil {
 			err = fmt.Errorf("astits: creating extended event item failed: %w", err)
 			return
 		}
@@ -554,6 +1218,11 @@ func newDescriptorExtendedEvent(i *astikit.BytesIterator) (d *DescriptorExtended
 		d.Items = append(d.Items, item)
 	}
 
+	// No text
+	if i.Offset() >= offsetEnd {
+		return
+	}
+
 	// Get next byte
 	if b, err = i.NextByte(); err != nil {
 		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
@@ -564,14 +1233,14 @@ func newDescriptorExtendedEvent(i *astikit.BytesIterator) (d *DescriptorExtended
 	textLength := int(b)
 
 	// Text
-	if d.Text, err = i.NextBytes(textLength); err != nil {
+	if d.Text, err = nextBytesUntil(i, textLength, offsetEnd); err != nil {
 		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
 		return
 	}
 	return
 }
 
-func newDescriptorExtendedEventItem(i *astikit.BytesIterator) (d *DescriptorExtendedEventItem, err error) {
+func newDescriptorExtendedEventItem(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorExtendedEventItem, err error) {
 	// Init
 	d = &DescriptorExtendedEventItem{}
 
@@ -586,11 +1255,16 @@ func newDescriptorExtendedEventItem(i *astikit.BytesIterator) (d *DescriptorExte
 	descriptionLength := int(b)
 
 	// Description
-	if d.Description, err = i.NextBytes(descriptionLength); err != nil {
+	if d.Description, err = nextBytesUntil(i, descriptionLength, offsetEnd); err != nil {
 		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
 		return
 	}
 
+	// No content
+	if i.Offset() >= offsetEnd {
+		return
+	}
+
 	// Get next byte
 	if b, err = i.NextByte(); err != nil {
 		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
@@ -601,7 +1275,7 @@ func newDescriptorExtendedEventItem(i *astikit.BytesIterator) (d *DescriptorExte
 	contentLength := int(b)
 
 	// Content
-	if d.Content, err = i.NextBytes(contentLength); err != nil {
+	if d.Content, err = nextBytesUntil(i, contentLength, offsetEnd); err != nil {
 		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
 		return
 	}
@@ -611,7 +1285,11 @@ func newDescriptorExtendedEventItem(i *astikit.BytesIterator) (d *DescriptorExte
 // DescriptorExtension represents an extension descriptor
 // Chapter: 6.2.16 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
 type DescriptorExtension struct {
+	AC4                *DescriptorExtensionAC4
+	ImageIcon          *DescriptorExtensionImageIcon
+	Message            *DescriptorExtensionMessage
 	SupplementaryAudio *DescriptorExtensionSupplementaryAudio
+	T2DeliverySystem   *DescriptorExtensionT2DeliverySystem
 	Tag                uint8
 	Unknown            *[]byte
 }
@@ -629,11 +1307,31 @@ func newDescriptorExtension(i *astikit.BytesIterator, offsetEnd int) (d *Descrip
 
 	// Switch on tag
 	switch d.Tag {
+	case DescriptorTagExtensionAC4:
+		if d.AC4, err = newDescriptorExtensionAC4(i, offsetEnd); err != nil {
+			err = fmt.Errorf("astits: parsing extension AC-4 descriptor failed: %w", err)
+			return
+		}
+	case DescriptorTagExtensionImageIcon:
+		if d.ImageIcon, err = newDescriptorExtensionImageIcon(i); err != nil {
+			err = fmt.Errorf("astits: parsing extension image icon descriptor failed: %w", err)
+			return
+		}
+	case DescriptorTagExtensionMessage:
+		if d.Message, err = newDescriptorExtensionMessage(i, offsetEnd); err != nil {
+			err = fmt.Errorf("astits: parsing extension message descriptor failed: %w", err)
+			return
+		}
 	case DescriptorTagExtensionSupplementaryAudio:
 		if d.SupplementaryAudio, err = newDescriptorExtensionSupplementaryAudio(i, offsetEnd); err != nil {
 			err = fmt.Errorf("astits: parsing extension supplementary audio descriptor failed: %w", err)
 			return
 		}
+	case DescriptorTagExtensionT2DeliverySystem:
+		if d.T2DeliverySystem, err = newDescriptorExtensionT2DeliverySystem(i, offsetEnd); err != nil {
+			err = fmt.Errorf("astits: parsing extension T2 delivery system descriptor failed: %w", err)
+			return
+		}
 	default:
 		// Get next bytes
 		var b []byte
@@ -648,17 +1346,19 @@ func newDescriptorExtension(i *astikit.BytesIterator, offsetEnd int) (d *Descrip
 	return
 }
 
-// DescriptorExtensionSupplementaryAudio represents a supplementary audio extension descriptor
-// Chapter: 6.4.10 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
-type DescriptorExtensionSupplementaryAudio struct {
-	EditorialClassification uint8
-	HasLanguageCode         bool
-	LanguageCode            []byte
-	MixType                 bool
-	PrivateData             []byte
+// DescriptorExtensionAC4 represents an AC-4 extension descriptor
+// Presentation info can be found in the TOC, which holds the ac4_dsi bytes
+// Chapter: D.7 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorExtensionAC4 struct {
+	AdditionalInfo           []byte
+	ChannelMode              uint8
+	DialogEnhancementEnabled bool
+	HasConfig                bool
+	HasTOC                   bool
+	TOC                      []byte
 }
 
-func newDescriptorExtensionSupplementaryAudio(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorExtensionSupplementaryAudio, err error) {
+func newDescriptorExtensionAC4(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorExtensionAC4, err error) {
 	// Get next byte
 	var b byte
 	if b, err = i.NextByte(); err != nil {
@@ -667,23 +1367,42 @@ func newDescriptorExtensionSupplementaryAudio(i *astikit.BytesIterator, offsetEn
 	}
 
 	// Init
-	d = &DescriptorExtensionSupplementaryAudio{
-		EditorialClassification: uint8(b >> 2 & 0x1f),
-		HasLanguageCode:         b&0x1 > 0,
-		MixType:                 b&0x80 > 0,
+	d = &DescriptorExtensionAC4{
+		HasConfig: b&0x80 > 0,
+		HasTOC:    b&0x40 > 0,
 	}
 
-	// Language code
-	if d.HasLanguageCode {
-		if d.LanguageCode, err = i.NextBytes(3); err != nil {
+	// Config
+	if d.HasConfig {
+		// Get next byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+
+		// Update descriptor
+		d.DialogEnhancementEnabled = b&0x80 > 0
+		d.ChannelMode = uint8(b >> 5 & 0x3)
+	}
+
+	// TOC
+	if d.HasTOC {
+		// Get next byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+
+		// Get next bytes
+		if d.TOC, err = i.NextBytes(int(b)); err != nil {
 			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
 			return
 		}
 	}
 
-	// Private data
+	// Additional info
 	if i.Offset() < offsetEnd {
-		if d.PrivateData, err = i.NextBytes(offsetEnd - i.Offset()); err != nil {
+		if d.AdditionalInfo, err = i.NextBytes(offsetEnd - i.Offset()); err != nil {
 			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
 			return
 		}
@@ -691,41 +1410,545 @@ func newDescriptorExtensionSupplementaryAudio(i *astikit.BytesIterator, offsetEn
 	return
 }
 
-// DescriptorISO639LanguageAndAudioType represents an ISO639 language descriptor
-// https://github.com/gfto/bitstream/blob/master/mpeg/psi/desc_0a.h
-// FIXME (barbashov) according to Chapter 2.6.18 ISO/IEC 13818-1:2015 there could be not one, but multiple such descriptors
-type DescriptorISO639LanguageAndAudioType struct {
-	Language []byte
-	Type     uint8
+// Icon transport modes
+// Chapter: 6.4.7 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+const (
+	IconTransportModeData = 0x0
+	IconTransportModeURL  = 0x1
+)
+
+// DescriptorExtensionImageIcon represents an image icon extension descriptor
+// Icon transport mode, position, icon type and URL are only present in the first descriptor of an icon, whose
+// descriptor number is 0. Following descriptors only carry the remaining icon data.
+// Chapter: 6.4.7 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorExtensionImageIcon struct {
+	CoordinateSystem     uint8
+	DescriptorNumber     uint8
+	HasPosition          bool
+	IconData             []byte
+	IconHorizontalOrigin uint16
+	IconID               uint8
+	IconTransportMode    uint8
+	IconType             []byte // MIME type of the icon, e.g. "image/png"
+	IconVerticalOrigin   uint16
+	LastDescriptorNumber uint8
+	URL                  []byte
 }
 
-// In some actual cases, the length is 3 and the language is described in only 2 bytes
-func newDescriptorISO639LanguageAndAudioType(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorISO639LanguageAndAudioType, err error) {
+func newDescriptorExtensionImageIcon(i *astikit.BytesIterator) (d *DescriptorExtensionImageIcon, err error) {
 	// Get next bytes
 	var bs []byte
-	if bs, err = i.NextBytes(offsetEnd - i.Offset()); err != nil {
+	if bs, err = i.NextBytesNoCopy(2); err != nil {
 		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
 		return
 	}
 
 	// Create descriptor
-	d = &DescriptorISO639LanguageAndAudioType{
-		Language: bs[0 : len(bs)-1],
-		Type:     uint8(bs[len(bs)-1]),
+	d = &DescriptorExtensionImageIcon{
+		DescriptorNumber:     uint8(bs[0] >> 4),
+		IconID:               uint8(bs[1] & 0x7),
+		LastDescriptorNumber: uint8(bs[0] & 0xf),
 	}
-	return
-}
 
-// DescriptorLocalTimeOffset represents a local time offset descriptor
-// Chapter: 6.2.20 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
-type DescriptorLocalTimeOffset struct {
-	Items []*DescriptorLocalTimeOffsetItem
-}
+	// Get length prefixed fields
+	fs := []*[]byte{&d.IconData}
+	if d.DescriptorNumber == 0 {
+		// Get next byte
+		var b byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
 
-// DescriptorLocalTimeOffsetItem represents a local time offset item descriptor
-// Chapter: 6.2.20 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
-type DescriptorLocalTimeOffsetItem struct {
-	CountryCode             []byte
+		// Update descriptor
+		d.IconTransportMode = uint8(b >> 6)
+		d.HasPosition = b&0x20 > 0
+
+		// Position
+		if d.HasPosition {
+			// Update descriptor
+			d.CoordinateSystem = uint8((b >> 2) & 0x7)
+
+			// Get next bytes
+			if bs, err = i.NextBytesNoCopy(3); err != nil {
+				err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+				return
+			}
+
+			// Update descriptor
+			d.IconHorizontalOrigin = uint16(bs[0])<<4 | uint16(bs[1]>>4)
+			d.IconVerticalOrigin = uint16(bs[1]&0xf)<<8 | uint16(bs[2])
+		}
+
+		// Update length prefixed fields
+		fs = []*[]byte{&d.IconType}
+		switch d.IconTransportMode {
+		case IconTransportModeData:
+			fs = append(fs, &d.IconData)
+		case IconTransportModeURL:
+			fs = append(fs, &d.URL)
+		}
+	}
+
+	// Loop through length prefixed fields
+	for _, f := range fs {
+		// Get next byte
+		var b byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+
+		// Get next bytes
+		if *f, err = i.NextBytes(int(b)); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+	}
+	return
+}
+
+// DescriptorExtensionMessage represents a message extension descriptor
+// Chapter: 6.4.9 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorExtensionMessage struct {
+	Language  []byte
+	MessageID uint8
+	Text      []byte
+}
+
+func newDescriptorExtensionMessage(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorExtensionMessage, err error) {
+	// Get next byte
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorExtensionMessage{MessageID: uint8(b)}
+
+	// Language
+	if d.Language, err = i.NextBytes(3); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Text
+	if i.Offset() < offsetEnd {
+		if d.Text, err = i.NextBytes(offsetEnd - i.Offset()); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+	}
+	return
+}
+
+// DescriptorExtensionSupplementaryAudio represents a supplementary audio extension descriptor
+// Chapter: 6.4.10 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorExtensionSupplementaryAudio struct {
+	EditorialClassification uint8
+	HasLanguageCode         bool
+	LanguageCode            []byte
+	MixType                 bool
+	PrivateData             []byte
+}
+
+func newDescriptorExtensionSupplementaryAudio(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorExtensionSupplementaryAudio, err error) {
+	// Get next byte
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Init
+	d = &DescriptorExtensionSupplementaryAudio{
+		EditorialClassification: uint8(b >> 2 & 0x1f),
+		HasLanguageCode:         b&0x1 > 0,
+		MixType:                 b&0x80 > 0,
+	}
+
+	// Language code
+	if d.HasLanguageCode {
+		if d.LanguageCode, err = i.NextBytes(3); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+	}
+
+	// Private data
+	if i.Offset() < offsetEnd {
+		if d.PrivateData, err = i.NextBytes(offsetEnd - i.Offset()); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+	}
+	return
+}
+
+// DescriptorExtensionT2DeliverySystem represents a T2 delivery system extension descriptor
+// Frequencies are expressed in multiples of 10 Hz
+// Chapter: 6.4.6.3 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorExtensionT2DeliverySystem struct {
+	Bandwidth          uint8
+	Cells              []*DescriptorExtensionT2DeliverySystemCell
+	GuardInterval      uint8
+	HasExtendedInfo    bool // Whether the fields below are present
+	OtherFrequencyFlag bool
+	PLPID              uint8
+	SISOMISO           uint8
+	T2SystemID         uint16
+	TFSFlag            bool
+	TransmissionMode   uint8
+}
+
+// DescriptorExtensionT2DeliverySystemCell represents a T2 delivery system extension descriptor cell
+type DescriptorExtensionT2DeliverySystemCell struct {
+	CellID            uint16
+	CentreFrequencies []uint32 // Only one centre frequency is present when TFS flag is false
+	SubCells          []*DescriptorExtensionT2DeliverySystemSubCell
+}
+
+// DescriptorExtensionT2DeliverySystemSubCell represents a T2 delivery system extension descriptor subcell
+type DescriptorExtensionT2DeliverySystemSubCell struct {
+	CellIDExtension     uint8
+	TransposerFrequency uint32
+}
+
+func newDescriptorExtensionT2DeliverySystem(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorExtensionT2DeliverySystem, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(3); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorExtensionT2DeliverySystem{
+		PLPID:      uint8(bs[0]),
+		T2SystemID: uint16(bs[1])<<8 | uint16(bs[2]),
+	}
+
+	// No extended info
+	if i.Offset() >= offsetEnd {
+		return
+	}
+	d.HasExtendedInfo = true
+
+	// Get next bytes
+	if bs, err = i.NextBytesNoCopy(2); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Update descriptor
+	d.SISOMISO = uint8(bs[0] >> 6)
+	d.Bandwidth = uint8(bs[0] >> 2 & 0xf)
+	d.GuardInterval = uint8(bs[1] >> 5)
+	d.TransmissionMode = uint8(bs[1] >> 2 & 0x7)
+	d.OtherFrequencyFlag = bs[1]&0x2 > 0
+	d.TFSFlag = bs[1]&0x1 > 0
+
+	// Loop through cells
+	for i.Offset() < offsetEnd {
+		// Get next bytes
+		if bs, err = i.NextBytesNoCopy(2); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Create cell
+		c := &DescriptorExtensionT2DeliverySystemCell{CellID: uint16(bs[0])<<8 | uint16(bs[1])}
+
+		// Get frequencies length
+		l := 4
+		if d.TFSFlag {
+			// Get next byte
+			var b byte
+			if b, err = i.NextByte(); err != nil {
+				err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+				return
+			}
+			l = int(b)
+		}
+
+		// Loop through frequencies
+		for offsetFrequenciesEnd := i.Offset() + l; i.Offset() < offsetFrequenciesEnd; {
+			// Get next bytes
+			if bs, err = i.NextBytesNoCopy(4); err != nil {
+				err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+				return
+			}
+
+			// Append frequency
+			c.CentreFrequencies = append(c.CentreFrequencies, uint32(bs[0])<<24|uint32(bs[1])<<16|uint32(bs[2])<<8|uint32(bs[3]))
+		}
+
+		// Get next byte
+		var b byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+
+		// Loop through subcells
+		for offsetSubCellsEnd := i.Offset() + int(b); i.Offset() < offsetSubCellsEnd; {
+			// Get next bytes
+			if bs, err = i.NextBytesNoCopy(5); err != nil {
+				err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+				return
+			}
+
+			// Append subcell
+			c.SubCells = append(c.SubCells, &DescriptorExtensionT2DeliverySystemSubCell{
+				CellIDExtension:     uint8(bs[0]),
+				TransposerFrequency: uint32(bs[1])<<24 | uint32(bs[2])<<16 | uint32(bs[3])<<8 | uint32(bs[4]),
+			})
+		}
+
+		// Append cell
+		d.Cells = append(d.Cells, c)
+	}
+	return
+}
+
+// Frequency list coding types
+const (
+	FrequencyListCodingTypeCable       = 0x2
+	FrequencyListCodingTypeNotDefined  = 0x0
+	FrequencyListCodingTypeSatellite   = 0x1
+	FrequencyListCodingTypeTerrestrial = 0x3
+)
+
+// DescriptorFrequencyList represents a frequency list descriptor
+// Chapter: 6.2.17 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorFrequencyList struct {
+	CodingType  uint8
+	Frequencies []uint32 // Frequencies are encoded as in the delivery system descriptor matching the coding type (BCD for satellite and cable, 10 Hz units for terrestrial)
+}
+
+func newDescriptorFrequencyList(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorFrequencyList, err error) {
+	// Get next byte
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorFrequencyList{CodingType: uint8(b & 0x3)}
+
+	// Loop through frequencies
+	for i.Offset() < offsetEnd {
+		// Get next bytes
+		var bs []byte
+		if bs, err = i.NextBytesNoCopy(4); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Append frequency
+		d.Frequencies = append(d.Frequencies, uint32(bs[0])<<24|uint32(bs[1])<<16|uint32(bs[2])<<8|uint32(bs[3]))
+	}
+	return
+}
+
+// DescriptorHEVCVideo represents an HEVC video descriptor
+// Chapter: 2.6.95 | Link: https://www.itu.int/rec/T-REC-H.222.0
+type DescriptorHEVCVideo struct {
+	Copied44Bits                   uint64
+	FrameOnlyConstraintFlag        bool
+	HDRWCGIDC                      uint8
+	HEVC24HrPicturePresentFlag     bool
+	HEVCStillPresentFlag           bool
+	InterlacedSourceFlag           bool
+	LevelIDC                       uint8
+	NonPackedConstraintFlag        bool
+	ProfileCompatibilityIndication uint32
+	ProfileIDC                     uint8
+	ProfileSpace                   uint8
+	ProgressiveSourceFlag          bool
+	SubPicHRDParamsNotPresentFlag  bool
+	TemporalIDMax                  uint8
+	TemporalIDMin                  uint8
+	TemporalLayerSubsetFlag        bool
+	TierFlag                       bool
+}
+
+func newDescriptorHEVCVideo(i *astikit.BytesIterator) (d *DescriptorHEVCVideo, err error) {
+	// Init
+	d = &DescriptorHEVCVideo{}
+
+	// Get next byte
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Profile
+	d.ProfileSpace = uint8(b >> 6)
+	d.TierFlag = b&0x20 > 0
+	d.ProfileIDC = uint8(b & 0x1f)
+
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(4); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Profile compatibility indication
+	d.ProfileCompatibilityIndication = uint32(bs[0])<<24 | uint32(bs[1])<<16 | uint32(bs[2])<<8 | uint32(bs[3])
+
+	// Get next bytes
+	if bs, err = i.NextBytesNoCopy(6); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Flags
+	d.ProgressiveSourceFlag = bs[0]&0x80 > 0
+	d.InterlacedSourceFlag = bs[0]&0x40 > 0
+	d.NonPackedConstraintFlag = bs[0]&0x20 > 0
+	d.FrameOnlyConstraintFlag = bs[0]&0x10 > 0
+
+	// Copied 44 bits
+	d.Copied44Bits = uint64(bs[0]&0xf)<<40 | uint64(bs[1])<<32 | uint64(bs[2])<<24 | uint64(bs[3])<<16 | uint64(bs[4])<<8 | uint64(bs[5])
+
+	// Get next byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Level idc
+	d.LevelIDC = uint8(b)
+
+	// Get next byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Flags
+	d.TemporalLayerSubsetFlag = b&0x80 > 0
+	d.HEVCStillPresentFlag = b&0x40 > 0
+	d.HEVC24HrPicturePresentFlag = b&0x20 > 0
+	d.SubPicHRDParamsNotPresentFlag = b&0x10 > 0
+	d.HDRWCGIDC = uint8(b & 0x3)
+
+	// Temporal layer subset
+	if d.TemporalLayerSubsetFlag {
+		// Get next bytes
+		if bs, err = i.NextBytesNoCopy(2); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+		d.TemporalIDMin = uint8(bs[0] & 0x7)
+		d.TemporalIDMax = uint8(bs[1] & 0x7)
+	}
+	return
+}
+
+// DescriptorHierarchy represents a hierarchy descriptor
+// Chapter: 2.6.6 | Link: https://www.itu.int/rec/T-REC-H.222.0
+type DescriptorHierarchy struct {
+	HierarchyChannel            uint8
+	HierarchyEmbeddedLayerIndex uint8
+	HierarchyLayerIndex         uint8
+	HierarchyType               uint8
+	NoQualityScalabilityFlag    bool
+	NoSpatialScalabilityFlag    bool
+	NoTemporalScalabilityFlag   bool
+	NoViewScalabilityFlag       bool
+	TREFPresentFlag             bool
+}
+
+func newDescriptorHierarchy(i *astikit.BytesIterator) (d *DescriptorHierarchy, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(4); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorHierarchy{
+		HierarchyChannel:            uint8(bs[3] & 0x3f),
+		HierarchyEmbeddedLayerIndex: uint8(bs[2] & 0x3f),
+		HierarchyLayerIndex:         uint8(bs[1] & 0x3f),
+		HierarchyType:               uint8(bs[0] & 0xf),
+		NoQualityScalabilityFlag:    bs[0]&0x10 > 0,
+		NoSpatialScalabilityFlag:    bs[0]&0x20 > 0,
+		NoTemporalScalabilityFlag:   bs[0]&0x40 > 0,
+		NoViewScalabilityFlag:       bs[0]&0x80 > 0,
+		TREFPresentFlag:             bs[2]&0x80 > 0,
+	}
+	return
+}
+
+// DescriptorISO639LanguageAndAudioType represents an ISO639 language descriptor
+// Chapter: 2.6.18 | Link: https://www.itu.int/rec/T-REC-H.222.0
+type DescriptorISO639LanguageAndAudioType struct {
+	Items []*DescriptorISO639LanguageAndAudioTypeItem
+}
+
+// DescriptorISO639LanguageAndAudioTypeItem represents an ISO639 language descriptor item
+// Chapter: 2.6.18 | Link: https://www.itu.int/rec/T-REC-H.222.0
+type DescriptorISO639LanguageAndAudioTypeItem struct {
+	Language []byte
+	Type     uint8
+}
+
+// In some actual cases, the length is 3 and the language is described in only 2 bytes
+func newDescriptorISO639LanguageAndAudioType(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorISO639LanguageAndAudioType, err error) {
+	// Create descriptor
+	d = &DescriptorISO639LanguageAndAudioType{}
+
+	// Loop
+	for i.Offset() < offsetEnd {
+		// Create item
+		itm := &DescriptorISO639LanguageAndAudioTypeItem{}
+
+		// Remaining bytes are too few for a full item, in which case the last byte is the type
+		l := 3
+		if offsetEnd-i.Offset() < 4 {
+			l = offsetEnd - i.Offset() - 1
+		}
+
+		// Language
+		if itm.Language, err = i.NextBytes(l); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Get next byte
+		var b byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+
+		// Type
+		itm.Type = uint8(b)
+
+		// Append item
+		d.Items = append(d.Items, itm)
+	}
+	return
+}
+
+// DescriptorLocalTimeOffset represents a local time offset descriptor
+// Chapter: 6.2.20 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorLocalTimeOffset struct {
+	Items []*DescriptorLocalTimeOffsetItem
+}
+
+// DescriptorLocalTimeOffsetItem represents a local time offset item descriptor
+// Chapter: 6.2.20 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorLocalTimeOffsetItem struct {
+	CountryCode             []byte
 	CountryRegionID         uint8
 	LocalTimeOffset         time.Duration
 	LocalTimeOffsetPolarity bool
@@ -799,7 +2022,69 @@ func newDescriptorMaximumBitrate(i *astikit.BytesIterator) (d *DescriptorMaximum
 	}
 
 	// Create descriptor
-	d = &DescriptorMaximumBitrate{Bitrate: (uint32(bs[0]&0x3f)<<16 | uint32(bs[1])<<8 | uint32(bs[2])) * 50}
+	d = &DescriptorMaximumBitrate{Bitrate: (uint32(bs[0]&0x3f)<<16 | uint32(bs[1])<<8 | uint32(bs[2])) * 50}
+	return
+}
+
+// DescriptorMultiplexBufferUtilization represents a multiplex buffer utilization descriptor
+// Chapter: 2.6.22 | Link: https://www.itu.int/rec/T-REC-H.222.0
+type DescriptorMultiplexBufferUtilization struct {
+	BoundValidFlag      bool
+	LTWOffsetLowerBound uint16 // In (27 MHz/300) clock periods
+	LTWOffsetUpperBound uint16 // In (27 MHz/300) clock periods
+}
+
+func newDescriptorMultiplexBufferUtilization(i *astikit.BytesIterator) (d *DescriptorMultiplexBufferUtilization, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(4); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorMultiplexBufferUtilization{
+		BoundValidFlag:      bs[0]&0x80 > 0,
+		LTWOffsetLowerBound: uint16(bs[0]&0x7f)<<8 | uint16(bs[1]),
+		LTWOffsetUpperBound: uint16(bs[2]&0x7f)<<8 | uint16(bs[3]),
+	}
+	return
+}
+
+// DescriptorNVODReference represents an NVOD reference descriptor
+// Chapter: 6.2.26 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorNVODReference struct {
+	Items []*DescriptorNVODReferenceItem
+}
+
+// DescriptorNVODReferenceItem represents an NVOD reference descriptor item
+// Chapter: 6.2.26 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorNVODReferenceItem struct {
+	OriginalNetworkID uint16
+	ServiceID         uint16
+	TransportStreamID uint16
+}
+
+func newDescriptorNVODReference(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorNVODReference, err error) {
+	// Create descriptor
+	d = &DescriptorNVODReference{}
+
+	// Loop until end of descriptor is reached
+	for i.Offset() < offsetEnd {
+		// Get next bytes
+		var bs []byte
+		if bs, err = i.NextBytesNoCopy(6); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Append item
+		d.Items = append(d.Items, &DescriptorNVODReferenceItem{
+			OriginalNetworkID: uint16(bs[2])<<8 | uint16(bs[3]),
+			ServiceID:         uint16(bs[4])<<8 | uint16(bs[5]),
+			TransportStreamID: uint16(bs[0])<<8 | uint16(bs[1]),
+		})
+	}
 	return
 }
 
@@ -821,6 +2106,25 @@ func newDescriptorNetworkName(i *astikit.BytesIterator, offsetEnd int) (d *Descr
 	return
 }
 
+// DescriptorPDC represents a PDC descriptor
+// Chapter: 6.2.30 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorPDC struct {
+	ProgrammeIdentificationLabel uint32 // Day, month, hour and minute coded on 5, 4, 5 and 6 bits
+}
+
+func newDescriptorPDC(i *astikit.BytesIterator) (d *DescriptorPDC, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(3); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorPDC{ProgrammeIdentificationLabel: uint32(bs[0]&0xf)<<16 | uint32(bs[1])<<8 | uint32(bs[2])}
+	return
+}
+
 // DescriptorParentalRating represents a parental rating descriptor
 // Chapter: 6.2.28 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
 type DescriptorParentalRating struct {
@@ -865,6 +2169,32 @@ func newDescriptorParentalRating(i *astikit.BytesIterator, offsetEnd int) (d *De
 	return
 }
 
+// DescriptorPartialTransportStream represents a partial transport stream descriptor, which is carried by the SIT of
+// recordings
+// Chapter: 7.2.1 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorPartialTransportStream struct {
+	MaximumOverallSmoothingBuffer uint16 // In bytes, 0x3fff means undefined
+	MinimumOverallSmoothingRate   uint32 // In bits/second, 0x3fffff * 400 means undefined
+	PeakRate                      uint32 // In bits/second
+}
+
+func newDescriptorPartialTransportStream(i *astikit.BytesIterator) (d *DescriptorPartialTransportStream, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(8); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorPartialTransportStream{
+		MaximumOverallSmoothingBuffer: uint16(bs[6]&0x3f)<<8 | uint16(bs[7]),
+		MinimumOverallSmoothingRate:   (uint32(bs[3]&0x3f)<<16 | uint32(bs[4])<<8 | uint32(bs[5])) * 400,
+		PeakRate:                      (uint32(bs[0]&0x3f)<<16 | uint32(bs[1])<<8 | uint32(bs[2])) * 400,
+	}
+	return
+}
+
 // DescriptorPrivateDataIndicator represents a private data Indicator descriptor
 type DescriptorPrivateDataIndicator struct {
 	Indicator uint32
@@ -929,6 +2259,25 @@ func newDescriptorRegistration(i *astikit.BytesIterator, offsetEnd int) (d *Desc
 	return
 }
 
+// DescriptorSTD represents a system target decoder descriptor
+// Chapter: 2.6.32 | Link: https://www.itu.int/rec/T-REC-H.222.0
+type DescriptorSTD struct {
+	LeakValidFlag bool
+}
+
+func newDescriptorSTD(i *astikit.BytesIterator) (d *DescriptorSTD, err error) {
+	// Get next byte
+	var b byte
+	if b, err = i.NextByte(); err != nil {
+		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorSTD{LeakValidFlag: b&0x1 > 0}
+	return
+}
+
 // DescriptorService represents a service descriptor
 // Chapter: 6.2.33 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
 type DescriptorService struct {
@@ -937,7 +2286,7 @@ type DescriptorService struct {
 	Type     uint8
 }
 
-func newDescriptorService(i *astikit.BytesIterator) (d *DescriptorService, err error) {
+func newDescriptorService(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorService, err error) {
 	// Get next byte
 	var b byte
 	if b, err = i.NextByte(); err != nil {
@@ -958,11 +2307,16 @@ func newDescriptorService(i *astikit.BytesIterator) (d *DescriptorService, err e
 	providerLength := int(b)
 
 	// Provider
-	if d.Provider, err = i.NextBytes(providerLength); err != nil {
+	if d.Provider, err = nextBytesUntil(i, providerLength, offsetEnd); err != nil {
 		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
 		return
 	}
 
+	// No name
+	if i.Offset() >= offsetEnd {
+		return
+	}
+
 	// Get next byte
 	if b, err = i.NextByte(); err != nil {
 		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
@@ -973,10 +2327,54 @@ func newDescriptorService(i *astikit.BytesIterator) (d *DescriptorService, err e
 	nameLength := int(b)
 
 	// Name
-	if d.Name, err = i.NextBytes(nameLength); err != nil {
+	if d.Name, err = nextBytesUntil(i, nameLength, offsetEnd); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+	return
+}
+
+// DescriptorServiceLocation represents an ATSC service location descriptor
+// Chapter: 6.9.5 | Link: https://www.atsc.org/wp-content/uploads/2021/04/A65_2013.pdf
+type DescriptorServiceLocation struct {
+	Elements []*DescriptorServiceLocationElement
+	PCRPID   uint16
+}
+
+// DescriptorServiceLocationElement represents an ATSC service location descriptor element
+type DescriptorServiceLocationElement struct {
+	ElementaryPID uint16
+	Language      []byte
+	StreamType    StreamType
+}
+
+func newDescriptorServiceLocation(i *astikit.BytesIterator) (d *DescriptorServiceLocation, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(3); err != nil {
 		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
 		return
 	}
+
+	// Create descriptor
+	d = &DescriptorServiceLocation{PCRPID: uint16(bs[0]&0x1f)<<8 | uint16(bs[1])}
+
+	// Loop through elements
+	for idx := 0; idx < int(bs[2]); idx++ {
+		// Get next bytes
+		var ebs []byte
+		if ebs, err = i.NextBytes(6); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Append element
+		d.Elements = append(d.Elements, &DescriptorServiceLocationElement{
+			ElementaryPID: uint16(ebs[1]&0x1f)<<8 | uint16(ebs[2]),
+			Language:      ebs[3:6],
+			StreamType:    StreamType(ebs[0]),
+		})
+	}
 	return
 }
 
@@ -988,7 +2386,7 @@ type DescriptorShortEvent struct {
 	Text      []byte
 }
 
-func newDescriptorShortEvent(i *astikit.BytesIterator) (d *DescriptorShortEvent, err error) {
+func newDescriptorShortEvent(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorShortEvent, err error) {
 	// Create descriptor
 	d = &DescriptorShortEvent{}
 
@@ -1009,11 +2407,16 @@ func newDescriptorShortEvent(i *astikit.BytesIterator) (d *DescriptorShortEvent,
 	eventLength := int(b)
 
 	// Event name
-	if d.EventName, err = i.NextBytes(eventLength); err != nil {
+	if d.EventName, err = nextBytesUntil(i, eventLength, offsetEnd); err != nil {
 		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
 		return
 	}
 
+	// No text
+	if i.Offset() >= offsetEnd {
+		return
+	}
+
 	// Get next byte
 	if b, err = i.NextByte(); err != nil {
 		err = fmt.Errorf("astits: fetching next byte failed: %w", err)
@@ -1024,10 +2427,33 @@ func newDescriptorShortEvent(i *astikit.BytesIterator) (d *DescriptorShortEvent,
 	textLength := int(b)
 
 	// Text
-	if d.Text, err = i.NextBytes(textLength); err != nil {
+	if d.Text, err = nextBytesUntil(i, textLength, offsetEnd); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+	return
+}
+
+// DescriptorSmoothingBuffer represents a smoothing buffer descriptor
+// Chapter: 2.6.30 | Link: https://www.itu.int/rec/T-REC-H.222.0
+type DescriptorSmoothingBuffer struct {
+	SBLeakRate uint32 // In bits/second
+	SBSize     uint32 // In bytes
+}
+
+func newDescriptorSmoothingBuffer(i *astikit.BytesIterator) (d *DescriptorSmoothingBuffer, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(6); err != nil {
 		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
 		return
 	}
+
+	// Create descriptor
+	d = &DescriptorSmoothingBuffer{
+		SBLeakRate: (uint32(bs[0]&0x3f)<<16 | uint32(bs[1])<<8 | uint32(bs[2])) * 400,
+		SBSize:     uint32(bs[3]&0x3f)<<16 | uint32(bs[4])<<8 | uint32(bs[5]),
+	}
 	return
 }
 
@@ -1047,6 +2473,24 @@ func newDescriptorStreamIdentifier(i *astikit.BytesIterator) (d *DescriptorStrea
 	return
 }
 
+// DescriptorStuffing represents a stuffing descriptor, whose content is opaque
+// Chapter: 6.2.40 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorStuffing struct {
+	Data []byte
+}
+
+func newDescriptorStuffing(i *astikit.BytesIterator, offsetEnd int) (d *DescriptorStuffing, err error) {
+	// Create descriptor
+	d = &DescriptorStuffing{}
+
+	// Get next bytes
+	if d.Data, err = i.NextBytes(offsetEnd - i.Offset()); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+	return
+}
+
 // DescriptorSubtitling represents a subtitling descriptor
 // Chapter: 6.2.41 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
 type DescriptorSubtitling struct {
@@ -1112,6 +2556,76 @@ func newDescriptorSubtitling(i *astikit.BytesIterator, offsetEnd int) (d *Descri
 	return
 }
 
+// DescriptorTargetBackgroundGrid represents a target background grid descriptor
+// Chapter: 2.6.12 | Link: https://www.itu.int/rec/T-REC-H.222.0
+type DescriptorTargetBackgroundGrid struct {
+	AspectRatioInformation uint8
+	HorizontalSize         uint16
+	VerticalSize           uint16
+}
+
+func newDescriptorTargetBackgroundGrid(i *astikit.BytesIterator) (d *DescriptorTargetBackgroundGrid, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(4); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorTargetBackgroundGrid{
+		AspectRatioInformation: uint8(bs[3] & 0xf),
+		HorizontalSize:         uint16(bs[0])<<6 | uint16(bs[1]>>2),
+		VerticalSize:           uint16(bs[1]&0x3)<<12 | uint16(bs[2])<<4 | uint16(bs[3]>>4),
+	}
+	return
+}
+
+// DescriptorTelephone represents a telephone descriptor
+// Chapter: 6.2.42 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorTelephone struct {
+	ConnectionType        uint8
+	CoreNumber            []byte
+	CountryPrefix         []byte
+	ForeignAvailability   bool
+	InternationalAreaCode []byte
+	NationalAreaCode      []byte
+	OperatorCode          []byte
+}
+
+func newDescriptorTelephone(i *astikit.BytesIterator) (d *DescriptorTelephone, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(3); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorTelephone{
+		ConnectionType:      uint8(bs[0] & 0x1f),
+		ForeignAvailability: bs[0]&0x20 > 0,
+	}
+
+	// Loop through numbers
+	for _, n := range []struct {
+		length int
+		v      *[]byte
+	}{
+		{length: int((bs[1] >> 5) & 0x3), v: &d.CountryPrefix},
+		{length: int((bs[1] >> 2) & 0x7), v: &d.InternationalAreaCode},
+		{length: int(bs[1] & 0x3), v: &d.OperatorCode},
+		{length: int((bs[2] >> 4) & 0x7), v: &d.NationalAreaCode},
+		{length: int(bs[2] & 0xf), v: &d.CoreNumber},
+	} {
+		if *n.v, err = i.NextBytes(n.length); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+	}
+	return
+}
+
 // DescriptorTeletext represents a teletext descriptor
 // Chapter: 6.2.43 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
 type DescriptorTeletext struct {
@@ -1162,7 +2676,7 @@ func newDescriptorTeletext(i *astikit.BytesIterator, offsetEnd int) (d *Descript
 		}
 
 		// Page
-		itm.Page = uint8(b)>>4*10 + uint8(b&0xf)
+		itm.Page = uint8(b>>4)*10 + uint8(b&0xf)
 
 		// Append item
 		d.Items = append(d.Items, itm)
@@ -1187,6 +2701,48 @@ func newDescriptorUnknown(i *astikit.BytesIterator, tag, length uint8) (d *Descr
 	return
 }
 
+// DescriptorTimeShiftedEvent represents a time shifted event descriptor
+// Chapter: 6.2.44 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorTimeShiftedEvent struct {
+	ReferenceEventID   uint16
+	ReferenceServiceID uint16
+}
+
+func newDescriptorTimeShiftedEvent(i *astikit.BytesIterator) (d *DescriptorTimeShiftedEvent, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(4); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorTimeShiftedEvent{
+		ReferenceEventID:   uint16(bs[2])<<8 | uint16(bs[3]),
+		ReferenceServiceID: uint16(bs[0])<<8 | uint16(bs[1]),
+	}
+	return
+}
+
+// DescriptorTimeShiftedService represents a time shifted service descriptor
+// Chapter: 6.2.45 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
+type DescriptorTimeShiftedService struct {
+	ReferenceServiceID uint16
+}
+
+func newDescriptorTimeShiftedService(i *astikit.BytesIterator) (d *DescriptorTimeShiftedService, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(2); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorTimeShiftedService{ReferenceServiceID: uint16(bs[0])<<8 | uint16(bs[1])}
+	return
+}
+
 // DescriptorVBIData represents a VBI data descriptor
 // Chapter: 6.2.47 | Link: https://www.etsi.org/deliver/etsi_en/300400_300499/300468/01.15.01_60/en_300468v011501p.pdf
 type DescriptorVBIData struct {
@@ -1265,6 +2821,51 @@ func newDescriptorVBIData(i *astikit.BytesIterator, offsetEnd int) (d *Descripto
 	return
 }
 
+// DescriptorVideoWindow represents a video window descriptor
+// Chapter: 2.6.14 | Link: https://www.itu.int/rec/T-REC-H.222.0
+type DescriptorVideoWindow struct {
+	HorizontalOffset uint16
+	VerticalOffset   uint16
+	WindowPriority   uint8
+}
+
+func newDescriptorVideoWindow(i *astikit.BytesIterator) (d *DescriptorVideoWindow, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(4); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create descriptor
+	d = &DescriptorVideoWindow{
+		HorizontalOffset: uint16(bs[0])<<6 | uint16(bs[1]>>2),
+		VerticalOffset:   uint16(bs[1]&0x3)<<12 | uint16(bs[2])<<4 | uint16(bs[3]>>4),
+		WindowPriority:   uint8(bs[3] & 0xf),
+	}
+	return
+}
+
+// FindDescriptor returns the first descriptor with the provided tag, if any
+func FindDescriptor(ds []*Descriptor, tag uint8) *Descriptor {
+	for _, d := range ds {
+		if d.Tag == tag {
+			return d
+		}
+	}
+	return nil
+}
+
+// FindDescriptors returns all the descriptors with the provided tag
+func FindDescriptors(ds []*Descriptor, tag uint8) (o []*Descriptor) {
+	for _, d := range ds {
+		if d.Tag == tag {
+			o = append(o, d)
+		}
+	}
+	return
+}
+
 // parseDescriptors parses descriptors
 func parseDescriptors(i *astikit.BytesIterator) (o []*Descriptor, err error) {
 	// Get next 2 bytes
@@ -1279,165 +2880,301 @@ func parseDescriptors(i *astikit.BytesIterator) (o []*Descriptor, err error) {
 
 	// Loop
 	if length > 0 {
-		offsetEnd := i.Offset() + length
-		for i.Offset() < offsetEnd {
-			// Get next 2 bytes
-			if bs, err = i.NextBytesNoCopy(2); err != nil {
-				err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
-				return
-			}
+		if o, err = parseDescriptorsUntil(i, i.Offset()+length); err != nil {
+			err = fmt.Errorf("astits: parsing descriptors until offset failed: %w", err)
+			return
+		}
+	}
+	return
+}
 
-			// Create descriptor
-			d := &Descriptor{
-				Length: uint8(bs[1]),
-				Tag:    uint8(bs[0]),
-			}
+// nextBytesUntil fetches the provided number of bytes without going past the provided offset, which protects
+// nested reads against inner lengths lying about the content of the descriptor
+func nextBytesUntil(i *astikit.BytesIterator, n, offsetEnd int) ([]byte, error) {
+	if max := offsetEnd - i.Offset(); n > max {
+		n = max
+	}
+	if n < 0 {
+		n = 0
+	}
+	return i.NextBytes(n)
+}
+
+// parseDescriptorsUntil parses descriptors until the provided offset is reached
+func parseDescriptorsUntil(i *astikit.BytesIterator, offsetEnd int) (o []*Descriptor, err error) {
+	var bs []byte
+	for i.Offset() < offsetEnd {
+		// Get next 2 bytes
+		if bs, err = i.NextBytesNoCopy(2); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
 
-			// Parse data
-			if d.Length > 0 {
-				// Unfortunately there's no way to be sure the real descriptor length is the same as the one indicated
-				// previously therefore we must fetch bytes in descriptor functions and seek at the end
-				offsetDescriptorEnd := i.Offset() + int(d.Length)
-
-				// User defined
-				if d.Tag >= 0x80 && d.Tag <= 0xfe {
-					// Get next bytes
-					if d.UserDefined, err = i.NextBytes(int(d.Length)); err != nil {
-						err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		// Create descriptor
+		d := &Descriptor{
+			Length: uint8(bs[1]),
+			Tag:    uint8(bs[0]),
+		}
+
+		// Parse data
+		if d.Length > 0 {
+			// Unfortunately there's no way to be sure the real descriptor length is the same as the one indicated
+			// previously therefore we must fetch bytes in descriptor functions and seek at the end
+			offsetDescriptorEnd := i.Offset() + int(d.Length)
+
+			// User defined
+			if d.Tag >= 0x80 && d.Tag <= 0xfe {
+				// Get next bytes
+				if d.UserDefined, err = i.NextBytes(int(d.Length)); err != nil {
+					err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+					return
+				}
+			} else {
+				// Switch on tag
+				switch d.Tag {
+				case DescriptorTagAAC:
+					if d.AAC, err = newDescriptorAAC(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing AAC descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagAC3:
+					if d.AC3, err = newDescriptorAC3(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing AC3 descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagAVCVideo:
+					if d.AVCVideo, err = newDescriptorAVCVideo(i); err != nil {
+						err = fmt.Errorf("astits: parsing AVC Video descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagAdaptationFieldData:
+					if d.AdaptationFieldData, err = newDescriptorAdaptationFieldData(i); err != nil {
+						err = fmt.Errorf("astits: parsing adaptation field data descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagAnnouncementSupport:
+					if d.AnnouncementSupport, err = newDescriptorAnnouncementSupport(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Announcement Support descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagCA:
+					if d.CA, err = newDescriptorCA(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing CA descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagComponent:
+					if d.Component, err = newDescriptorComponent(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Component descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagContent:
+					if d.Content, err = newDescriptorContent(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Content descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagContentIdentifier:
+					if d.ContentIdentifier, err = newDescriptorContentIdentifier(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Content Identifier descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagDataBroadcast:
+					if d.DataBroadcast, err = newDescriptorDataBroadcast(i); err != nil {
+						err = fmt.Errorf("astits: parsing Data Broadcast descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagDataBroadcastID:
+					if d.DataBroadcastID, err = newDescriptorDataBroadcastID(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Data Broadcast ID descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagDataStreamAlignment:
+					if d.DataStreamAlignment, err = newDescriptorDataStreamAlignment(i); err != nil {
+						err = fmt.Errorf("astits: parsing Data Stream Alignment descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagDefaultAuthority:
+					if d.DefaultAuthority, err = newDescriptorDefaultAuthority(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Default Authority descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagEnhancedAC3:
+					if d.EnhancedAC3, err = newDescriptorEnhancedAC3(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Enhanced AC3 descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagExtendedEvent:
+					if d.ExtendedEvent, err = newDescriptorExtendedEvent(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Extended event descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagExtension:
+					if d.Extension, err = newDescriptorExtension(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Extension descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagFrequencyList:
+					if d.FrequencyList, err = newDescriptorFrequencyList(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing frequency list descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagHEVCVideo:
+					if d.HEVCVideo, err = newDescriptorHEVCVideo(i); err != nil {
+						err = fmt.Errorf("astits: parsing HEVC Video descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagHierarchy:
+					if d.Hierarchy, err = newDescriptorHierarchy(i); err != nil {
+						err = fmt.Errorf("astits: parsing Hierarchy descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagISO639LanguageAndAudioType:
+					if d.ISO639LanguageAndAudioType, err = newDescriptorISO639LanguageAndAudioType(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing ISO639 Language and Audio Type descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagLocalTimeOffset:
+					if d.LocalTimeOffset, err = newDescriptorLocalTimeOffset(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Local Time Offset descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagMaximumBitrate:
+					if d.MaximumBitrate, err = newDescriptorMaximumBitrate(i); err != nil {
+						err = fmt.Errorf("astits: parsing Maximum Bitrate descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagMultiplexBufferUtilization:
+					if d.MultiplexBufferUtilization, err = newDescriptorMultiplexBufferUtilization(i); err != nil {
+						err = fmt.Errorf("astits: parsing Multiplex Buffer Utilization descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagNVODReference:
+					if d.NVODReference, err = newDescriptorNVODReference(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing NVOD Reference descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagNetworkName:
+					if d.NetworkName, err = newDescriptorNetworkName(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Network Name descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagPDC:
+					if d.PDC, err = newDescriptorPDC(i); err != nil {
+						err = fmt.Errorf("astits: parsing PDC descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagParentalRating:
+					if d.ParentalRating, err = newDescriptorParentalRating(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Parental Rating descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagPartialTransportStream:
+					if d.PartialTransportStream, err = newDescriptorPartialTransportStream(i); err != nil {
+						err = fmt.Errorf("astits: parsing Partial Transport Stream descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagPrivateDataIndicator:
+					if d.PrivateDataIndicator, err = newDescriptorPrivateDataIndicator(i); err != nil {
+						err = fmt.Errorf("astits: parsing Private Data Indicator descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagPrivateDataSpecifier:
+					if d.PrivateDataSpecifier, err = newDescriptorPrivateDataSpecifier(i); err != nil {
+						err = fmt.Errorf("astits: parsing Private Data Specifier descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagRegistration:
+					if d.Registration, err = newDescriptorRegistration(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Registration descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagSTD:
+					if d.STD, err = newDescriptorSTD(i); err != nil {
+						err = fmt.Errorf("astits: parsing STD descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagService:
+					if d.Service, err = newDescriptorService(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Service descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagShortEvent:
+					if d.ShortEvent, err = newDescriptorShortEvent(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Short Event descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagSmoothingBuffer:
+					if d.SmoothingBuffer, err = newDescriptorSmoothingBuffer(i); err != nil {
+						err = fmt.Errorf("astits: parsing Smoothing Buffer descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagStreamIdentifier:
+					if d.StreamIdentifier, err = newDescriptorStreamIdentifier(i); err != nil {
+						err = fmt.Errorf("astits: parsing Stream Identifier descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagStuffing:
+					if d.Stuffing, err = newDescriptorStuffing(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing stuffing descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagSubtitling:
+					if d.Subtitling, err = newDescriptorSubtitling(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Subtitling descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagTargetBackgroundGrid:
+					if d.TargetBackgroundGrid, err = newDescriptorTargetBackgroundGrid(i); err != nil {
+						err = fmt.Errorf("astits: parsing Target Background Grid descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagTelephone:
+					if d.Telephone, err = newDescriptorTelephone(i); err != nil {
+						err = fmt.Errorf("astits: parsing Telephone descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagTeletext:
+					if d.Teletext, err = newDescriptorTeletext(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing Teletext descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagTimeShiftedEvent:
+					if d.TimeShiftedEvent, err = newDescriptorTimeShiftedEvent(i); err != nil {
+						err = fmt.Errorf("astits: parsing Time Shifted Event descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagTimeShiftedService:
+					if d.TimeShiftedService, err = newDescriptorTimeShiftedService(i); err != nil {
+						err = fmt.Errorf("astits: parsing Time Shifted Service descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagVBIData:
+					if d.VBIData, err = newDescriptorVBIData(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing VBI Date descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagVBITeletext:
+					if d.VBITeletext, err = newDescriptorTeletext(i, offsetDescriptorEnd); err != nil {
+						err = fmt.Errorf("astits: parsing VBI Teletext descriptor failed: %w", err)
+						return
+					}
+				case DescriptorTagVideoWindow:
+					if d.VideoWindow, err = newDescriptorVideoWindow(i); err != nil {
+						err = fmt.Errorf("astits: parsing Video Window descriptor failed: %w", err)
 						return
 					}
-				} else {
-					// Switch on tag
-					switch d.Tag {
-					case DescriptorTagAC3:
-						if d.AC3, err = newDescriptorAC3(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing AC3 descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagAVCVideo:
-						if d.AVCVideo, err = newDescriptorAVCVideo(i); err != nil {
-							err = fmt.Errorf("astits: parsing AVC Video descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagComponent:
-						if d.Component, err = newDescriptorComponent(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing Component descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagContent:
-						if d.Content, err = newDescriptorContent(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing Content descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagDataStreamAlignment:
-						if d.DataStreamAlignment, err = newDescriptorDataStreamAlignment(i); err != nil {
-							err = fmt.Errorf("astits: parsing Data Stream Alignment descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagEnhancedAC3:
-						if d.EnhancedAC3, err = newDescriptorEnhancedAC3(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing Enhanced AC3 descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagExtendedEvent:
-						if d.ExtendedEvent, err = newDescriptorExtendedEvent(i); err != nil {
-							err = fmt.Errorf("astits: parsing Extended event descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagExtension:
-						if d.Extension, err = newDescriptorExtension(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing Extension descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagISO639LanguageAndAudioType:
-						if d.ISO639LanguageAndAudioType, err = newDescriptorISO639LanguageAndAudioType(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing ISO639 Language and Audio Type descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagLocalTimeOffset:
-						if d.LocalTimeOffset, err = newDescriptorLocalTimeOffset(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing Local Time Offset descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagMaximumBitrate:
-						if d.MaximumBitrate, err = newDescriptorMaximumBitrate(i); err != nil {
-							err = fmt.Errorf("astits: parsing Maximum Bitrate descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagNetworkName:
-						if d.NetworkName, err = newDescriptorNetworkName(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing Network Name descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagParentalRating:
-						if d.ParentalRating, err = newDescriptorParentalRating(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing Parental Rating descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagPrivateDataIndicator:
-						if d.PrivateDataIndicator, err = newDescriptorPrivateDataIndicator(i); err != nil {
-							err = fmt.Errorf("astits: parsing Private Data Indicator descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagPrivateDataSpecifier:
-						if d.PrivateDataSpecifier, err = newDescriptorPrivateDataSpecifier(i); err != nil {
-							err = fmt.Errorf("astits: parsing Private Data Specifier descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagRegistration:
-						if d.Registration, err = newDescriptorRegistration(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing Registration descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagService:
-						if d.Service, err = newDescriptorService(i); err != nil {
-							err = fmt.Errorf("astits: parsing Service descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagShortEvent:
-						if d.ShortEvent, err = newDescriptorShortEvent(i); err != nil {
-							err = fmt.Errorf("astits: parsing Short Event descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagStreamIdentifier:
-						if d.StreamIdentifier, err = newDescriptorStreamIdentifier(i); err != nil {
-							err = fmt.Errorf("astits: parsing Stream Identifier descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagSubtitling:
-						if d.Subtitling, err = newDescriptorSubtitling(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing Subtitling descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagTeletext:
-						if d.Teletext, err = newDescriptorTeletext(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing Teletext descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagVBIData:
-						if d.VBIData, err = newDescriptorVBIData(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing VBI Date descriptor failed: %w", err)
-							return
-						}
-					case DescriptorTagVBITeletext:
-						if d.VBITeletext, err = newDescriptorTeletext(i, offsetDescriptorEnd); err != nil {
-							err = fmt.Errorf("astits: parsing VBI Teletext descriptor failed: %w", err)
-							return
-						}
-					default:
-						if d.Unknown, err = newDescriptorUnknown(i, d.Tag, d.Length); err != nil {
-							err = fmt.Errorf("astits: parsing unknown descriptor failed: %w", err)
-							return
-						}
+				default:
+					if d.Unknown, err = newDescriptorUnknown(i, d.Tag, d.Length); err != nil {
+						err = fmt.Errorf("astits: parsing unknown descriptor failed: %w", err)
+						return
 					}
 				}
-
-				// Seek in iterator to make sure we move to the end of the descriptor since its content may be
-				// corrupted
-				i.Seek(offsetDescriptorEnd)
 			}
-			o = append(o, d)
+
+			// Seek in iterator to make sure we move to the end of the descriptor since its content may be
+			// corrupted
+			i.Seek(offsetDescriptorEnd)
 		}
+		o = append(o, d)
 	}
 	return
 }
@@ -1457,6 +3194,42 @@ func writeDescriptorUserDefined(w *astikit.BitsWriter, d []byte) error {
 	return b.Err()
 }
 
+func calcDescriptorAACLength(d *DescriptorAAC) uint8 {
+	if d == nil {
+		return 0
+	}
+
+	ret := 1 // profile and level
+
+	if d.HasAACType || len(d.AdditionalInfo) > 0 {
+		ret++ // flags
+	}
+	if d.HasAACType {
+		ret++
+	}
+	ret += len(d.AdditionalInfo)
+
+	return uint8(ret)
+}
+
+func writeDescriptorAAC(w *astikit.BitsWriter, d *DescriptorAAC) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.ProfileAndLevel)
+
+	if d.HasAACType || len(d.AdditionalInfo) > 0 {
+		b.Write(d.HasAACType)
+		b.WriteN(uint8(0xff), 7)
+
+		if d.HasAACType {
+			b.Write(d.AACType)
+		}
+		b.Write(d.AdditionalInfo)
+	}
+
+	return b.Err()
+}
+
 func calcDescriptorAC3Length(d *DescriptorAC3) uint8 {
 	if d == nil {
 		return 0
@@ -1533,6 +3306,104 @@ func writeDescriptorAVCVideo(w *astikit.BitsWriter, d *DescriptorAVCVideo) error
 	return b.Err()
 }
 
+func calcDescriptorAdaptationFieldDataLength(d *DescriptorAdaptationFieldData) uint8 {
+	if d == nil {
+		return 0
+	}
+	return 1
+}
+
+func writeDescriptorAdaptationFieldData(w *astikit.BitsWriter, d *DescriptorAdaptationFieldData) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.Flags)
+
+	return b.Err()
+}
+
+func calcDescriptorAnnouncementSupportLength(d *DescriptorAnnouncementSupport) uint8 {
+	if d == nil {
+		return 0
+	}
+
+	ret := 2 // announcement support indicator
+	for _, a := range d.Announcements {
+		ret++ // announcement type and reference type
+		if a.hasReference() {
+			ret += 7
+		}
+	}
+	return uint8(ret)
+}
+
+func writeDescriptorAnnouncementSupport(w *astikit.BitsWriter, d *DescriptorAnnouncementSupport) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.AnnouncementSupportIndicator)
+	for _, a := range d.Announcements {
+		b.WriteN(a.AnnouncementType, 4)
+		b.Write(true) // reserved for future use
+		b.WriteN(a.ReferenceType, 3)
+		if a.hasReference() {
+			b.Write(a.OriginalNetworkID)
+			b.Write(a.TransportStreamID)
+			b.Write(a.ServiceID)
+			b.Write(a.ComponentTag)
+		}
+	}
+
+	return b.Err()
+}
+
+func calcDescriptorCaptionServiceLength(d *DescriptorCaptionService) uint8 {
+	if d == nil {
+		return 0
+	}
+	return uint8(1 + 6*len(d.Services))
+}
+
+func writeDescriptorCaptionService(w *astikit.BitsWriter, d *DescriptorCaptionService) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.WriteN(uint8(0xff), 3) // reserved
+	b.WriteN(uint8(len(d.Services)), 5)
+
+	for _, s := range d.Services {
+		b.WriteBytesN(s.Language, 3, 0)
+		b.Write(s.DigitalCC)
+		b.Write(true) // reserved
+		if s.DigitalCC {
+			b.WriteN(s.CaptionServiceNumber, 6)
+		} else {
+			b.WriteN(uint8(0xff), 5) // reserved
+			b.Write(s.Line21Field)
+		}
+		b.Write(s.EasyReader)
+		b.Write(s.WideAspectRatio)
+		b.WriteN(uint16(0xffff), 14) // reserved
+	}
+
+	return b.Err()
+}
+
+func calcDescriptorCALength(d *DescriptorCA) uint8 {
+	if d == nil {
+		return 0
+	}
+	return uint8(4 + len(d.PrivateData))
+}
+
+func writeDescriptorCA(w *astikit.BitsWriter, d *DescriptorCA) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.CASystemID)
+	b.WriteN(uint8(0xff), 3)
+	b.WriteN(d.CAPID, 13)
+	b.Write(d.PrivateData)
+
+	return b.Err()
+}
+
 func calcDescriptorComponentLength(d *DescriptorComponent) uint8 {
 	if d == nil {
 		return 0
@@ -1575,6 +3446,87 @@ func writeDescriptorContent(w *astikit.BitsWriter, d *DescriptorContent) error {
 	return b.Err()
 }
 
+func calcDescriptorContentIdentifierLength(d *DescriptorContentIdentifier) uint8 {
+	if d == nil {
+		return 0
+	}
+
+	ret := 0
+	for _, item := range d.Items {
+		ret++ // type and location
+
+		switch item.CRIDLocation {
+		case CRIDLocationDescriptor:
+			ret += 1 + len(item.CRID)
+		case CRIDLocationCIT:
+			ret += 2
+		}
+	}
+	return uint8(ret)
+}
+
+func writeDescriptorContentIdentifier(w *astikit.BitsWriter, d *DescriptorContentIdentifier) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	for _, item := range d.Items {
+		b.WriteN(item.CRIDType, 6)
+		b.WriteN(item.CRIDLocation, 2)
+
+		switch item.CRIDLocation {
+		case CRIDLocationDescriptor:
+			b.Write(uint8(len(item.CRID)))
+			b.Write(item.CRID)
+		case CRIDLocationCIT:
+			b.Write(item.CRIDReference)
+		}
+	}
+
+	return b.Err()
+}
+
+func calcDescriptorDataBroadcastLength(d *DescriptorDataBroadcast) uint8 {
+	if d == nil {
+		return 0
+	}
+	ret := 2 + 1 + 1 + 3 + 1 // data broadcast id, component tag, selector length, language code and text length
+	ret += len(d.Selector)
+	ret += len(d.Text)
+	return uint8(ret)
+}
+
+func writeDescriptorDataBroadcast(w *astikit.BitsWriter, d *DescriptorDataBroadcast) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.DataBroadcastID)
+	b.Write(d.ComponentTag)
+
+	b.Write(uint8(len(d.Selector)))
+	b.Write(d.Selector)
+
+	b.WriteBytesN(d.ISO639LanguageCode, 3, 0)
+
+	b.Write(uint8(len(d.Text)))
+	b.Write(d.Text)
+
+	return b.Err()
+}
+
+func calcDescriptorDataBroadcastIDLength(d *DescriptorDataBroadcastID) uint8 {
+	if d == nil {
+		return 0
+	}
+	return uint8(2 + len(d.IDSelector))
+}
+
+func writeDescriptorDataBroadcastID(w *astikit.BitsWriter, d *DescriptorDataBroadcastID) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.DataBroadcastID)
+	b.Write(d.IDSelector)
+
+	return b.Err()
+}
+
 func calcDescriptorDataStreamAlignmentLength(d *DescriptorDataStreamAlignment) uint8 {
 	if d == nil {
 		return 0
@@ -1590,6 +3542,21 @@ func writeDescriptorDataStreamAlignment(w *astikit.BitsWriter, d *DescriptorData
 	return b.Err()
 }
 
+func calcDescriptorDefaultAuthorityLength(d *DescriptorDefaultAuthority) uint8 {
+	if d == nil {
+		return 0
+	}
+	return uint8(len(d.Authority))
+}
+
+func writeDescriptorDefaultAuthority(w *astikit.BitsWriter, d *DescriptorDefaultAuthority) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.Authority)
+
+	return b.Err()
+}
+
 func calcDescriptorEnhancedAC3Length(d *DescriptorEnhancedAC3) uint8 {
 	if d == nil {
 		return 0
@@ -1710,6 +3677,50 @@ func writeDescriptorExtendedEvent(w *astikit.BitsWriter, d *DescriptorExtendedEv
 	return b.Err()
 }
 
+func calcDescriptorExtensionAC4Length(d *DescriptorExtensionAC4) int {
+	if d == nil {
+		return 0
+	}
+	ret := 1
+	if d.HasConfig {
+		ret++
+	}
+	if d.HasTOC {
+		ret += 1 + len(d.TOC)
+	}
+	ret += len(d.AdditionalInfo)
+	return ret
+}
+
+func calcDescriptorExtensionImageIconLength(d *DescriptorExtensionImageIcon) int {
+	if d == nil {
+		return 0
+	}
+	ret := 2
+	if d.DescriptorNumber != 0 {
+		return ret + 1 + len(d.IconData)
+	}
+	ret++
+	if d.HasPosition {
+		ret += 3
+	}
+	ret += 1 + len(d.IconType)
+	switch d.IconTransportMode {
+	case IconTransportModeData:
+		ret += 1 + len(d.IconData)
+	case IconTransportModeURL:
+		ret += 1 + len(d.URL)
+	}
+	return ret
+}
+
+func calcDescriptorExtensionMessageLength(d *DescriptorExtensionMessage) int {
+	if d == nil {
+		return 0
+	}
+	return 4 + len(d.Text)
+}
+
 func calcDescriptorExtensionSupplementaryAudioLength(d *DescriptorExtensionSupplementaryAudio) int {
 	if d == nil {
 		return 0
@@ -1722,6 +3733,27 @@ func calcDescriptorExtensionSupplementaryAudioLength(d *DescriptorExtensionSuppl
 	return ret
 }
 
+func calcDescriptorExtensionT2DeliverySystemLength(d *DescriptorExtensionT2DeliverySystem) int {
+	if d == nil {
+		return 0
+	}
+	ret := 3
+	if !d.HasExtendedInfo {
+		return ret
+	}
+	ret += 2
+	for _, c := range d.Cells {
+		ret += 2 // cell id
+		if d.TFSFlag {
+			ret += 1 + 4*len(c.CentreFrequencies)
+		} else {
+			ret += 4
+		}
+		ret += 1 + 5*len(c.SubCells)
+	}
+	return ret
+}
+
 func calcDescriptorExtensionLength(d *DescriptorExtension) uint8 {
 	if d == nil {
 		return 0
@@ -1729,15 +3761,96 @@ func calcDescriptorExtensionLength(d *DescriptorExtension) uint8 {
 	ret := 1 // tag
 
 	switch d.Tag {
+	case DescriptorTagExtensionAC4:
+		ret += calcDescriptorExtensionAC4Length(d.AC4)
+	case DescriptorTagExtensionImageIcon:
+		ret += calcDescriptorExtensionImageIconLength(d.ImageIcon)
+	case DescriptorTagExtensionMessage:
+		ret += calcDescriptorExtensionMessageLength(d.Message)
 	case DescriptorTagExtensionSupplementaryAudio:
 		ret += calcDescriptorExtensionSupplementaryAudioLength(d.SupplementaryAudio)
+	case DescriptorTagExtensionT2DeliverySystem:
+		ret += calcDescriptorExtensionT2DeliverySystemLength(d.T2DeliverySystem)
 	default:
 		if d.Unknown != nil {
 			ret += len(*d.Unknown)
 		}
 	}
 
-	return uint8(ret)
+	return uint8(ret)
+}
+
+func writeDescriptorExtensionAC4(w *astikit.BitsWriter, d *DescriptorExtensionAC4) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.HasConfig)
+	b.Write(d.HasTOC)
+	b.WriteN(uint8(0), 6) // reserved
+
+	if d.HasConfig {
+		b.Write(d.DialogEnhancementEnabled)
+		b.WriteN(d.ChannelMode, 2)
+		b.WriteN(uint8(0), 5) // reserved
+	}
+
+	if d.HasTOC {
+		b.Write(uint8(len(d.TOC)))
+		b.Write(d.TOC)
+	}
+
+	b.Write(d.AdditionalInfo)
+
+	return b.Err()
+}
+
+func writeDescriptorExtensionImageIcon(w *astikit.BitsWriter, d *DescriptorExtensionImageIcon) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.WriteN(d.DescriptorNumber, 4)
+	b.WriteN(d.LastDescriptorNumber, 4)
+	b.WriteN(uint8(0xff), 5) // reserved
+	b.WriteN(d.IconID, 3)
+
+	if d.DescriptorNumber != 0 {
+		b.Write(uint8(len(d.IconData)))
+		b.Write(d.IconData)
+		return b.Err()
+	}
+
+	b.WriteN(d.IconTransportMode, 2)
+	b.Write(d.HasPosition)
+	if d.HasPosition {
+		b.WriteN(d.CoordinateSystem, 3)
+		b.WriteN(uint8(0xff), 2) // reserved
+		b.WriteN(d.IconHorizontalOrigin, 12)
+		b.WriteN(d.IconVerticalOrigin, 12)
+	} else {
+		b.WriteN(uint8(0xff), 5) // reserved
+	}
+
+	b.Write(uint8(len(d.IconType)))
+	b.Write(d.IconType)
+
+	switch d.IconTransportMode {
+	case IconTransportModeData:
+		b.Write(uint8(len(d.IconData)))
+		b.Write(d.IconData)
+	case IconTransportModeURL:
+		b.Write(uint8(len(d.URL)))
+		b.Write(d.URL)
+	}
+
+	return b.Err()
+}
+
+func writeDescriptorExtensionMessage(w *astikit.BitsWriter, d *DescriptorExtensionMessage) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.MessageID)
+	b.WriteBytesN(d.Language, 3, 0)
+	b.Write(d.Text)
+
+	return b.Err()
 }
 
 func writeDescriptorExtensionSupplementaryAudio(w *astikit.BitsWriter, d *DescriptorExtensionSupplementaryAudio) error {
@@ -1757,17 +3870,77 @@ func writeDescriptorExtensionSupplementaryAudio(w *astikit.BitsWriter, d *Descri
 	return b.Err()
 }
 
+func writeDescriptorExtensionT2DeliverySystem(w *astikit.BitsWriter, d *DescriptorExtensionT2DeliverySystem) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.PLPID)
+	b.Write(d.T2SystemID)
+
+	if d.HasExtendedInfo {
+		b.WriteN(d.SISOMISO, 2)
+		b.WriteN(d.Bandwidth, 4)
+		b.WriteN(uint8(0xff), 2) // reserved
+		b.WriteN(d.GuardInterval, 3)
+		b.WriteN(d.TransmissionMode, 3)
+		b.Write(d.OtherFrequencyFlag)
+		b.Write(d.TFSFlag)
+
+		for _, c := range d.Cells {
+			b.Write(c.CellID)
+			if d.TFSFlag {
+				b.Write(uint8(4 * len(c.CentreFrequencies)))
+				for _, f := range c.CentreFrequencies {
+					b.Write(f)
+				}
+			} else {
+				var f uint32
+				if len(c.CentreFrequencies) > 0 {
+					f = c.CentreFrequencies[0]
+				}
+				b.Write(f)
+			}
+			b.Write(uint8(5 * len(c.SubCells)))
+			for _, sc := range c.SubCells {
+				b.Write(sc.CellIDExtension)
+				b.Write(sc.TransposerFrequency)
+			}
+		}
+	}
+
+	return b.Err()
+}
+
 func writeDescriptorExtension(w *astikit.BitsWriter, d *DescriptorExtension) error {
 	b := astikit.NewBitsWriterBatch(w)
 
 	b.Write(d.Tag)
 
 	switch d.Tag {
+	case DescriptorTagExtensionAC4:
+		err := writeDescriptorExtensionAC4(w, d.AC4)
+		if err != nil {
+			return err
+		}
+	case DescriptorTagExtensionImageIcon:
+		err := writeDescriptorExtensionImageIcon(w, d.ImageIcon)
+		if err != nil {
+			return err
+		}
+	case DescriptorTagExtensionMessage:
+		err := writeDescriptorExtensionMessage(w, d.Message)
+		if err != nil {
+			return err
+		}
 	case DescriptorTagExtensionSupplementaryAudio:
 		err := writeDescriptorExtensionSupplementaryAudio(w, d.SupplementaryAudio)
 		if err != nil {
 			return err
 		}
+	case DescriptorTagExtensionT2DeliverySystem:
+		err := writeDescriptorExtensionT2DeliverySystem(w, d.T2DeliverySystem)
+		if err != nil {
+			return err
+		}
 	default:
 		if d.Unknown != nil {
 			b.Write(*d.Unknown)
@@ -1777,18 +3950,109 @@ func writeDescriptorExtension(w *astikit.BitsWriter, d *DescriptorExtension) err
 	return b.Err()
 }
 
+func calcDescriptorFrequencyListLength(d *DescriptorFrequencyList) uint8 {
+	if d == nil {
+		return 0
+	}
+	return uint8(1 + 4*len(d.Frequencies))
+}
+
+func writeDescriptorFrequencyList(w *astikit.BitsWriter, d *DescriptorFrequencyList) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.WriteN(uint8(0xff), 6)
+	b.WriteN(d.CodingType, 2)
+	for _, f := range d.Frequencies {
+		b.Write(f)
+	}
+
+	return b.Err()
+}
+
+func calcDescriptorHEVCVideoLength(d *DescriptorHEVCVideo) uint8 {
+	if d == nil {
+		return 0
+	}
+	if d.TemporalLayerSubsetFlag {
+		return 15
+	}
+	return 13
+}
+
+func writeDescriptorHEVCVideo(w *astikit.BitsWriter, d *DescriptorHEVCVideo) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.WriteN(d.ProfileSpace, 2)
+	b.Write(d.TierFlag)
+	b.WriteN(d.ProfileIDC, 5)
+
+	b.Write(d.ProfileCompatibilityIndication)
+
+	b.Write(d.ProgressiveSourceFlag)
+	b.Write(d.InterlacedSourceFlag)
+	b.Write(d.NonPackedConstraintFlag)
+	b.Write(d.FrameOnlyConstraintFlag)
+	b.WriteN(d.Copied44Bits, 44)
+
+	b.Write(d.LevelIDC)
+
+	b.Write(d.TemporalLayerSubsetFlag)
+	b.Write(d.HEVCStillPresentFlag)
+	b.Write(d.HEVC24HrPicturePresentFlag)
+	b.Write(d.SubPicHRDParamsNotPresentFlag)
+	b.WriteN(uint8(0xff), 2)
+	b.WriteN(d.HDRWCGIDC, 2)
+
+	if d.TemporalLayerSubsetFlag {
+		b.WriteN(uint8(0xff), 5)
+		b.WriteN(d.TemporalIDMin, 3)
+		b.WriteN(uint8(0xff), 5)
+		b.WriteN(d.TemporalIDMax, 3)
+	}
+
+	return b.Err()
+}
+
+func calcDescriptorHierarchyLength(d *DescriptorHierarchy) uint8 {
+	if d == nil {
+		return 0
+	}
+	return 4
+}
+
+func writeDescriptorHierarchy(w *astikit.BitsWriter, d *DescriptorHierarchy) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.NoViewScalabilityFlag)
+	b.Write(d.NoTemporalScalabilityFlag)
+	b.Write(d.NoSpatialScalabilityFlag)
+	b.Write(d.NoQualityScalabilityFlag)
+	b.WriteN(d.HierarchyType, 4)
+	b.WriteN(uint8(0xff), 2)
+	b.WriteN(d.HierarchyLayerIndex, 6)
+	b.Write(d.TREFPresentFlag)
+	b.Write(true)
+	b.WriteN(d.HierarchyEmbeddedLayerIndex, 6)
+	b.WriteN(uint8(0xff), 2)
+	b.WriteN(d.HierarchyChannel, 6)
+
+	return b.Err()
+}
+
 func calcDescriptorISO639LanguageAndAudioTypeLength(d *DescriptorISO639LanguageAndAudioType) uint8 {
 	if d == nil {
 		return 0
 	}
-	return 3 + 1 // language code + type
+	return uint8(4 * len(d.Items)) // language code + type
 }
 
 func writeDescriptorISO639LanguageAndAudioType(w *astikit.BitsWriter, d *DescriptorISO639LanguageAndAudioType) error {
 	b := astikit.NewBitsWriterBatch(w)
 
-	b.WriteBytesN(d.Language, 3, 0)
-	b.Write(d.Type)
+	for _, item := range d.Items {
+		b.WriteBytesN(item.Language, 3, 0)
+		b.Write(item.Type)
+	}
 
 	return b.Err()
 }
@@ -1840,6 +4104,43 @@ func writeDescriptorMaximumBitrate(w *astikit.BitsWriter, d *DescriptorMaximumBi
 	return b.Err()
 }
 
+func calcDescriptorMultiplexBufferUtilizationLength(d *DescriptorMultiplexBufferUtilization) uint8 {
+	if d == nil {
+		return 0
+	}
+	return 4
+}
+
+func writeDescriptorMultiplexBufferUtilization(w *astikit.BitsWriter, d *DescriptorMultiplexBufferUtilization) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.BoundValidFlag)
+	b.WriteN(d.LTWOffsetLowerBound, 15)
+	b.Write(true)
+	b.WriteN(d.LTWOffsetUpperBound, 15)
+
+	return b.Err()
+}
+
+func calcDescriptorNVODReferenceLength(d *DescriptorNVODReference) uint8 {
+	if d == nil {
+		return 0
+	}
+	return uint8(6 * len(d.Items))
+}
+
+func writeDescriptorNVODReference(w *astikit.BitsWriter, d *DescriptorNVODReference) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	for _, item := range d.Items {
+		b.Write(item.TransportStreamID)
+		b.Write(item.OriginalNetworkID)
+		b.Write(item.ServiceID)
+	}
+
+	return b.Err()
+}
+
 func calcDescriptorNetworkNameLength(d *DescriptorNetworkName) uint8 {
 	if d == nil {
 		return 0
@@ -1855,6 +4156,22 @@ func writeDescriptorNetworkName(w *astikit.BitsWriter, d *DescriptorNetworkName)
 	return b.Err()
 }
 
+func calcDescriptorPDCLength(d *DescriptorPDC) uint8 {
+	if d == nil {
+		return 0
+	}
+	return 3
+}
+
+func writeDescriptorPDC(w *astikit.BitsWriter, d *DescriptorPDC) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.WriteN(uint8(0xff), 4)
+	b.WriteN(d.ProgrammeIdentificationLabel, 20)
+
+	return b.Err()
+}
+
 func calcDescriptorParentalRatingLength(d *DescriptorParentalRating) uint8 {
 	if d == nil {
 		return 0
@@ -1873,6 +4190,26 @@ func writeDescriptorParentalRating(w *astikit.BitsWriter, d *DescriptorParentalR
 	return b.Err()
 }
 
+func calcDescriptorPartialTransportStreamLength(d *DescriptorPartialTransportStream) uint8 {
+	if d == nil {
+		return 0
+	}
+	return 8
+}
+
+func writeDescriptorPartialTransportStream(w *astikit.BitsWriter, d *DescriptorPartialTransportStream) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.WriteN(uint8(0xff), 2)
+	b.WriteN(d.PeakRate/400, 22)
+	b.WriteN(uint8(0xff), 2)
+	b.WriteN(d.MinimumOverallSmoothingRate/400, 22)
+	b.WriteN(uint8(0xff), 2)
+	b.WriteN(d.MaximumOverallSmoothingBuffer, 14)
+
+	return b.Err()
+}
+
 func calcDescriptorPrivateDataIndicatorLength(d *DescriptorPrivateDataIndicator) uint8 {
 	if d == nil {
 		return 0
@@ -1919,6 +4256,22 @@ func writeDescriptorRegistration(w *astikit.BitsWriter, d *DescriptorRegistratio
 	return b.Err()
 }
 
+func calcDescriptorSTDLength(d *DescriptorSTD) uint8 {
+	if d == nil {
+		return 0
+	}
+	return 1
+}
+
+func writeDescriptorSTD(w *astikit.BitsWriter, d *DescriptorSTD) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.WriteN(uint8(0xff), 7)
+	b.Write(d.LeakValidFlag)
+
+	return b.Err()
+}
+
 func calcDescriptorServiceLength(d *DescriptorService) uint8 {
 	if d == nil {
 		return 0
@@ -1941,6 +4294,30 @@ func writeDescriptorService(w *astikit.BitsWriter, d *DescriptorService) error {
 	return b.Err()
 }
 
+func calcDescriptorServiceLocationLength(d *DescriptorServiceLocation) uint8 {
+	if d == nil {
+		return 0
+	}
+	return uint8(3 + 6*len(d.Elements))
+}
+
+func writeDescriptorServiceLocation(w *astikit.BitsWriter, d *DescriptorServiceLocation) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.WriteN(uint8(0xff), 3) // reserved
+	b.WriteN(d.PCRPID, 13)
+	b.Write(uint8(len(d.Elements)))
+
+	for _, e := range d.Elements {
+		b.Write(uint8(e.StreamType))
+		b.WriteN(uint8(0xff), 3) // reserved
+		b.WriteN(e.ElementaryPID, 13)
+		b.WriteBytesN(e.Language, 3, 0)
+	}
+
+	return b.Err()
+}
+
 func calcDescriptorShortEventLength(d *DescriptorShortEvent) uint8 {
 	if d == nil {
 		return 0
@@ -1965,6 +4342,24 @@ func writeDescriptorShortEvent(w *astikit.BitsWriter, d *DescriptorShortEvent) e
 	return b.Err()
 }
 
+func calcDescriptorSmoothingBufferLength(d *DescriptorSmoothingBuffer) uint8 {
+	if d == nil {
+		return 0
+	}
+	return 6
+}
+
+func writeDescriptorSmoothingBuffer(w *astikit.BitsWriter, d *DescriptorSmoothingBuffer) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.WriteN(uint8(0xff), 2)
+	b.WriteN(d.SBLeakRate/400, 22)
+	b.WriteN(uint8(0xff), 2)
+	b.WriteN(d.SBSize, 22)
+
+	return b.Err()
+}
+
 func calcDescriptorStreamIdentifierLength(d *DescriptorStreamIdentifier) uint8 {
 	if d == nil {
 		return 0
@@ -1980,6 +4375,21 @@ func writeDescriptorStreamIdentifier(w *astikit.BitsWriter, d *DescriptorStreamI
 	return b.Err()
 }
 
+func calcDescriptorStuffingLength(d *DescriptorStuffing) uint8 {
+	if d == nil {
+		return 0
+	}
+	return uint8(len(d.Data))
+}
+
+func writeDescriptorStuffing(w *astikit.BitsWriter, d *DescriptorStuffing) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.Data)
+
+	return b.Err()
+}
+
 func calcDescriptorSubtitlingLength(d *DescriptorSubtitling) uint8 {
 	if d == nil {
 		return 0
@@ -2000,6 +4410,52 @@ func writeDescriptorSubtitling(w *astikit.BitsWriter, d *DescriptorSubtitling) e
 	return b.Err()
 }
 
+func calcDescriptorTargetBackgroundGridLength(d *DescriptorTargetBackgroundGrid) uint8 {
+	if d == nil {
+		return 0
+	}
+	return 4
+}
+
+func writeDescriptorTargetBackgroundGrid(w *astikit.BitsWriter, d *DescriptorTargetBackgroundGrid) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.WriteN(d.HorizontalSize, 14)
+	b.WriteN(d.VerticalSize, 14)
+	b.WriteN(d.AspectRatioInformation, 4)
+
+	return b.Err()
+}
+
+func calcDescriptorTelephoneLength(d *DescriptorTelephone) uint8 {
+	if d == nil {
+		return 0
+	}
+	return uint8(3 + len(d.CountryPrefix) + len(d.InternationalAreaCode) + len(d.OperatorCode) + len(d.NationalAreaCode) + len(d.CoreNumber))
+}
+
+func writeDescriptorTelephone(w *astikit.BitsWriter, d *DescriptorTelephone) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.WriteN(uint8(0xff), 2)
+	b.Write(d.ForeignAvailability)
+	b.WriteN(d.ConnectionType, 5)
+	b.Write(true)
+	b.WriteN(uint8(len(d.CountryPrefix)), 2)
+	b.WriteN(uint8(len(d.InternationalAreaCode)), 3)
+	b.WriteN(uint8(len(d.OperatorCode)), 2)
+	b.Write(true)
+	b.WriteN(uint8(len(d.NationalAreaCode)), 3)
+	b.WriteN(uint8(len(d.CoreNumber)), 4)
+	b.Write(d.CountryPrefix)
+	b.Write(d.InternationalAreaCode)
+	b.Write(d.OperatorCode)
+	b.Write(d.NationalAreaCode)
+	b.Write(d.CoreNumber)
+
+	return b.Err()
+}
+
 func calcDescriptorTeletextLength(d *DescriptorTeletext) uint8 {
 	if d == nil {
 		return 0
@@ -2021,6 +4477,37 @@ func writeDescriptorTeletext(w *astikit.BitsWriter, d *DescriptorTeletext) error
 	return b.Err()
 }
 
+func calcDescriptorTimeShiftedEventLength(d *DescriptorTimeShiftedEvent) uint8 {
+	if d == nil {
+		return 0
+	}
+	return 4
+}
+
+func writeDescriptorTimeShiftedEvent(w *astikit.BitsWriter, d *DescriptorTimeShiftedEvent) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.ReferenceServiceID)
+	b.Write(d.ReferenceEventID)
+
+	return b.Err()
+}
+
+func calcDescriptorTimeShiftedServiceLength(d *DescriptorTimeShiftedService) uint8 {
+	if d == nil {
+		return 0
+	}
+	return 2
+}
+
+func writeDescriptorTimeShiftedService(w *astikit.BitsWriter, d *DescriptorTimeShiftedService) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.Write(d.ReferenceServiceID)
+
+	return b.Err()
+}
+
 func calcDescriptorVBIDataLength(d *DescriptorVBIData) uint8 {
 	if d == nil {
 		return 0
@@ -2057,6 +4544,23 @@ func writeDescriptorVBIData(w *astikit.BitsWriter, d *DescriptorVBIData) error {
 	return b.Err()
 }
 
+func calcDescriptorVideoWindowLength(d *DescriptorVideoWindow) uint8 {
+	if d == nil {
+		return 0
+	}
+	return 4
+}
+
+func writeDescriptorVideoWindow(w *astikit.BitsWriter, d *DescriptorVideoWindow) error {
+	b := astikit.NewBitsWriterBatch(w)
+
+	b.WriteN(d.HorizontalOffset, 14)
+	b.WriteN(d.VerticalOffset, 14)
+	b.WriteN(d.WindowPriority, 4)
+
+	return b.Err()
+}
+
 func calcDescriptorUnknownLength(d *DescriptorUnknown) uint8 {
 	if d == nil {
 		return 0
@@ -2074,20 +4578,42 @@ func writeDescriptorUnknown(w *astikit.BitsWriter, d *DescriptorUnknown) error {
 
 func calcDescriptorLength(d *Descriptor) uint8 {
 	if d.Tag >= 0x80 && d.Tag <= 0xfe {
+		if d.Tag == DescriptorTagATSCCaptionService && d.CaptionService != nil {
+			return calcDescriptorCaptionServiceLength(d.CaptionService)
+		}
+		if d.Tag == DescriptorTagATSCServiceLocation && d.ServiceLocation != nil {
+			return calcDescriptorServiceLocationLength(d.ServiceLocation)
+		}
 		return calcDescriptorUserDefinedLength(d.UserDefined)
 	}
 
 	switch d.Tag {
+	case DescriptorTagAAC:
+		return calcDescriptorAACLength(d.AAC)
 	case DescriptorTagAC3:
 		return calcDescriptorAC3Length(d.AC3)
 	case DescriptorTagAVCVideo:
 		return calcDescriptorAVCVideoLength(d.AVCVideo)
+	case DescriptorTagAdaptationFieldData:
+		return calcDescriptorAdaptationFieldDataLength(d.AdaptationFieldData)
+	case DescriptorTagAnnouncementSupport:
+		return calcDescriptorAnnouncementSupportLength(d.AnnouncementSupport)
+	case DescriptorTagCA:
+		return calcDescriptorCALength(d.CA)
 	case DescriptorTagComponent:
 		return calcDescriptorComponentLength(d.Component)
 	case DescriptorTagContent:
 		return calcDescriptorContentLength(d.Content)
+	case DescriptorTagContentIdentifier:
+		return calcDescriptorContentIdentifierLength(d.ContentIdentifier)
+	case DescriptorTagDataBroadcast:
+		return calcDescriptorDataBroadcastLength(d.DataBroadcast)
+	case DescriptorTagDataBroadcastID:
+		return calcDescriptorDataBroadcastIDLength(d.DataBroadcastID)
 	case DescriptorTagDataStreamAlignment:
 		return calcDescriptorDataStreamAlignmentLength(d.DataStreamAlignment)
+	case DescriptorTagDefaultAuthority:
+		return calcDescriptorDefaultAuthorityLength(d.DefaultAuthority)
 	case DescriptorTagEnhancedAC3:
 		return calcDescriptorEnhancedAC3Length(d.EnhancedAC3)
 	case DescriptorTagExtendedEvent:
@@ -2095,36 +4621,66 @@ func calcDescriptorLength(d *Descriptor) uint8 {
 		return ret
 	case DescriptorTagExtension:
 		return calcDescriptorExtensionLength(d.Extension)
+	case DescriptorTagFrequencyList:
+		return calcDescriptorFrequencyListLength(d.FrequencyList)
+	case DescriptorTagHEVCVideo:
+		return calcDescriptorHEVCVideoLength(d.HEVCVideo)
+	case DescriptorTagHierarchy:
+		return calcDescriptorHierarchyLength(d.Hierarchy)
 	case DescriptorTagISO639LanguageAndAudioType:
 		return calcDescriptorISO639LanguageAndAudioTypeLength(d.ISO639LanguageAndAudioType)
 	case DescriptorTagLocalTimeOffset:
 		return calcDescriptorLocalTimeOffsetLength(d.LocalTimeOffset)
 	case DescriptorTagMaximumBitrate:
 		return calcDescriptorMaximumBitrateLength(d.MaximumBitrate)
+	case DescriptorTagMultiplexBufferUtilization:
+		return calcDescriptorMultiplexBufferUtilizationLength(d.MultiplexBufferUtilization)
+	case DescriptorTagNVODReference:
+		return calcDescriptorNVODReferenceLength(d.NVODReference)
 	case DescriptorTagNetworkName:
 		return calcDescriptorNetworkNameLength(d.NetworkName)
+	case DescriptorTagPDC:
+		return calcDescriptorPDCLength(d.PDC)
 	case DescriptorTagParentalRating:
 		return calcDescriptorParentalRatingLength(d.ParentalRating)
+	case DescriptorTagPartialTransportStream:
+		return calcDescriptorPartialTransportStreamLength(d.PartialTransportStream)
 	case DescriptorTagPrivateDataIndicator:
 		return calcDescriptorPrivateDataIndicatorLength(d.PrivateDataIndicator)
 	case DescriptorTagPrivateDataSpecifier:
 		return calcDescriptorPrivateDataSpecifierLength(d.PrivateDataSpecifier)
 	case DescriptorTagRegistration:
 		return calcDescriptorRegistrationLength(d.Registration)
+	case DescriptorTagSTD:
+		return calcDescriptorSTDLength(d.STD)
 	case DescriptorTagService:
 		return calcDescriptorServiceLength(d.Service)
 	case DescriptorTagShortEvent:
 		return calcDescriptorShortEventLength(d.ShortEvent)
+	case DescriptorTagSmoothingBuffer:
+		return calcDescriptorSmoothingBufferLength(d.SmoothingBuffer)
 	case DescriptorTagStreamIdentifier:
 		return calcDescriptorStreamIdentifierLength(d.StreamIdentifier)
+	case DescriptorTagStuffing:
+		return calcDescriptorStuffingLength(d.Stuffing)
 	case DescriptorTagSubtitling:
 		return calcDescriptorSubtitlingLength(d.Subtitling)
+	case DescriptorTagTargetBackgroundGrid:
+		return calcDescriptorTargetBackgroundGridLength(d.TargetBackgroundGrid)
+	case DescriptorTagTelephone:
+		return calcDescriptorTelephoneLength(d.Telephone)
 	case DescriptorTagTeletext:
 		return calcDescriptorTeletextLength(d.Teletext)
+	case DescriptorTagTimeShiftedEvent:
+		return calcDescriptorTimeShiftedEventLength(d.TimeShiftedEvent)
+	case DescriptorTagTimeShiftedService:
+		return calcDescriptorTimeShiftedServiceLength(d.TimeShiftedService)
 	case DescriptorTagVBIData:
 		return calcDescriptorVBIDataLength(d.VBIData)
 	case DescriptorTagVBITeletext:
 		return calcDescriptorTeletextLength(d.VBITeletext)
+	case DescriptorTagVideoWindow:
+		return calcDescriptorVideoWindowLength(d.VideoWindow)
 	}
 
 	return calcDescriptorUnknownLength(d.Unknown)
@@ -2148,56 +4704,108 @@ func writeDescriptor(w *astikit.BitsWriter, d *Descriptor) (int, error) {
 	}
 
 	if d.Tag >= 0x80 && d.Tag <= 0xfe {
+		if d.Tag == DescriptorTagATSCCaptionService && d.CaptionService != nil {
+			return written, writeDescriptorCaptionService(w, d.CaptionService)
+		}
+		if d.Tag == DescriptorTagATSCServiceLocation && d.ServiceLocation != nil {
+			return written, writeDescriptorServiceLocation(w, d.ServiceLocation)
+		}
 		return written, writeDescriptorUserDefined(w, d.UserDefined)
 	}
 
 	switch d.Tag {
+	case DescriptorTagAAC:
+		return written, writeDescriptorAAC(w, d.AAC)
 	case DescriptorTagAC3:
 		return written, writeDescriptorAC3(w, d.AC3)
 	case DescriptorTagAVCVideo:
 		return written, writeDescriptorAVCVideo(w, d.AVCVideo)
+	case DescriptorTagAdaptationFieldData:
+		return written, writeDescriptorAdaptationFieldData(w, d.AdaptationFieldData)
+	case DescriptorTagAnnouncementSupport:
+		return written, writeDescriptorAnnouncementSupport(w, d.AnnouncementSupport)
+	case DescriptorTagCA:
+		return written, writeDescriptorCA(w, d.CA)
 	case DescriptorTagComponent:
 		return written, writeDescriptorComponent(w, d.Component)
 	case DescriptorTagContent:
 		return written, writeDescriptorContent(w, d.Content)
+	case DescriptorTagContentIdentifier:
+		return written, writeDescriptorContentIdentifier(w, d.ContentIdentifier)
+	case DescriptorTagDataBroadcast:
+		return written, writeDescriptorDataBroadcast(w, d.DataBroadcast)
+	case DescriptorTagDataBroadcastID:
+		return written, writeDescriptorDataBroadcastID(w, d.DataBroadcastID)
 	case DescriptorTagDataStreamAlignment:
 		return written, writeDescriptorDataStreamAlignment(w, d.DataStreamAlignment)
+	case DescriptorTagDefaultAuthority:
+		return written, writeDescriptorDefaultAuthority(w, d.DefaultAuthority)
 	case DescriptorTagEnhancedAC3:
 		return written, writeDescriptorEnhancedAC3(w, d.EnhancedAC3)
 	case DescriptorTagExtendedEvent:
 		return written, writeDescriptorExtendedEvent(w, d.ExtendedEvent)
 	case DescriptorTagExtension:
 		return written, writeDescriptorExtension(w, d.Extension)
+	case DescriptorTagFrequencyList:
+		return written, writeDescriptorFrequencyList(w, d.FrequencyList)
+	case DescriptorTagHEVCVideo:
+		return written, writeDescriptorHEVCVideo(w, d.HEVCVideo)
+	case DescriptorTagHierarchy:
+		return written, writeDescriptorHierarchy(w, d.Hierarchy)
 	case DescriptorTagISO639LanguageAndAudioType:
 		return written, writeDescriptorISO639LanguageAndAudioType(w, d.ISO639LanguageAndAudioType)
 	case DescriptorTagLocalTimeOffset:
 		return written, writeDescriptorLocalTimeOffset(w, d.LocalTimeOffset)
 	case DescriptorTagMaximumBitrate:
 		return written, writeDescriptorMaximumBitrate(w, d.MaximumBitrate)
+	case DescriptorTagMultiplexBufferUtilization:
+		return written, writeDescriptorMultiplexBufferUtilization(w, d.MultiplexBufferUtilization)
+	case DescriptorTagNVODReference:
+		return written, writeDescriptorNVODReference(w, d.NVODReference)
 	case DescriptorTagNetworkName:
 		return written, writeDescriptorNetworkName(w, d.NetworkName)
+	case DescriptorTagPDC:
+		return written, writeDescriptorPDC(w, d.PDC)
 	case DescriptorTagParentalRating:
 		return written, writeDescriptorParentalRating(w, d.ParentalRating)
+	case DescriptorTagPartialTransportStream:
+		return written, writeDescriptorPartialTransportStream(w, d.PartialTransportStream)
 	case DescriptorTagPrivateDataIndicator:
 		return written, writeDescriptorPrivateDataIndicator(w, d.PrivateDataIndicator)
 	case DescriptorTagPrivateDataSpecifier:
 		return written, writeDescriptorPrivateDataSpecifier(w, d.PrivateDataSpecifier)
 	case DescriptorTagRegistration:
 		return written, writeDescriptorRegistration(w, d.Registration)
+	case DescriptorTagSTD:
+		return written, writeDescriptorSTD(w, d.STD)
 	case DescriptorTagService:
 		return written, writeDescriptorService(w, d.Service)
 	case DescriptorTagShortEvent:
 		return written, writeDescriptorShortEvent(w, d.ShortEvent)
+	case DescriptorTagSmoothingBuffer:
+		return written, writeDescriptorSmoothingBuffer(w, d.SmoothingBuffer)
 	case DescriptorTagStreamIdentifier:
 		return written, writeDescriptorStreamIdentifier(w, d.StreamIdentifier)
+	case DescriptorTagStuffing:
+		return written, writeDescriptorStuffing(w, d.Stuffing)
 	case DescriptorTagSubtitling:
 		return written, writeDescriptorSubtitling(w, d.Subtitling)
+	case DescriptorTagTargetBackgroundGrid:
+		return written, writeDescriptorTargetBackgroundGrid(w, d.TargetBackgroundGrid)
+	case DescriptorTagTelephone:
+		return written, writeDescriptorTelephone(w, d.Telephone)
 	case DescriptorTagTeletext:
 		return written, writeDescriptorTeletext(w, d.Teletext)
+	case DescriptorTagTimeShiftedEvent:
+		return written, writeDescriptorTimeShiftedEvent(w, d.TimeShiftedEvent)
+	case DescriptorTagTimeShiftedService:
+		return written, writeDescriptorTimeShiftedService(w, d.TimeShiftedService)
 	case DescriptorTagVBIData:
 		return written, writeDescriptorVBIData(w, d.VBIData)
 	case DescriptorTagVBITeletext:
 		return written, writeDescriptorTeletext(w, d.VBITeletext)
+	case DescriptorTagVideoWindow:
+		return written, writeDescriptorVideoWindow(w, d.VideoWindow)
 	}
 
 	return written, writeDescriptorUnknown(w, d.Unknown)
@@ -59,21 +59,61 @@ var descriptorTestTable = []descriptorTest{
 				MainID:           uint8(3),
 			}},
 	},
+	{
+		"Hierarchy",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagHierarchy))               // Tag
+			w.Write(uint8(4))                                    // Length
+			w.Write("1")                                         // No view scalability flag
+			w.Write("0")                                         // No temporal scalability flag
+			w.Write("1")                                         // No spatial scalability flag
+			w.Write("1")                                         // No quality scalability flag
+			w.WriteN(uint8(HierarchyTypeTemporalScalability), 4) // Hierarchy type
+			w.Write("11")                                        // Reserved
+			w.WriteN(uint8(2), 6)                                // Hierarchy layer index
+			w.Write("1")                                         // TREF present flag
+			w.Write("1")                                         // Reserved
+			w.WriteN(uint8(1), 6)                                // Hierarchy embedded layer index
+			w.Write("11")                                        // Reserved
+			w.WriteN(uint8(3), 6)                                // Hierarchy channel
+		},
+		Descriptor{
+			Tag:    DescriptorTagHierarchy,
+			Length: 4,
+			Hierarchy: &DescriptorHierarchy{
+				HierarchyChannel:            3,
+				HierarchyEmbeddedLayerIndex: 1,
+				HierarchyLayerIndex:         2,
+				HierarchyType:               HierarchyTypeTemporalScalability,
+				NoQualityScalabilityFlag:    true,
+				NoSpatialScalabilityFlag:    true,
+				NoViewScalabilityFlag:       true,
+				TREFPresentFlag:             true,
+			}},
+	},
 	{
 		"ISO639LanguageAndAudioType",
 		func(w *astikit.BitsWriter) {
 			w.Write(uint8(DescriptorTagISO639LanguageAndAudioType)) // Tag
-			w.Write(uint8(4))                                       // Length
-			w.Write([]byte("eng"))                                  // Language
-			w.Write(uint8(AudioTypeCleanEffects))                   // Audio type
+			w.Write(uint8(8))                                       // Length
+			w.Write([]byte("eng"))                                  // Item #1 language
+			w.Write(uint8(AudioTypeCleanEffects))                   // Item #1 audio type
+			w.Write([]byte("fra"))                                  // Item #2 language
+			w.Write(uint8(AudioTypeHearingImpaired))                // Item #2 audio type
 		},
 		Descriptor{
 			Tag:    DescriptorTagISO639LanguageAndAudioType,
-			Length: 4,
-			ISO639LanguageAndAudioType: &DescriptorISO639LanguageAndAudioType{
-				Language: []byte("eng"),
-				Type:     AudioTypeCleanEffects,
-			}},
+			Length: 8,
+			ISO639LanguageAndAudioType: &DescriptorISO639LanguageAndAudioType{Items: []*DescriptorISO639LanguageAndAudioTypeItem{
+				{
+					Language: []byte("eng"),
+					Type:     AudioTypeCleanEffects,
+				},
+				{
+					Language: []byte("fra"),
+					Type:     AudioTypeHearingImpaired,
+				},
+			}}},
 	},
 	{
 		"MaximumBitrate",
@@ -87,6 +127,45 @@ var descriptorTestTable = []descriptorTest{
 			Length:         3,
 			MaximumBitrate: &DescriptorMaximumBitrate{Bitrate: uint32(50)}},
 	},
+	{
+		"MultiplexBufferUtilization",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagMultiplexBufferUtilization)) // Tag
+			w.Write(uint8(4))                                       // Length
+			w.Write("1")                                            // Bound valid flag
+			w.WriteN(uint16(1000), 15)                              // LTW offset lower bound
+			w.Write("1")                                            // Reserved
+			w.WriteN(uint16(0x7fff), 15)                            // LTW offset upper bound
+		},
+		Descriptor{
+			Tag:    DescriptorTagMultiplexBufferUtilization,
+			Length: 4,
+			MultiplexBufferUtilization: &DescriptorMultiplexBufferUtilization{
+				BoundValidFlag:      true,
+				LTWOffsetLowerBound: 1000,
+				LTWOffsetUpperBound: 0x7fff,
+			}},
+	},
+	{
+		"NVODReference",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagNVODReference)) // Tag
+			w.Write(uint8(12))                         // Length
+			w.Write(uint16(1))                         // Item #1 transport stream ID
+			w.Write(uint16(2))                         // Item #1 original network ID
+			w.Write(uint16(3))                         // Item #1 service ID
+			w.Write(uint16(4))                         // Item #2 transport stream ID
+			w.Write(uint16(5))                         // Item #2 original network ID
+			w.Write(uint16(6))                         // Item #2 service ID
+		},
+		Descriptor{
+			Tag:    DescriptorTagNVODReference,
+			Length: 12,
+			NVODReference: &DescriptorNVODReference{Items: []*DescriptorNVODReferenceItem{
+				{OriginalNetworkID: 2, ServiceID: 3, TransportStreamID: 1},
+				{OriginalNetworkID: 5, ServiceID: 6, TransportStreamID: 4},
+			}}},
+	},
 	{
 		"NetworkName",
 		func(w *astikit.BitsWriter) {
@@ -99,6 +178,19 @@ var descriptorTestTable = []descriptorTest{
 			Length:      4,
 			NetworkName: &DescriptorNetworkName{Name: []byte("name")}},
 	},
+	{
+		"STD",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagSTD)) // Tag
+			w.Write(uint8(1))                // Length
+			w.Write("1111111")               // Reserved
+			w.Write("1")                     // Leak valid flag
+		},
+		Descriptor{
+			Tag:    DescriptorTagSTD,
+			Length: 1,
+			STD:    &DescriptorSTD{LeakValidFlag: true}},
+	},
 	{
 		"Service",
 		func(w *astikit.BitsWriter) {
@@ -139,6 +231,24 @@ var descriptorTestTable = []descriptorTest{
 				Text:      []byte("text"),
 			}},
 	},
+	{
+		"SmoothingBuffer",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagSmoothingBuffer)) // Tag
+			w.Write(uint8(6))                            // Length
+			w.Write("11")                                // Reserved
+			w.WriteN(uint32(2500), 22)                   // SB leak rate
+			w.Write("11")                                // Reserved
+			w.WriteN(uint32(0x3fffff), 22)               // SB size
+		},
+		Descriptor{
+			Tag:    DescriptorTagSmoothingBuffer,
+			Length: 6,
+			SmoothingBuffer: &DescriptorSmoothingBuffer{
+				SBLeakRate: 2500 * 400,
+				SBSize:     0x3fffff,
+			}},
+	},
 	{
 		"StreamIdentifier",
 		func(w *astikit.BitsWriter) {
@@ -183,6 +293,58 @@ var descriptorTestTable = []descriptorTest{
 				},
 			}}},
 	},
+	{
+		"TargetBackgroundGrid",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagTargetBackgroundGrid)) // Tag
+			w.Write(uint8(4))                                 // Length
+			w.WriteN(uint16(1920), 14)                        // Horizontal size
+			w.WriteN(uint16(1080), 14)                        // Vertical size
+			w.WriteN(uint8(3), 4)                             // Aspect ratio information
+		},
+		Descriptor{
+			Tag:    DescriptorTagTargetBackgroundGrid,
+			Length: 4,
+			TargetBackgroundGrid: &DescriptorTargetBackgroundGrid{
+				AspectRatioInformation: 3,
+				HorizontalSize:         1920,
+				VerticalSize:           1080,
+			}},
+	},
+	{
+		"Telephone",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagTelephone)) // Tag
+			w.Write(uint8(14))                     // Length
+			w.Write("11")                          // Reserved for future use
+			w.Write("1")                           // Foreign availability
+			w.Write("00010")                       // Connection type
+			w.Write("1")                           // Reserved for future use
+			w.Write("10")                          // Country prefix length
+			w.Write("001")                         // International area code length
+			w.Write("01")                          // Operator code length
+			w.Write("1")                           // Reserved for future use
+			w.Write("011")                         // National area code length
+			w.Write("0100")                        // Core number length
+			w.Write([]byte("33"))                  // Country prefix
+			w.Write([]byte("1"))                   // International area code
+			w.Write([]byte("6"))                   // Operator code
+			w.Write([]byte("123"))                 // National area code
+			w.Write([]byte("4567"))                // Core number
+		},
+		Descriptor{
+			Tag:    DescriptorTagTelephone,
+			Length: 14,
+			Telephone: &DescriptorTelephone{
+				ConnectionType:        2,
+				CoreNumber:            []byte("4567"),
+				CountryPrefix:         []byte("33"),
+				ForeignAvailability:   true,
+				InternationalAreaCode: []byte("1"),
+				NationalAreaCode:      []byte("123"),
+				OperatorCode:          []byte("6"),
+			}},
+	},
 	{
 		"Teletext",
 		func(w *astikit.BitsWriter) {
@@ -317,6 +479,226 @@ var descriptorTestTable = []descriptorTest{
 				Unknown: nil,
 			}},
 	},
+	{
+		"ExtensionSupplementaryAudioNoLanguageCode",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagExtension))                   // Tag
+			w.Write(uint8(9))                                        // Length
+			w.Write(uint8(DescriptorTagExtensionSupplementaryAudio)) // Extension tag
+			w.Write("0")                                             // Mix type
+			w.Write("00001")                                         // Editorial classification
+			w.Write("1")                                             // Reserved
+			w.Write("0")                                             // Language code flag
+			w.Write([]byte("private"))                               // Private data
+		},
+		Descriptor{
+			Tag:    DescriptorTagExtension,
+			Length: 9,
+			Extension: &DescriptorExtension{
+				SupplementaryAudio: &DescriptorExtensionSupplementaryAudio{
+					EditorialClassification: 1,
+					PrivateData:             []byte("private"),
+				},
+				Tag: DescriptorTagExtensionSupplementaryAudio,
+			}},
+	},
+	{
+		"ExtensionAC4",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagExtension))    // Tag
+			w.Write(uint8(11))                        // Length
+			w.Write(uint8(DescriptorTagExtensionAC4)) // Extension tag
+			w.Write("1")                              // Config flag
+			w.Write("1")                              // TOC flag
+			w.Write("000000")                         // Reserved
+			w.Write("1")                              // Dialog enhancement enabled
+			w.Write("10")                             // Channel mode
+			w.Write("00000")                          // Reserved
+			w.Write(uint8(3))                         // TOC length
+			w.Write([]byte("toc"))                    // TOC
+			w.Write([]byte("info"))                   // Additional info
+		},
+		Descriptor{
+			Tag:    DescriptorTagExtension,
+			Length: 11,
+			Extension: &DescriptorExtension{
+				AC4: &DescriptorExtensionAC4{
+					AdditionalInfo:           []byte("info"),
+					ChannelMode:              2,
+					DialogEnhancementEnabled: true,
+					HasConfig:                true,
+					HasTOC:                   true,
+					TOC:                      []byte("toc"),
+				},
+				Tag: DescriptorTagExtensionAC4,
+			}},
+	},
+	{
+		"ExtensionImageIcon",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagExtension))          // Tag
+			w.Write(uint8(20))                              // Length
+			w.Write(uint8(DescriptorTagExtensionImageIcon)) // Extension tag
+			w.Write("0000")                                 // Descriptor number
+			w.Write("0001")                                 // Last descriptor number
+			w.Write("11111")                                // Reserved
+			w.Write("010")                                  // Icon ID
+			w.Write("00")                                   // Icon transport mode
+			w.Write("1")                                    // Position flag
+			w.Write("011")                                  // Coordinate system
+			w.Write("11")                                   // Reserved
+			w.Write("000001100100")                         // Icon horizontal origin
+			w.Write("000011001000")                         // Icon vertical origin
+			w.Write(uint8(9))                               // Icon type length
+			w.Write([]byte("image/png"))                    // Icon type
+			w.Write(uint8(2))                               // Icon data length
+			w.Write([]byte("ic"))                           // Icon data
+		},
+		Descriptor{
+			Tag:    DescriptorTagExtension,
+			Length: 20,
+			Extension: &DescriptorExtension{
+				ImageIcon: &DescriptorExtensionImageIcon{
+					CoordinateSystem:     3,
+					HasPosition:          true,
+					IconData:             []byte("ic"),
+					IconHorizontalOrigin: 100,
+					IconID:               2,
+					IconTransportMode:    IconTransportModeData,
+					IconType:             []byte("image/png"),
+					IconVerticalOrigin:   200,
+					LastDescriptorNumber: 1,
+				},
+				Tag: DescriptorTagExtensionImageIcon,
+			}},
+	},
+	{
+		"ExtensionImageIconURL",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagExtension))          // Tag
+			w.Write(uint8(14))                              // Length
+			w.Write(uint8(DescriptorTagExtensionImageIcon)) // Extension tag
+			w.Write("0000")                                 // Descriptor number
+			w.Write("0000")                                 // Last descriptor number
+			w.Write("11111")                                // Reserved
+			w.Write("001")                                  // Icon ID
+			w.Write("01")                                   // Icon transport mode
+			w.Write("0")                                    // Position flag
+			w.Write("11111")                                // Reserved
+			w.Write(uint8(0))                               // Icon type length
+			w.Write(uint8(8))                               // URL length
+			w.Write([]byte("http://i"))                     // URL
+		},
+		Descriptor{
+			Tag:    DescriptorTagExtension,
+			Length: 14,
+			Extension: &DescriptorExtension{
+				ImageIcon: &DescriptorExtensionImageIcon{
+					IconID:            1,
+					IconTransportMode: IconTransportModeURL,
+					IconType:          []byte{},
+					URL:               []byte("http://i"),
+				},
+				Tag: DescriptorTagExtensionImageIcon,
+			}},
+	},
+	{
+		"ExtensionImageIconContinuation",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagExtension))          // Tag
+			w.Write(uint8(6))                               // Length
+			w.Write(uint8(DescriptorTagExtensionImageIcon)) // Extension tag
+			w.Write("0001")                                 // Descriptor number
+			w.Write("0001")                                 // Last descriptor number
+			w.Write("11111")                                // Reserved
+			w.Write("010")                                  // Icon ID
+			w.Write(uint8(2))                               // Icon data length
+			w.Write([]byte("on"))                           // Icon data
+		},
+		Descriptor{
+			Tag:    DescriptorTagExtension,
+			Length: 6,
+			Extension: &DescriptorExtension{
+				ImageIcon: &DescriptorExtensionImageIcon{
+					DescriptorNumber:     1,
+					IconData:             []byte("on"),
+					IconID:               2,
+					LastDescriptorNumber: 1,
+				},
+				Tag: DescriptorTagExtensionImageIcon,
+			}},
+	},
+	{
+		"ExtensionMessage",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagExtension))        // Tag
+			w.Write(uint8(9))                             // Length
+			w.Write(uint8(DescriptorTagExtensionMessage)) // Extension tag
+			w.Write(uint8(7))                             // Message ID
+			w.Write([]byte("eng"))                        // Language
+			w.Write([]byte("text"))                       // Text
+		},
+		Descriptor{
+			Tag:    DescriptorTagExtension,
+			Length: 9,
+			Extension: &DescriptorExtension{
+				Message: &DescriptorExtensionMessage{
+					Language:  []byte("eng"),
+					MessageID: 7,
+					Text:      []byte("text"),
+				},
+				Tag: DescriptorTagExtensionMessage,
+			}},
+	},
+	{
+		"ExtensionT2DeliverySystem",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagExtension))                 // Tag
+			w.Write(uint8(23))                                     // Length
+			w.Write(uint8(DescriptorTagExtensionT2DeliverySystem)) // Extension tag
+			w.Write(uint8(1))                                      // PLP ID
+			w.Write(uint16(2))                                     // T2 system ID
+			w.Write("01")                                          // SISO/MISO
+			w.Write("0010")                                        // Bandwidth
+			w.Write("11")                                          // Reserved
+			w.Write("011")                                         // Guard interval
+			w.Write("100")                                         // Transmission mode
+			w.Write("1")                                           // Other frequency flag
+			w.Write("1")                                           // TFS flag
+			w.Write(uint16(3))                                     // Cell #1 ID
+			w.Write(uint8(8))                                      // Cell #1 frequency loop length
+			w.Write(uint32(4))                                     // Cell #1 centre frequency #1
+			w.Write(uint32(5))                                     // Cell #1 centre frequency #2
+			w.Write(uint8(5))                                      // Cell #1 subcell info loop length
+			w.Write(uint8(6))                                      // Cell #1 subcell #1 cell ID extension
+			w.Write(uint32(7))                                     // Cell #1 subcell #1 transposer frequency
+		},
+		Descriptor{
+			Tag:    DescriptorTagExtension,
+			Length: 23,
+			Extension: &DescriptorExtension{
+				T2DeliverySystem: &DescriptorExtensionT2DeliverySystem{
+					Bandwidth: 2,
+					Cells: []*DescriptorExtensionT2DeliverySystemCell{{
+						CellID:            3,
+						CentreFrequencies: []uint32{4, 5},
+						SubCells: []*DescriptorExtensionT2DeliverySystemSubCell{{
+							CellIDExtension:     6,
+							TransposerFrequency: 7,
+						}},
+					}},
+					GuardInterval:      3,
+					HasExtendedInfo:    true,
+					OtherFrequencyFlag: true,
+					PLPID:              1,
+					SISOMISO:           1,
+					T2SystemID:         2,
+					TFSFlag:            true,
+					TransmissionMode:   4,
+				},
+				Tag: DescriptorTagExtensionT2DeliverySystem,
+			}},
+	},
 	{
 		"Component",
 		func(w *astikit.BitsWriter) {
@@ -359,6 +741,23 @@ var descriptorTestTable = []descriptorTest{
 				UserByte:            3,
 			}}}},
 	},
+	{
+		"PDC",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagPDC)) // Tag
+			w.Write(uint8(3))                // Length
+			w.Write("1111")                  // Reserved
+			w.Write("11111")                 // Day
+			w.Write("1010")                  // Month
+			w.Write("10111")                 // Hour
+			w.Write("111011")                // Minute
+		},
+		Descriptor{
+			Length: 3,
+			PDC:    &DescriptorPDC{ProgrammeIdentificationLabel: 0xfd5fb},
+			Tag:    DescriptorTagPDC,
+		},
+	},
 	{
 		"ParentalRating",
 		func(w *astikit.BitsWriter) {
@@ -375,6 +774,27 @@ var descriptorTestTable = []descriptorTest{
 				Rating:      2,
 			}}}},
 	},
+	{
+		"PartialTransportStream",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagPartialTransportStream)) // Tag
+			w.Write(uint8(8))                                   // Length
+			w.Write("11")                                       // Reserved
+			w.WriteN(uint32(5000), 22)                          // Peak rate
+			w.Write("11")                                       // Reserved
+			w.WriteN(uint32(0x3fffff), 22)                      // Minimum overall smoothing rate
+			w.Write("11")                                       // Reserved
+			w.WriteN(uint16(0x3fff), 14)                        // Maximum overall smoothing buffer
+		},
+		Descriptor{
+			Tag:    DescriptorTagPartialTransportStream,
+			Length: 8,
+			PartialTransportStream: &DescriptorPartialTransportStream{
+				MaximumOverallSmoothingBuffer: 0x3fff,
+				MinimumOverallSmoothingRate:   0x3fffff * 400,
+				PeakRate:                      5000 * 400,
+			}},
+	},
 	{
 		"LocalTimeOffset",
 		func(w *astikit.BitsWriter) {
@@ -442,6 +862,24 @@ var descriptorTestTable = []descriptorTest{
 				Type:     uint8(1),
 			}}}},
 	},
+	{
+		"VideoWindow",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagVideoWindow)) // Tag
+			w.Write(uint8(4))                        // Length
+			w.WriteN(uint16(320), 14)                // Horizontal offset
+			w.WriteN(uint16(16383), 14)              // Vertical offset
+			w.WriteN(uint8(15), 4)                   // Window priority
+		},
+		Descriptor{
+			Tag:    DescriptorTagVideoWindow,
+			Length: 4,
+			VideoWindow: &DescriptorVideoWindow{
+				HorizontalOffset: 320,
+				VerticalOffset:   16383,
+				WindowPriority:   15,
+			}},
+	},
 	{
 		"AVCVideo",
 		func(w *astikit.BitsWriter) {
@@ -499,6 +937,46 @@ var descriptorTestTable = []descriptorTest{
 				Type: 2,
 			}},
 	},
+	{
+		"DataBroadcast",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagDataBroadcast)) // Tag
+			w.Write(uint8(14))                         // Length
+			w.Write(uint16(0x1234))                    // Data broadcast id
+			w.Write(uint8(5))                          // Component tag
+			w.Write(uint8(2))                          // Selector length
+			w.Write([]byte("se"))                      // Selector
+			w.Write([]byte("eng"))                     // ISO639 language code
+			w.Write(uint8(4))                          // Text length
+			w.Write([]byte("text"))                    // Text
+		},
+		Descriptor{
+			Tag:    DescriptorTagDataBroadcast,
+			Length: 14,
+			DataBroadcast: &DescriptorDataBroadcast{
+				ComponentTag:       5,
+				DataBroadcastID:    0x1234,
+				ISO639LanguageCode: []byte("eng"),
+				Selector:           []byte("se"),
+				Text:               []byte("text"),
+			}},
+	},
+	{
+		"DataBroadcastID",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagDataBroadcastID)) // Tag
+			w.Write(uint8(5))                            // Length
+			w.Write(uint16(0x1234))                      // Data broadcast id
+			w.Write([]byte("sel"))                       // ID selector
+		},
+		Descriptor{
+			Tag:    DescriptorTagDataBroadcastID,
+			Length: 5,
+			DataBroadcastID: &DescriptorDataBroadcastID{
+				DataBroadcastID: 0x1234,
+				IDSelector:      []byte("sel"),
+			}},
+	},
 	{
 		"PrivateDataIndicator",
 		func(w *astikit.BitsWriter) {
@@ -513,6 +991,255 @@ var descriptorTestTable = []descriptorTest{
 				Indicator: 127,
 			}},
 	},
+	{
+		"AAC",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagAAC)) // Tag
+			w.Write(uint8(7))                // Length
+			w.Write(uint8(0x58))             // Profile and level
+			w.Write("1")                     // AAC type flag
+			w.Write("1111111")               // Reserved
+			w.Write(uint8(0x3))              // AAC type
+			w.Write([]byte("info"))          // Additional info
+		},
+		Descriptor{
+			Tag:    DescriptorTagAAC,
+			Length: 7,
+			AAC: &DescriptorAAC{
+				AACType:         0x3,
+				AdditionalInfo:  []byte("info"),
+				HasAACType:      true,
+				ProfileAndLevel: 0x58,
+			}},
+	},
+	{
+		"AACProfileOnly",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagAAC)) // Tag
+			w.Write(uint8(1))                // Length
+			w.Write(uint8(0x51))             // Profile and level
+		},
+		Descriptor{
+			Tag:    DescriptorTagAAC,
+			Length: 1,
+			AAC:    &DescriptorAAC{ProfileAndLevel: 0x51},
+		},
+	},
+	{
+		"HEVCVideo",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagHEVCVideo)) // Tag
+			w.Write(uint8(15))                     // Length
+			w.Write("01")                          // Profile space
+			w.Write("1")                           // Tier flag
+			w.Write("00010")                       // Profile idc
+			w.Write(uint32(0x60000000))            // Profile compatibility indication
+			w.Write("1")                           // Progressive source flag
+			w.Write("0")                           // Interlaced source flag
+			w.Write("1")                           // Non packed constraint flag
+			w.Write("0")                           // Frame only constraint flag
+			w.WriteN(uint64(0x123456789ab), 44)    // Copied 44 bits
+			w.Write(uint8(153))                    // Level idc
+			w.Write("1")                           // Temporal layer subset flag
+			w.Write("0")                           // HEVC still present flag
+			w.Write("1")                           // HEVC 24hr picture present flag
+			w.Write("0")                           // Sub pic hrd params not present flag
+			w.Write("11")                          // Reserved
+			w.Write("10")                          // HDR WCG idc
+			w.Write("11111")                       // Reserved
+			w.Write("001")                         // Temporal id min
+			w.Write("11111")                       // Reserved
+			w.Write("110")                         // Temporal id max
+		},
+		Descriptor{
+			Tag:    DescriptorTagHEVCVideo,
+			Length: 15,
+			HEVCVideo: &DescriptorHEVCVideo{
+				Copied44Bits:                   0x123456789ab,
+				HDRWCGIDC:                      2,
+				HEVC24HrPicturePresentFlag:     true,
+				LevelIDC:                       153,
+				NonPackedConstraintFlag:        true,
+				ProfileCompatibilityIndication: 0x60000000,
+				ProfileIDC:                     2,
+				ProfileSpace:                   1,
+				ProgressiveSourceFlag:          true,
+				TemporalIDMax:                  6,
+				TemporalIDMin:                  1,
+				TemporalLayerSubsetFlag:        true,
+				TierFlag:                       true,
+			}},
+	},
+	{
+		"ContentIdentifier",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagContentIdentifier)) // Tag
+			w.Write(uint8(10))                             // Length
+			w.Write("110001")                              // Item #1 CRID type
+			w.Write("00")                                  // Item #1 CRID location
+			w.Write(uint8(5))                              // Item #1 CRID length
+			w.Write([]byte("/crid"))                       // Item #1 CRID
+			w.Write("110010")                              // Item #2 CRID type
+			w.Write("01")                                  // Item #2 CRID location
+			w.Write(uint16(0x1234))                        // Item #2 CRID reference
+		},
+		Descriptor{
+			Tag:    DescriptorTagContentIdentifier,
+			Length: 10,
+			ContentIdentifier: &DescriptorContentIdentifier{Items: []*DescriptorContentIdentifierItem{
+				{
+					CRID:         []byte("/crid"),
+					CRIDLocation: CRIDLocationDescriptor,
+					CRIDType:     0x31,
+				},
+				{
+					CRIDLocation:  CRIDLocationCIT,
+					CRIDReference: 0x1234,
+					CRIDType:      0x32,
+				},
+			}}},
+	},
+	{
+		"DefaultAuthority",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagDefaultAuthority)) // Tag
+			w.Write(uint8(10))                            // Length
+			w.Write([]byte("fp.bbc.uk/"))                 // Authority
+		},
+		Descriptor{
+			Tag:              DescriptorTagDefaultAuthority,
+			Length:           10,
+			DefaultAuthority: &DescriptorDefaultAuthority{Authority: []byte("fp.bbc.uk/")}},
+	},
+	{
+		"TimeShiftedEvent",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagTimeShiftedEvent)) // Tag
+			w.Write(uint8(4))                             // Length
+			w.Write(uint16(0x1234))                       // Reference service id
+			w.Write(uint16(0x5678))                       // Reference event id
+		},
+		Descriptor{
+			Tag:    DescriptorTagTimeShiftedEvent,
+			Length: 4,
+			TimeShiftedEvent: &DescriptorTimeShiftedEvent{
+				ReferenceEventID:   0x5678,
+				ReferenceServiceID: 0x1234,
+			}},
+	},
+	{
+		"TimeShiftedService",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagTimeShiftedService)) // Tag
+			w.Write(uint8(2))                               // Length
+			w.Write(uint16(0x1234))                         // Reference service id
+		},
+		Descriptor{
+			Tag:                DescriptorTagTimeShiftedService,
+			Length:             2,
+			TimeShiftedService: &DescriptorTimeShiftedService{ReferenceServiceID: 0x1234}},
+	},
+	{
+		"CA",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagCA)) // Tag
+			w.Write(uint8(6))               // Length
+			w.Write(uint16(0x0500))         // CA system ID
+			w.Write("111")                  // Reserved
+			w.Write("0000100000001")        // CA PID
+			w.Write([]byte("pd"))           // Private data
+		},
+		Descriptor{
+			Tag:    DescriptorTagCA,
+			Length: 6,
+			CA: &DescriptorCA{
+				CAPID:       0x101,
+				CASystemID:  0x0500,
+				PrivateData: []byte("pd"),
+			}},
+	},
+	{
+		"FrequencyList",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagFrequencyList)) // Tag
+			w.Write(uint8(13))                         // Length
+			w.Write("111111")                          // Reserved
+			w.Write("11")                              // Coding type
+			w.Write(uint32(0x3a2c6a0))                 // Frequency #1
+			w.Write(uint32(0x3a6f1c0))                 // Frequency #2
+			w.Write(uint32(0x3ab1ce0))                 // Frequency #3
+		},
+		Descriptor{
+			Tag:    DescriptorTagFrequencyList,
+			Length: 13,
+			FrequencyList: &DescriptorFrequencyList{
+				CodingType:  FrequencyListCodingTypeTerrestrial,
+				Frequencies: []uint32{0x3a2c6a0, 0x3a6f1c0, 0x3ab1ce0},
+			},
+		},
+	},
+	{
+		"Stuffing",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagStuffing)) // Tag
+			w.Write(uint8(3))                     // Length
+			w.Write([]byte{0xff, 0xff, 0xff})     // Stuffing
+		},
+		Descriptor{
+			Tag:      DescriptorTagStuffing,
+			Length:   3,
+			Stuffing: &DescriptorStuffing{Data: []byte{0xff, 0xff, 0xff}},
+		},
+	},
+	{
+		"AdaptationFieldData",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagAdaptationFieldData)) // Tag
+			w.Write(uint8(1))                                // Length
+			w.Write(uint8(5))                                // Flags
+		},
+		Descriptor{
+			AdaptationFieldData: &DescriptorAdaptationFieldData{Flags: 5},
+			Length:              1,
+			Tag:                 DescriptorTagAdaptationFieldData,
+		},
+	},
+	{
+		"AnnouncementSupport",
+		func(w *astikit.BitsWriter) {
+			w.Write(uint8(DescriptorTagAnnouncementSupport)) // Tag
+			w.Write(uint8(11))                               // Length
+			w.Write(uint16(0x3))                             // Announcement support indicator
+			w.Write("0000")                                  // Announcement #1 type
+			w.Write("1")                                     // Announcement #1 reserved
+			w.Write("000")                                   // Announcement #1 reference type
+			w.Write("0001")                                  // Announcement #2 type
+			w.Write("1")                                     // Announcement #2 reserved
+			w.Write("001")                                   // Announcement #2 reference type
+			w.Write(uint16(1))                               // Announcement #2 original network ID
+			w.Write(uint16(2))                               // Announcement #2 transport stream ID
+			w.Write(uint16(3))                               // Announcement #2 service ID
+			w.Write(uint8(4))                                // Announcement #2 component tag
+		},
+		Descriptor{
+			AnnouncementSupport: &DescriptorAnnouncementSupport{
+				AnnouncementSupportIndicator: 0x3,
+				Announcements: []*DescriptorAnnouncementSupportAnnouncement{
+					{},
+					{
+						AnnouncementType:  1,
+						ComponentTag:      4,
+						OriginalNetworkID: 1,
+						ReferenceType:     1,
+						ServiceID:         3,
+						TransportStreamID: 2,
+					},
+				},
+			},
+			Length: 11,
+			Tag:    DescriptorTagAnnouncementSupport,
+		},
+	},
 	{
 		"UserDefined",
 		func(w *astikit.BitsWriter) {
@@ -561,14 +1288,14 @@ var descriptorTestTable = []descriptorTest{
 		func(w *astikit.BitsWriter) {
 			w.Write(uint8(DescriptorTagExtension)) // Tag
 			w.Write(uint8(5))                      // Length
-			w.Write(uint8(0))                      // Extension tag
+			w.Write(uint8(0x1))                    // Extension tag
 			w.Write([]byte("test"))                // Content
 		},
 		Descriptor{
 			Tag:    DescriptorTagExtension,
 			Length: 5,
 			Extension: &DescriptorExtension{
-				Tag:     0,
+				Tag:     0x1,
 				Unknown: &[]byte{'t', 'e', 's', 't'},
 			}},
 	},
@@ -664,6 +1391,203 @@ func TestWriteDescriptorAll(t *testing.T) {
 	assert.Equal(t, bufExpected.Bytes(), bufActual.Bytes())
 }
 
+func TestDescriptorTeletextPage(t *testing.T) {
+	buf := bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &buf})
+	w.Write([]byte("lan")) // Language
+	w.Write("00010")       // Type
+	w.Write("001")         // Magazine
+	w.Write(uint8(0x23))   // Page number
+
+	d, err := newDescriptorTeletext(astikit.NewBytesIterator(buf.Bytes()), buf.Len())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(d.Items))
+	assert.Equal(t, uint8(23), d.Items[0].Page)
+
+	bufActual := bytes.Buffer{}
+	wActual := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &bufActual})
+	assert.NoError(t, writeDescriptorTeletext(wActual, d))
+	assert.Equal(t, buf.Bytes(), bufActual.Bytes())
+}
+
+func TestDescriptorComponentDescribe(t *testing.T) {
+	for _, c := range []struct {
+		d *DescriptorComponent
+		s string
+	}{
+		{d: &DescriptorComponent{ComponentType: 0x05, StreamContent: 0x9, StreamContentExt: 0x0}, s: "HEVC ultra high definition video with PQ10 HDR with a frame rate lower than or equal to 60 Hz"},
+		{d: &DescriptorComponent{ComponentType: 0x04, StreamContent: 0xb, StreamContentExt: 0xf}, s: "HLG10 HDR"},
+		{d: &DescriptorComponent{ComponentType: 0x03, StreamContent: 0x6, StreamContentExt: 0xf}, s: "HE-AAC audio, stereo"},
+		{d: &DescriptorComponent{ComponentType: 0x10, StreamContent: 0x3}, s: "DVB subtitles (normal) with no monitor aspect ratio criticality"},
+		{d: &DescriptorComponent{ComponentType: 0x0b, StreamContent: 0x5, StreamContentExt: 0xf}, s: "H.264/AVC high definition video, 16:9 aspect ratio, 25 Hz"},
+		{d: &DescriptorComponent{ComponentType: 0xc4, StreamContent: 0x4, StreamContentExt: 0xf}, s: "Enhanced AC-3 audio"},
+		{d: &DescriptorComponent{ComponentType: 0xff, StreamContent: 0x9, StreamContentExt: 0x0}, s: "Unknown"},
+	} {
+		assert.Equal(t, c.s, c.d.Describe())
+	}
+}
+
+func captionServiceBytes() []byte {
+	buf := bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &buf})
+	w.Write("111")            // Reserved
+	w.Write("00010")          // Number of services
+	w.Write([]byte("eng"))    // Service #1 language
+	w.Write("1")              // Service #1 digital cc
+	w.Write("1")              // Service #1 reserved
+	w.Write("000011")         // Service #1 caption service number
+	w.Write("1")              // Service #1 easy reader
+	w.Write("0")              // Service #1 wide aspect ratio
+	w.Write("11111111111111") // Service #1 reserved
+	w.Write([]byte("spa"))    // Service #2 language
+	w.Write("0")              // Service #2 digital cc
+	w.Write("1")              // Service #2 reserved
+	w.Write("11111")          // Service #2 reserved
+	w.Write("1")              // Service #2 line21 field
+	w.Write("0")              // Service #2 easy reader
+	w.Write("1")              // Service #2 wide aspect ratio
+	w.Write("11111111111111") // Service #2 reserved
+	return buf.Bytes()
+}
+
+var captionService = &DescriptorCaptionService{Services: []*DescriptorCaptionServiceItem{
+	{CaptionServiceNumber: 3, DigitalCC: true, EasyReader: true, Language: []byte("eng")},
+	{Language: []byte("spa"), Line21Field: true, WideAspectRatio: true},
+}}
+
+func TestDescriptorCaptionService(t *testing.T) {
+	// User defined bytes are only interpreted on demand
+	ds := []*Descriptor{{Length: 13, Tag: DescriptorTagATSCCaptionService, UserDefined: captionServiceBytes()}}
+	assert.NoError(t, parseATSCDescriptors(ds))
+	assert.Equal(t, captionService, ds[0].CaptionService)
+
+	// Caption service is written in place of user defined bytes
+	buf := bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &buf})
+	_, err := writeDescriptor(w, &Descriptor{CaptionService: captionService, Length: 13, Tag: DescriptorTagATSCCaptionService})
+	assert.NoError(t, err)
+	assert.Equal(t, append([]byte{DescriptorTagATSCCaptionService, 13}, captionServiceBytes()...), buf.Bytes())
+}
+
+func TestParseDescriptorISO639LanguageAndAudioTypeShort(t *testing.T) {
+	for _, c := range []struct {
+		b    []byte
+		name string
+		ds   []*Descriptor
+	}{
+		{
+			b:    []byte{0x0, 0x5, DescriptorTagISO639LanguageAndAudioType, 0x3, 'e', 'n', AudioTypeCleanEffects},
+			name: "last",
+			ds: []*Descriptor{{
+				ISO639LanguageAndAudioType: &DescriptorISO639LanguageAndAudioType{Items: []*DescriptorISO639LanguageAndAudioTypeItem{
+					{Language: []byte("en"), Type: AudioTypeCleanEffects},
+				}},
+				Length: 3,
+				Tag:    DescriptorTagISO639LanguageAndAudioType,
+			}},
+		},
+		{
+			b:    []byte{0x0, 0x8, DescriptorTagISO639LanguageAndAudioType, 0x3, 'e', 'n', AudioTypeCleanEffects, DescriptorTagStreamIdentifier, 0x1, 0x2},
+			name: "followed by another descriptor",
+			ds: []*Descriptor{
+				{
+					ISO639LanguageAndAudioType: &DescriptorISO639LanguageAndAudioType{Items: []*DescriptorISO639LanguageAndAudioTypeItem{
+						{Language: []byte("en"), Type: AudioTypeCleanEffects},
+					}},
+					Length: 3,
+					Tag:    DescriptorTagISO639LanguageAndAudioType,
+				},
+				{
+					Length:           1,
+					StreamIdentifier: &DescriptorStreamIdentifier{ComponentTag: 0x2},
+					Tag:              DescriptorTagStreamIdentifier,
+				},
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			ds, err := parseDescriptors(astikit.NewBytesIterator(c.b))
+			assert.NoError(t, err)
+			assert.Equal(t, c.ds, ds)
+		})
+	}
+}
+
+func TestParseDescriptorLyingLengths(t *testing.T) {
+	for _, c := range []struct {
+		assert func(t *testing.T, d *Descriptor)
+		b      []byte
+		name   string
+	}{
+		{
+			assert: func(t *testing.T, d *Descriptor) {
+				assert.Equal(t, []*DescriptorExtendedEventItem{{Description: []byte("ab")}}, d.ExtendedEvent.Items)
+				assert.Equal(t, []byte(nil), d.ExtendedEvent.Text)
+			},
+			// Items length and description length go past the end of the descriptor
+			b:    []byte{DescriptorTagExtendedEvent, 8, 0x10, 'e', 'n', 'g', 0xff, 0xff, 'a', 'b'},
+			name: "extended event items",
+		},
+		{
+			assert: func(t *testing.T, d *Descriptor) {
+				assert.Equal(t, []byte("a"), d.ExtendedEvent.Text)
+			},
+			// Text length goes past the end of the descriptor
+			b:    []byte{DescriptorTagExtendedEvent, 7, 0x10, 'e', 'n', 'g', 0, 0xff, 'a'},
+			name: "extended event text",
+		},
+		{
+			assert: func(t *testing.T, d *Descriptor) {
+				assert.Equal(t, []byte("pro"), d.Service.Provider)
+				assert.Equal(t, []byte("na"), d.Service.Name)
+			},
+			// Name length goes past the end of the descriptor
+			b:    []byte{DescriptorTagService, 8, 1, 3, 'p', 'r', 'o', 0xff, 'n', 'a'},
+			name: "service name",
+		},
+		{
+			assert: func(t *testing.T, d *Descriptor) {
+				assert.Equal(t, []byte("pr"), d.Service.Provider)
+				assert.Equal(t, []byte(nil), d.Service.Name)
+			},
+			// Provider length goes past the end of the descriptor
+			b:    []byte{DescriptorTagService, 4, 1, 0xff, 'p', 'r'},
+			name: "service provider",
+		},
+		{
+			assert: func(t *testing.T, d *Descriptor) {
+				assert.Equal(t, []byte("ev"), d.ShortEvent.EventName)
+				assert.Equal(t, []byte(nil), d.ShortEvent.Text)
+			},
+			// Event length goes past the end of the descriptor
+			b:    []byte{DescriptorTagShortEvent, 6, 'e', 'n', 'g', 0xff, 'e', 'v'},
+			name: "short event name",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			// Lying descriptor is followed by a valid one that must not be swallowed
+			b := append(c.b, DescriptorTagStreamIdentifier, 1, 7)
+			ds, err := parseDescriptorsUntil(astikit.NewBytesIterator(b), len(b))
+			assert.NoError(t, err)
+			assert.Len(t, ds, 2)
+			c.assert(t, ds[0])
+			assert.Equal(t, &DescriptorStreamIdentifier{ComponentTag: 7}, ds[1].StreamIdentifier)
+		})
+	}
+}
+
+func TestFindDescriptor(t *testing.T) {
+	ds := []*Descriptor{
+		{Tag: DescriptorTagRegistration},
+		{Length: 1, Tag: DescriptorTagStuffing},
+		{Length: 2, Tag: DescriptorTagStuffing},
+	}
+	assert.Equal(t, ds[1], FindDescriptor(ds, DescriptorTagStuffing))
+	assert.Nil(t, FindDescriptor(ds, DescriptorTagCA))
+	assert.Equal(t, []*Descriptor{ds[1], ds[2]}, FindDescriptors(ds, DescriptorTagStuffing))
+	assert.Nil(t, FindDescriptors(ds, DescriptorTagCA))
+}
+
 func BenchmarkWriteDescriptor(b *testing.B) {
 	buf := bytes.Buffer{}
 	buf.Grow(1024)
@@ -11,6 +11,9 @@ import (
 // This field is coded as 16 bits giving the 16 LSBs of MJD followed by 24 bits coded as 6 digits in 4 - bit Binary
 // Coded Decimal (BCD). If the start time is undefined (e.g. for an event in a NVOD reference service) all bits of the
 // field are set to "1".
+// MJD is the number of days since 1858-11-17 00:00:00 UTC, its epoch: for instance 1993-01-01 is MJD 48988. The
+// conversion below is valid from 1900-03-01 to 2100-02-28.
+// The returned time is in UTC.
 // I apologize for the computation which is really messy but details are given in the documentation
 // Page: 160 | Annex C | Link: https://www.dvb.org/resources/public/standards/a38_dvb-si_specification.pdf
 // (barbashov) the link above can be broken, alternative: https://dvb.org/wp-content/uploads/2019/12/a038_tm1217r37_en300468v1_17_1_-_rev-134_-_si_specification.pdf
@@ -74,7 +77,11 @@ func parseDVBDurationByte(i byte) time.Duration {
 	return time.Duration(uint8(i)>>4*10 + uint8(i)&0xf)
 }
 
+// writeDVBTime writes a DVB time. See parseDVBTime for details about the format.
 func writeDVBTime(w *astikit.BitsWriter, t time.Time) (int, error) {
+	// Date and time must be computed in UTC, otherwise the date could be off by one day
+	t = t.UTC()
+
 	year := t.Year() - 1900
 	month := t.Month()
 	day := t.Day()
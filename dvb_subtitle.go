@@ -0,0 +1,447 @@
+package astits
+
+import (
+	"fmt"
+
+	"github.com/asticode/go-astikit"
+)
+
+// DVB subtitle data identifiers and markers
+const (
+	dvbSubtitleDataIdentifier          = 0x20
+	dvbSubtitleEndOfPESDataFieldMarker = 0xff
+	dvbSubtitleSyncByte                = 0x0f
+)
+
+// DVB subtitle segment types
+// Chapter: 7.2 | Link: https://www.etsi.org/deliver/etsi_en/300700_300799/300743/01.06.01_60/en_300743v010601p.pdf
+const (
+	DVBSubtitleSegmentTypeAlternativeCLUT    = 0x16
+	DVBSubtitleSegmentTypeCLUTDefinition     = 0x12
+	DVBSubtitleSegmentTypeDisparitySignaling = 0x15
+	DVBSubtitleSegmentTypeDisplayDefinition  = 0x14
+	DVBSubtitleSegmentTypeEndOfDisplaySet    = 0x80
+	DVBSubtitleSegmentTypeObjectData         = 0x13
+	DVBSubtitleSegmentTypePageComposition    = 0x10
+	DVBSubtitleSegmentTypeRegionComposition  = 0x11
+	DVBSubtitleSegmentTypeStuffing           = 0xff
+)
+
+// DVB subtitle object coding methods
+const (
+	DVBSubtitleObjectCodingMethodPixels     = 0x0
+	DVBSubtitleObjectCodingMethodCharacters = 0x1
+)
+
+// DVB subtitle region object types
+const (
+	DVBSubtitleObjectTypeBasicBitmap     = 0x0
+	DVBSubtitleObjectTypeBasicCharacter  = 0x1
+	DVBSubtitleObjectTypeCompositeString = 0x2
+)
+
+// DVBSubtitleSegment represents a DVB subtitle segment
+// Chapter: 7.2 | Link: https://www.etsi.org/deliver/etsi_en/300700_300799/300743/01.06.01_60/en_300743v010601p.pdf
+type DVBSubtitleSegment struct {
+	CLUTDefinition    *DVBSubtitleCLUTDefinition
+	Data              []byte // Raw data of segment types that are not parsed
+	ObjectData        *DVBSubtitleObjectData
+	PageComposition   *DVBSubtitlePageComposition
+	PageID            uint16
+	RegionComposition *DVBSubtitleRegionComposition
+	Type              uint8
+}
+
+// DVBSubtitlePageComposition represents a DVB subtitle page composition segment
+// Chapter: 7.2.2 | Link: https://www.etsi.org/deliver/etsi_en/300700_300799/300743/01.06.01_60/en_300743v010601p.pdf
+type DVBSubtitlePageComposition struct {
+	Regions       []*DVBSubtitlePageCompositionRegion
+	State         uint8
+	TimeOut       uint8 // In seconds
+	VersionNumber uint8
+}
+
+// DVBSubtitlePageCompositionRegion represents a region of a DVB subtitle page composition segment
+type DVBSubtitlePageCompositionRegion struct {
+	HorizontalAddress uint16
+	ID                uint8
+	VerticalAddress   uint16
+}
+
+// DVBSubtitleRegionComposition represents a DVB subtitle region composition segment
+// Chapter: 7.2.3 | Link: https://www.etsi.org/deliver/etsi_en/300700_300799/300743/01.06.01_60/en_300743v010601p.pdf
+type DVBSubtitleRegionComposition struct {
+	CLUTID               uint8
+	Depth                uint8
+	FillFlag             bool
+	Height               uint16
+	ID                   uint8
+	LevelOfCompatibility uint8
+	Objects              []*DVBSubtitleRegionCompositionObject
+	PixelCode2Bit        uint8
+	PixelCode4Bit        uint8
+	PixelCode8Bit        uint8
+	VersionNumber        uint8
+	Width                uint16
+}
+
+// DVBSubtitleRegionCompositionObject represents an object of a DVB subtitle region composition segment
+type DVBSubtitleRegionCompositionObject struct {
+	BackgroundPixelCode uint8
+	ForegroundPixelCode uint8
+	HorizontalPosition  uint16
+	ID                  uint16
+	ProviderFlag        uint8
+	Type                uint8
+	VerticalPosition    uint16
+}
+
+// DVBSubtitleCLUTDefinition represents a DVB subtitle CLUT definition segment
+// Chapter: 7.2.4 | Link: https://www.etsi.org/deliver/etsi_en/300700_300799/300743/01.06.01_60/en_300743v010601p.pdf
+type DVBSubtitleCLUTDefinition struct {
+	Entries       []*DVBSubtitleCLUTEntry
+	ID            uint8
+	VersionNumber uint8
+}
+
+// DVBSubtitleCLUTEntry represents an entry of a DVB subtitle CLUT definition segment
+// When FullRangeFlag is false, values are stored with their reduced precision: 6 bits for Y, 4 bits for Cr and Cb
+// and 2 bits for T
+type DVBSubtitleCLUTEntry struct {
+	Cb            uint8
+	Cr            uint8
+	FullRangeFlag bool
+	ID            uint8
+	Is2Bit        bool
+	Is4Bit        bool
+	Is8Bit        bool
+	T             uint8
+	Y             uint8
+}
+
+// DVBSubtitleObjectData represents a DVB subtitle object data segment
+// Pixel data sub-blocks are not decoded
+// Chapter: 7.2.5 | Link: https://www.etsi.org/deliver/etsi_en/300700_300799/300743/01.06.01_60/en_300743v010601p.pdf
+type DVBSubtitleObjectData struct {
+	BottomFieldData        []byte
+	CharacterCodes         []uint16
+	CodingMethod           uint8
+	ID                     uint16
+	NonModifyingColourFlag bool
+	TopFieldData           []byte
+	VersionNumber          uint8
+}
+
+// ParseDVBSubtitleSegments parses the segments of a DVB subtitle PES data field
+func ParseDVBSubtitleSegments(pesData []byte) (ss []*DVBSubtitleSegment, err error) {
+	// Create iterator
+	i := astikit.NewBytesIterator(pesData)
+
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(2); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Data identifier
+	if bs[0] != dvbSubtitleDataIdentifier {
+		err = fmt.Errorf("astits: data identifier %#x is not a DVB subtitle one", bs[0])
+		return
+	}
+
+	// Loop through segments
+	for i.HasBytesLeft() {
+		// Get next byte
+		var b byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+
+		// End of PES data field
+		if b == dvbSubtitleEndOfPESDataFieldMarker {
+			break
+		} else if b != dvbSubtitleSyncByte {
+			err = fmt.Errorf("astits: sync byte %#x is invalid", b)
+			return
+		}
+
+		// Parse segment
+		var s *DVBSubtitleSegment
+		if s, err = parseDVBSubtitleSegment(i); err != nil {
+			err = fmt.Errorf("astits: parsing DVB subtitle segment failed: %w", err)
+			return
+		}
+
+		// Append segment
+		ss = append(ss, s)
+	}
+	return
+}
+
+func parseDVBSubtitleSegment(i *astikit.BytesIterator) (s *DVBSubtitleSegment, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(5); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create segment
+	s = &DVBSubtitleSegment{
+		PageID: uint16(bs[1])<<8 | uint16(bs[2]),
+		Type:   uint8(bs[0]),
+	}
+
+	// Segment length
+	segmentLength := int(uint16(bs[3])<<8 | uint16(bs[4]))
+
+	// Get segment data
+	if bs, err = i.NextBytes(segmentLength); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+	si := astikit.NewBytesIterator(bs)
+
+	// Switch on type
+	switch s.Type {
+	case DVBSubtitleSegmentTypeCLUTDefinition:
+		if s.CLUTDefinition, err = parseDVBSubtitleCLUTDefinition(si); err != nil {
+			err = fmt.Errorf("astits: parsing CLUT definition failed: %w", err)
+			return
+		}
+	case DVBSubtitleSegmentTypeObjectData:
+		if s.ObjectData, err = parseDVBSubtitleObjectData(si); err != nil {
+			err = fmt.Errorf("astits: parsing object data failed: %w", err)
+			return
+		}
+	case DVBSubtitleSegmentTypePageComposition:
+		if s.PageComposition, err = parseDVBSubtitlePageComposition(si); err != nil {
+			err = fmt.Errorf("astits: parsing page composition failed: %w", err)
+			return
+		}
+	case DVBSubtitleSegmentTypeRegionComposition:
+		if s.RegionComposition, err = parseDVBSubtitleRegionComposition(si); err != nil {
+			err = fmt.Errorf("astits: parsing region composition failed: %w", err)
+			return
+		}
+	default:
+		s.Data = bs
+	}
+	return
+}
+
+func parseDVBSubtitlePageComposition(i *astikit.BytesIterator) (d *DVBSubtitlePageComposition, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(2); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create page composition
+	d = &DVBSubtitlePageComposition{
+		State:         uint8(bs[1]>>2) & 0x3,
+		TimeOut:       uint8(bs[0]),
+		VersionNumber: uint8(bs[1] >> 4),
+	}
+
+	// Add regions
+	for i.HasBytesLeft() {
+		// Get next bytes
+		if bs, err = i.NextBytesNoCopy(6); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Append region
+		d.Regions = append(d.Regions, &DVBSubtitlePageCompositionRegion{
+			HorizontalAddress: uint16(bs[2])<<8 | uint16(bs[3]),
+			ID:                uint8(bs[0]),
+			VerticalAddress:   uint16(bs[4])<<8 | uint16(bs[5]),
+		})
+	}
+	return
+}
+
+func parseDVBSubtitleRegionComposition(i *astikit.BytesIterator) (d *DVBSubtitleRegionComposition, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(10); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create region composition
+	d = &DVBSubtitleRegionComposition{
+		CLUTID:               uint8(bs[7]),
+		Depth:                uint8(bs[6]>>2) & 0x7,
+		FillFlag:             bs[1]&0x8 > 0,
+		Height:               uint16(bs[4])<<8 | uint16(bs[5]),
+		ID:                   uint8(bs[0]),
+		LevelOfCompatibility: uint8(bs[6] >> 5),
+		PixelCode2Bit:        uint8(bs[9]>>2) & 0x3,
+		PixelCode4Bit:        uint8(bs[9] >> 4),
+		PixelCode8Bit:        uint8(bs[8]),
+		VersionNumber:        uint8(bs[1] >> 4),
+		Width:                uint16(bs[2])<<8 | uint16(bs[3]),
+	}
+
+	// Add objects
+	for i.HasBytesLeft() {
+		// Get next bytes
+		if bs, err = i.NextBytesNoCopy(6); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Create object
+		o := &DVBSubtitleRegionCompositionObject{
+			HorizontalPosition: uint16(bs[2]&0xf)<<8 | uint16(bs[3]),
+			ID:                 uint16(bs[0])<<8 | uint16(bs[1]),
+			ProviderFlag:       uint8(bs[2]>>4) & 0x3,
+			Type:               uint8(bs[2] >> 6),
+			VerticalPosition:   uint16(bs[4]&0xf)<<8 | uint16(bs[5]),
+		}
+
+		// Character objects have pixel codes
+		if o.Type == DVBSubtitleObjectTypeBasicCharacter || o.Type == DVBSubtitleObjectTypeCompositeString {
+			// Get next bytes
+			if bs, err = i.NextBytesNoCopy(2); err != nil {
+				err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+				return
+			}
+
+			// Pixel codes
+			o.ForegroundPixelCode = uint8(bs[0])
+			o.BackgroundPixelCode = uint8(bs[1])
+		}
+
+		// Append object
+		d.Objects = append(d.Objects, o)
+	}
+	return
+}
+
+func parseDVBSubtitleCLUTDefinition(i *astikit.BytesIterator) (d *DVBSubtitleCLUTDefinition, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(2); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create CLUT definition
+	d = &DVBSubtitleCLUTDefinition{
+		ID:            uint8(bs[0]),
+		VersionNumber: uint8(bs[1] >> 4),
+	}
+
+	// Add entries
+	for i.HasBytesLeft() {
+		// Get next bytes
+		if bs, err = i.NextBytesNoCopy(2); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Create entry
+		e := &DVBSubtitleCLUTEntry{
+			FullRangeFlag: bs[1]&0x1 > 0,
+			ID:            uint8(bs[0]),
+			Is2Bit:        bs[1]&0x80 > 0,
+			Is4Bit:        bs[1]&0x40 > 0,
+			Is8Bit:        bs[1]&0x20 > 0,
+		}
+
+		// Full range
+		if e.FullRangeFlag {
+			// Get next bytes
+			if bs, err = i.NextBytesNoCopy(4); err != nil {
+				err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+				return
+			}
+
+			// Values
+			e.Y = uint8(bs[0])
+			e.Cr = uint8(bs[1])
+			e.Cb = uint8(bs[2])
+			e.T = uint8(bs[3])
+		} else {
+			// Get next bytes
+			if bs, err = i.NextBytesNoCopy(2); err != nil {
+				err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+				return
+			}
+
+			// Values
+			e.Y = uint8(bs[0] >> 2)
+			e.Cr = uint8(bs[0]&0x3)<<2 | uint8(bs[1]>>6)
+			e.Cb = uint8(bs[1]>>2) & 0xf
+			e.T = uint8(bs[1] & 0x3)
+		}
+
+		// Append entry
+		d.Entries = append(d.Entries, e)
+	}
+	return
+}
+
+func parseDVBSubtitleObjectData(i *astikit.BytesIterator) (d *DVBSubtitleObjectData, err error) {
+	// Get next bytes
+	var bs []byte
+	if bs, err = i.NextBytesNoCopy(3); err != nil {
+		err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+		return
+	}
+
+	// Create object data
+	d = &DVBSubtitleObjectData{
+		CodingMethod:           uint8(bs[2]>>2) & 0x3,
+		ID:                     uint16(bs[0])<<8 | uint16(bs[1]),
+		NonModifyingColourFlag: bs[2]&0x2 > 0,
+		VersionNumber:          uint8(bs[2] >> 4),
+	}
+
+	// Switch on coding method
+	switch d.CodingMethod {
+	case DVBSubtitleObjectCodingMethodPixels:
+		// Get next bytes
+		if bs, err = i.NextBytesNoCopy(4); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Top field data
+		if d.TopFieldData, err = i.NextBytes(int(uint16(bs[0])<<8 | uint16(bs[1]))); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Bottom field data
+		if d.BottomFieldData, err = i.NextBytes(int(uint16(bs[2])<<8 | uint16(bs[3]))); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+	case DVBSubtitleObjectCodingMethodCharacters:
+		// Get next byte
+		var b byte
+		if b, err = i.NextByte(); err != nil {
+			err = fmt.Errorf("astits: fetching next byte failed: %w", err)
+			return
+		}
+
+		// Get next bytes
+		if bs, err = i.NextBytesNoCopy(2 * int(b)); err != nil {
+			err = fmt.Errorf("astits: fetching next bytes failed: %w", err)
+			return
+		}
+
+		// Character codes
+		for idx := 0; idx < len(bs); idx += 2 {
+			d.CharacterCodes = append(d.CharacterCodes, uint16(bs[idx])<<8|uint16(bs[idx+1]))
+		}
+	}
+	return
+}
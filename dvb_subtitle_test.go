@@ -0,0 +1,236 @@
+package astits
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+var dvbSubtitleSegments = []*DVBSubtitleSegment{
+	{
+		PageComposition: &DVBSubtitlePageComposition{
+			Regions: []*DVBSubtitlePageCompositionRegion{{
+				HorizontalAddress: 100,
+				ID:                1,
+				VerticalAddress:   400,
+			}},
+			State:         2,
+			TimeOut:       10,
+			VersionNumber: 3,
+		},
+		PageID: 1,
+		Type:   DVBSubtitleSegmentTypePageComposition,
+	},
+	{
+		PageID: 1,
+		RegionComposition: &DVBSubtitleRegionComposition{
+			CLUTID:               2,
+			Depth:                2,
+			FillFlag:             true,
+			Height:               40,
+			ID:                   1,
+			LevelOfCompatibility: 2,
+			Objects: []*DVBSubtitleRegionCompositionObject{
+				{
+					HorizontalPosition: 5,
+					ID:                 7,
+					VerticalPosition:   6,
+				},
+				{
+					BackgroundPixelCode: 9,
+					ForegroundPixelCode: 8,
+					HorizontalPosition:  10,
+					ID:                  8,
+					ProviderFlag:        1,
+					Type:                DVBSubtitleObjectTypeBasicCharacter,
+					VerticalPosition:    11,
+				},
+			},
+			PixelCode2Bit: 1,
+			PixelCode4Bit: 4,
+			PixelCode8Bit: 3,
+			VersionNumber: 1,
+			Width:         720,
+		},
+		Type: DVBSubtitleSegmentTypeRegionComposition,
+	},
+	{
+		CLUTDefinition: &DVBSubtitleCLUTDefinition{
+			Entries: []*DVBSubtitleCLUTEntry{
+				{
+					Cb:            3,
+					Cr:            2,
+					FullRangeFlag: true,
+					ID:            1,
+					Is4Bit:        true,
+					T:             4,
+					Y:             1,
+				},
+				{
+					Cb:     9,
+					Cr:     6,
+					ID:     2,
+					Is2Bit: true,
+					T:      1,
+					Y:      35,
+				},
+			},
+			ID:            2,
+			VersionNumber: 5,
+		},
+		PageID: 1,
+		Type:   DVBSubtitleSegmentTypeCLUTDefinition,
+	},
+	{
+		ObjectData: &DVBSubtitleObjectData{
+			BottomFieldData:        []byte{3},
+			ID:                     7,
+			NonModifyingColourFlag: true,
+			TopFieldData:           []byte{1, 2},
+			VersionNumber:          2,
+		},
+		PageID: 1,
+		Type:   DVBSubtitleSegmentTypeObjectData,
+	},
+	{
+		ObjectData: &DVBSubtitleObjectData{
+			CharacterCodes: []uint16{0x41, 0x42},
+			CodingMethod:   DVBSubtitleObjectCodingMethodCharacters,
+			ID:             8,
+		},
+		PageID: 1,
+		Type:   DVBSubtitleSegmentTypeObjectData,
+	},
+	{
+		Data:   []byte{},
+		PageID: 1,
+		Type:   DVBSubtitleSegmentTypeEndOfDisplaySet,
+	},
+}
+
+func dvbSubtitleBytes() []byte {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	w.Write(uint8(0x20))                                        // Data identifier
+	w.Write(uint8(0))                                           // Subtitle stream id
+	w.Write(uint8(0x0f))                                        // Segment #1 sync byte
+	w.Write(uint8(DVBSubtitleSegmentTypePageComposition))       // Segment #1 type
+	w.Write(uint16(1))                                          // Segment #1 page id
+	w.Write(uint16(8))                                          // Segment #1 length
+	w.Write(uint8(10))                                          // Segment #1 time out
+	w.WriteN(uint8(3), 4)                                       // Segment #1 version number
+	w.WriteN(uint8(2), 2)                                       // Segment #1 state
+	w.Write("11")                                               // Segment #1 reserved
+	w.Write(uint8(1))                                           // Segment #1 region #1 id
+	w.Write(uint8(0xff))                                        // Segment #1 region #1 reserved
+	w.Write(uint16(100))                                        // Segment #1 region #1 horizontal address
+	w.Write(uint16(400))                                        // Segment #1 region #1 vertical address
+	w.Write(uint8(0x0f))                                        // Segment #2 sync byte
+	w.Write(uint8(DVBSubtitleSegmentTypeRegionComposition))     // Segment #2 type
+	w.Write(uint16(1))                                          // Segment #2 page id
+	w.Write(uint16(24))                                         // Segment #2 length
+	w.Write(uint8(1))                                           // Segment #2 region id
+	w.WriteN(uint8(1), 4)                                       // Segment #2 version number
+	w.Write("1")                                                // Segment #2 fill flag
+	w.Write("111")                                              // Segment #2 reserved
+	w.Write(uint16(720))                                        // Segment #2 width
+	w.Write(uint16(40))                                         // Segment #2 height
+	w.WriteN(uint8(2), 3)                                       // Segment #2 level of compatibility
+	w.WriteN(uint8(2), 3)                                       // Segment #2 depth
+	w.Write("11")                                               // Segment #2 reserved
+	w.Write(uint8(2))                                           // Segment #2 CLUT id
+	w.Write(uint8(3))                                           // Segment #2 8-bit pixel code
+	w.WriteN(uint8(4), 4)                                       // Segment #2 4-bit pixel code
+	w.WriteN(uint8(1), 2)                                       // Segment #2 2-bit pixel code
+	w.Write("11")                                               // Segment #2 reserved
+	w.Write(uint16(7))                                          // Segment #2 object #1 id
+	w.WriteN(uint8(DVBSubtitleObjectTypeBasicBitmap), 2)        // Segment #2 object #1 type
+	w.WriteN(uint8(0), 2)                                       // Segment #2 object #1 provider flag
+	w.WriteN(uint16(5), 12)                                     // Segment #2 object #1 horizontal position
+	w.Write("1111")                                             // Segment #2 object #1 reserved
+	w.WriteN(uint16(6), 12)                                     // Segment #2 object #1 vertical position
+	w.Write(uint16(8))                                          // Segment #2 object #2 id
+	w.WriteN(uint8(DVBSubtitleObjectTypeBasicCharacter), 2)     // Segment #2 object #2 type
+	w.WriteN(uint8(1), 2)                                       // Segment #2 object #2 provider flag
+	w.WriteN(uint16(10), 12)                                    // Segment #2 object #2 horizontal position
+	w.Write("1111")                                             // Segment #2 object #2 reserved
+	w.WriteN(uint16(11), 12)                                    // Segment #2 object #2 vertical position
+	w.Write(uint8(8))                                           // Segment #2 object #2 foreground pixel code
+	w.Write(uint8(9))                                           // Segment #2 object #2 background pixel code
+	w.Write(uint8(0x0f))                                        // Segment #3 sync byte
+	w.Write(uint8(DVBSubtitleSegmentTypeCLUTDefinition))        // Segment #3 type
+	w.Write(uint16(1))                                          // Segment #3 page id
+	w.Write(uint16(12))                                         // Segment #3 length
+	w.Write(uint8(2))                                           // Segment #3 CLUT id
+	w.WriteN(uint8(5), 4)                                       // Segment #3 version number
+	w.Write("1111")                                             // Segment #3 reserved
+	w.Write(uint8(1))                                           // Segment #3 entry #1 id
+	w.Write("010")                                              // Segment #3 entry #1 2-bit/4-bit/8-bit flags
+	w.Write("1111")                                             // Segment #3 entry #1 reserved
+	w.Write("1")                                                // Segment #3 entry #1 full range flag
+	w.Write([]byte{1, 2, 3, 4})                                 // Segment #3 entry #1 Y, Cr, Cb and T
+	w.Write(uint8(2))                                           // Segment #3 entry #2 id
+	w.Write("100")                                              // Segment #3 entry #2 2-bit/4-bit/8-bit flags
+	w.Write("1111")                                             // Segment #3 entry #2 reserved
+	w.Write("0")                                                // Segment #3 entry #2 full range flag
+	w.WriteN(uint8(35), 6)                                      // Segment #3 entry #2 Y
+	w.WriteN(uint8(6), 4)                                       // Segment #3 entry #2 Cr
+	w.WriteN(uint8(9), 4)                                       // Segment #3 entry #2 Cb
+	w.WriteN(uint8(1), 2)                                       // Segment #3 entry #2 T
+	w.Write(uint8(0x0f))                                        // Segment #4 sync byte
+	w.Write(uint8(DVBSubtitleSegmentTypeObjectData))            // Segment #4 type
+	w.Write(uint16(1))                                          // Segment #4 page id
+	w.Write(uint16(10))                                         // Segment #4 length
+	w.Write(uint16(7))                                          // Segment #4 object id
+	w.WriteN(uint8(2), 4)                                       // Segment #4 version number
+	w.WriteN(uint8(DVBSubtitleObjectCodingMethodPixels), 2)     // Segment #4 coding method
+	w.Write("1")                                                // Segment #4 non modifying colour flag
+	w.Write("1")                                                // Segment #4 reserved
+	w.Write(uint16(2))                                          // Segment #4 top field data block length
+	w.Write(uint16(1))                                          // Segment #4 bottom field data block length
+	w.Write([]byte{1, 2})                                       // Segment #4 top field data
+	w.Write([]byte{3})                                          // Segment #4 bottom field data
+	w.Write(uint8(0x0f))                                        // Segment #5 sync byte
+	w.Write(uint8(DVBSubtitleSegmentTypeObjectData))            // Segment #5 type
+	w.Write(uint16(1))                                          // Segment #5 page id
+	w.Write(uint16(8))                                          // Segment #5 length
+	w.Write(uint16(8))                                          // Segment #5 object id
+	w.WriteN(uint8(0), 4)                                       // Segment #5 version number
+	w.WriteN(uint8(DVBSubtitleObjectCodingMethodCharacters), 2) // Segment #5 coding method
+	w.Write("0")                                                // Segment #5 non modifying colour flag
+	w.Write("1")                                                // Segment #5 reserved
+	w.Write(uint8(2))                                           // Segment #5 number of codes
+	w.Write(uint16(0x41))                                       // Segment #5 character code #1
+	w.Write(uint16(0x42))                                       // Segment #5 character code #2
+	w.Write(uint8(0x0f))                                        // Segment #6 sync byte
+	w.Write(uint8(DVBSubtitleSegmentTypeEndOfDisplaySet))       // Segment #6 type
+	w.Write(uint16(1))                                          // Segment #6 page id
+	w.Write(uint16(0))                                          // Segment #6 length
+	w.Write(uint8(0xff))                                        // End of PES data field marker
+	return buf.Bytes()
+}
+
+func TestParseDVBSubtitleSegments(t *testing.T) {
+	ss, err := ParseDVBSubtitleSegments(dvbSubtitleBytes())
+	assert.NoError(t, err)
+	assert.Equal(t, dvbSubtitleSegments, ss)
+
+	// Invalid data identifier
+	b := dvbSubtitleBytes()
+	b[0] = 0x10
+	_, err = ParseDVBSubtitleSegments(b)
+	assert.Error(t, err)
+
+	// Invalid sync byte
+	b = dvbSubtitleBytes()
+	b[2] = 0x0e
+	_, err = ParseDVBSubtitleSegments(b)
+	assert.Error(t, err)
+
+	// Truncated segment
+	b = dvbSubtitleBytes()
+	_, err = ParseDVBSubtitleSegments(b[:10])
+	assert.Error(t, err)
+}
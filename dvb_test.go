@@ -24,6 +24,34 @@ func TestParseDVBTime(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestDVBTimeEdges(t *testing.T) {
+	for _, v := range []struct {
+		b []byte
+		t time.Time
+	}{
+		{b: []byte{0xbf, 0x5c, 0x0, 0x0, 0x0}, t: time.Date(1993, 1, 1, 0, 0, 0, 0, time.UTC)},         // MJD 48988
+		{b: []byte{0xbf, 0x5b, 0x23, 0x59, 0x59}, t: time.Date(1992, 12, 31, 23, 59, 59, 0, time.UTC)}, // MJD 48987
+		{b: []byte{0xc9, 0x93, 0x12, 0x0, 0x0}, t: time.Date(2000, 2, 29, 12, 0, 0, 0, time.UTC)},      // MJD 51603
+		{b: []byte{0xeb, 0xd1, 0x23, 0x59, 0x59}, t: time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC)},  // MJD 60369
+		{b: []byte{0xeb, 0xd2, 0x0, 0x0, 0x0}, t: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},         // MJD 60370
+	} {
+		d, err := parseDVBTime(astikit.NewBytesIterator(v.b))
+		assert.NoError(t, err)
+		assert.Equal(t, v.t, d)
+
+		buf := &bytes.Buffer{}
+		_, err = writeDVBTime(astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf}), v.t)
+		assert.NoError(t, err)
+		assert.Equal(t, v.b, buf.Bytes())
+	}
+
+	// Time in another location is written in UTC
+	buf := &bytes.Buffer{}
+	_, err := writeDVBTime(astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf}), time.Date(2024, 3, 1, 1, 59, 59, 0, time.FixedZone("UTC+2", 2*3600)))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xeb, 0xd1, 0x23, 0x59, 0x59}, buf.Bytes())
+}
+
 func TestParseDVBDurationMinutes(t *testing.T) {
 	d, err := parseDVBDurationMinutes(astikit.NewBytesIterator(dvbDurationMinutesBytes))
 	assert.Equal(t, dvbDurationMinutes, d)
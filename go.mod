@@ -4,6 +4,7 @@ go 1.13
 
 require (
 	github.com/asticode/go-astikit v0.30.0
+	github.com/klauspost/compress v1.15.15
 	github.com/pkg/profile v1.4.0
 	github.com/stretchr/testify v1.4.0
 )
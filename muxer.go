@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/asticode/go-astikit"
 )
@@ -19,6 +21,10 @@ var (
 	ErrPIDNotFound      = errors.New("astits: PID not found")
 	ErrPIDAlreadyExists = errors.New("astits: PID already exists")
 	ErrPCRPIDInvalid    = errors.New("astits: PCR PID invalid")
+	ErrPESPacketTooLong = errors.New("astits: PES packet too long")
+	ErrMuxerDataEmpty   = errors.New("astits: muxer data has neither PES nor adaptation field")
+	ErrMuxerClosed      = errors.New("astits: muxer is closed")
+	ErrMissingPESHeader = errors.New("astits: PES header is missing")
 )
 
 type Muxer struct {
@@ -26,8 +32,12 @@ type Muxer struct {
 	w          io.Writer
 	bitsWriter *astikit.BitsWriter
 
+	closed                 bool
 	packetSize             int
+	tablesOnClose          bool
 	tablesRetransmitPeriod int // period in PES packets
+	transportStreamID      uint16
+	videoPESPacketLength   bool
 
 	pm         *programMap // pid -> programNumber
 	pmUpdated  bool
@@ -36,8 +46,8 @@ type Muxer struct {
 	nextPID    uint16
 	patVersion wrappingCounter
 	pmtVersion wrappingCounter
-	patCC      wrappingCounter
-	pmtCC      wrappingCounter
+	sdtData    []byte
+	sdtVersion wrappingCounter
 
 	patBytes bytes.Buffer
 	pmtBytes bytes.Buffer
@@ -45,20 +55,29 @@ type Muxer struct {
 	buf       bytes.Buffer
 	bufWriter *astikit.BitsWriter
 
+	packetInfos *[]PacketInfo // Only set while WriteDataVerbose is running
+
 	// We use map[uint32] instead map[uint16] as go runtime provide optimized hash functions for (u)int32/64 keys
+	ccs                     map[uint32]*wrappingCounter // pid -> continuity counter, shared by tables and elementary streams
 	esContexts              map[uint32]*esContext
 	tablesRetransmitCounter int
 }
 
+// PacketInfo represents metadata of a packet written by the muxer
+type PacketInfo struct {
+	ContinuityCounter         uint8
+	HasPayload                bool
+	PID                       uint16
+	PayloadUnitStartIndicator bool
+}
+
 type esContext struct {
 	es *PMTElementaryStream
-	cc wrappingCounter
 }
 
 func newEsContext(es *PMTElementaryStream) *esContext {
 	return &esContext{
 		es: es,
-		cc: newWrappingCounter(0b1111), // CC is 4 bits
 	}
 }
 
@@ -68,8 +87,43 @@ func MuxerOptTablesRetransmitPeriod(newPeriod int) func(*Muxer) {
 	}
 }
 
+// MuxerOptTablesOnClose makes Close write a final set of tables
+func MuxerOptTablesOnClose(v bool) func(*Muxer) {
+	return func(m *Muxer) {
+		m.tablesOnClose = v
+	}
+}
+
+// MuxerOptContinuityCounterStart seeds the continuity counters of the provided PIDs, which is useful to reproduce
+// reference streams bit-exactly
+func MuxerOptContinuityCounterStart(ccs map[uint16]uint8) func(*Muxer) {
+	return func(m *Muxer) {
+		for pid, cc := range ccs {
+			m.SetContinuityCounter(pid, cc)
+		}
+	}
+}
+
+// MuxerOptTransportStreamID sets the transport stream id written in the PAT and the SDT
+func MuxerOptTransportStreamID(id uint16) func(*Muxer) {
+	return func(m *Muxer) {
+		m.transportStreamID = id
+	}
+}
+
+// MuxerOptVideoPESPacketLength makes the muxer write the packet length of video PES packets whenever it fits, instead
+// of always writing zero
+func MuxerOptVideoPESPacketLength(v bool) func(*Muxer) {
+	return func(m *Muxer) {
+		m.videoPESPacketLength = v
+	}
+}
+
 // TODO MuxerOptAutodetectPCRPID selecting first video PID for each PMT, falling back to first audio, falling back to any other
 
+// NewMuxer creates a new muxer
+// The muxer doesn't own w: it is neither flushed nor closed by the muxer, which is up to the caller once Close has
+// been called
 func NewMuxer(ctx context.Context, w io.Writer, opts ...func(*Muxer)) *Muxer {
 	m := &Muxer{
 		ctx: ctx,
@@ -87,10 +141,9 @@ func NewMuxer(ctx context.Context, w io.Writer, opts ...func(*Muxer)) *Muxer {
 		// table version is 5-bit field
 		patVersion: newWrappingCounter(0b11111),
 		pmtVersion: newWrappingCounter(0b11111),
+		sdtVersion: newWrappingCounter(0b11111),
 
-		patCC: newWrappingCounter(0b1111),
-		pmtCC: newWrappingCounter(0b1111),
-
+		ccs:        map[uint32]*wrappingCounter{},
 		esContexts: map[uint32]*esContext{},
 	}
 
@@ -133,6 +186,22 @@ func (m *Muxer) AddElementaryStream(es PMTElementaryStream) error {
 	return nil
 }
 
+// AddElementaryStreamWithRegistration adds an elementary stream whose PMT entry carries a registration
+// descriptor built from the provided format identifier (e.g. "Opus", "AV01")
+func (m *Muxer) AddElementaryStreamWithRegistration(es PMTElementaryStream, fourCC [4]byte) error {
+	// Copy descriptors so that the caller's slice is not modified
+	ds := make([]*Descriptor, 0, len(es.ElementaryStreamDescriptors)+1)
+	ds = append(ds, es.ElementaryStreamDescriptors...)
+	es.ElementaryStreamDescriptors = append(ds, &Descriptor{
+		Length: 4,
+		Registration: &DescriptorRegistration{
+			FormatIdentifier: uint32(fourCC[0])<<24 | uint32(fourCC[1])<<16 | uint32(fourCC[2])<<8 | uint32(fourCC[3]),
+		},
+		Tag: DescriptorTagRegistration,
+	})
+	return m.AddElementaryStream(es)
+}
+
 func (m *Muxer) RemoveElementaryStream(pid uint16) error {
 	foundIdx := -1
 	for i, oes := range m.pmt.ElementaryStreams {
@@ -159,15 +228,38 @@ func (m *Muxer) SetPCRPID(pid uint16) {
 	m.pmtUpdated = true
 }
 
+// SetContinuityCounter sets the continuity counter of the next packet carrying a payload written on pid
+func (m *Muxer) SetContinuityCounter(pid uint16, cc uint8) {
+	// Counter is incremented right before writing a packet carrying a payload
+	c := newWrappingCounter(0b1111) // CC is 4 bits
+	if cc &= 0b1111; cc > 0 {
+		c.value = int(cc) - 1
+	}
+	m.ccs[uint32(pid)] = &c
+}
+
 // WriteData writes MuxerData to TS stream
 // Currently only PES packets are supported
+// If d.PES is nil, a single adaptation field only packet is written, which is useful to write a PCR without payload
+// Data whose PID is not a registered elementary stream, which is empty, whose PES header is missing or whose non-video
+// PES is too long for its packet length is rejected before anything is written with ErrPIDNotFound, ErrMuxerDataEmpty,
+// ErrMissingPESHeader or ErrPESPacketTooLong
 // Be aware that after successful call WriteData will set d.AdaptationField.StuffingLength value to zero
 func (m *Muxer) WriteData(d *MuxerData) (int, error) {
-	ctx, ok := m.esContexts[uint32(d.PID)]
-	if !ok {
-		return 0, ErrPIDNotFound
+	if m.closed {
+		return 0, ErrMuxerClosed
+	}
+
+	if err := m.validateData(d); err != nil {
+		return 0, err
+	}
+
+	if d.PES == nil {
+		return m.writeAdaptationFieldOnly(d.PID, d.AdaptationField)
 	}
 
+	ctx := m.esContexts[uint32(d.PID)]
+
 	bytesWritten := 0
 
 	forceTables := d.AdaptationField != nil &&
@@ -188,7 +280,6 @@ func (m *Muxer) WriteData(d *MuxerData) (int, error) {
 		pktLen := 1 + mpegTsPacketHeaderSize // sync byte + header
 		pkt := Packet{
 			Header: PacketHeader{
-				ContinuityCounter:         uint8(ctx.cc.inc()),
 				HasAdaptationField:        writeAf,
 				HasPayload:                false,
 				PayloadUnitStartIndicator: false,
@@ -233,6 +324,7 @@ func (m *Muxer) WriteData(d *MuxerData) (int, error) {
 				d.PES.Header,
 				d.PES.Data[payloadBytesWritten:],
 				payloadStart,
+				m.videoPESPacketLength,
 				bytesAvailable,
 			)
 			if err != nil {
@@ -255,10 +347,12 @@ func (m *Muxer) WriteData(d *MuxerData) (int, error) {
 				}
 			}
 
+			pkt.Header.ContinuityCounter = m.continuityCounter(d.PID, true)
 			n, err = writePacket(m.bitsWriter, &pkt, m.packetSize)
 			if err != nil {
 				return bytesWritten, err
 			}
+			m.addPacketInfo(pkt.Header)
 
 			bytesWritten += n
 
@@ -273,9 +367,341 @@ func (m *Muxer) WriteData(d *MuxerData) (int, error) {
 	return bytesWritten, nil
 }
 
+// validateData checks upfront that d can be written, so that no invalid output is produced
+func (m *Muxer) validateData(d *MuxerData) error {
+	ctx, ok := m.esContexts[uint32(d.PID)]
+	if !ok {
+		return ErrPIDNotFound
+	}
+
+	if d.PES == nil {
+		if d.AdaptationField == nil {
+			return ErrMuxerDataEmpty
+		}
+		return nil
+	}
+
+	if d.PES.Header == nil {
+		return ErrMissingPESHeader
+	}
+
+	// Only video PES packets can have a zero packet length
+	h := *d.PES.Header
+	if h.StreamID == 0 {
+		h.StreamID = ctx.es.StreamType.ToPESStreamID()
+	}
+	if l := calcPESPacketLength(&h, len(d.PES.Data)); !h.IsVideoStream() && l > 0xffff {
+		return fmt.Errorf("astits: packet length %d of non-video PES is too big: %w", l, ErrPESPacketTooLong)
+	}
+	return nil
+}
+
+// WriteDataVerbose is similar to WriteData but also returns metadata of every packet it has written, tables
+// included, in order
+func (m *Muxer) WriteDataVerbose(d *MuxerData) (n int, pkts []PacketInfo, err error) {
+	m.packetInfos = &pkts
+	defer func() { m.packetInfos = nil }()
+	n, err = m.WriteData(d)
+	return
+}
+
+// addPacketInfo records metadata of a written packet when WriteDataVerbose is running
+func (m *Muxer) addPacketInfo(h PacketHeader) {
+	if m.packetInfos == nil {
+		return
+	}
+	*m.packetInfos = append(*m.packetInfos, PacketInfo{
+		ContinuityCounter:         h.ContinuityCounter,
+		HasPayload:                h.HasPayload,
+		PID:                       h.PID,
+		PayloadUnitStartIndicator: h.PayloadUnitStartIndicator,
+	})
+}
+
+// addPacketInfos records metadata of written packets serialized beforehand, such as tables, when WriteDataVerbose is
+// running
+func (m *Muxer) addPacketInfos(b []byte) {
+	if m.packetInfos == nil {
+		return
+	}
+	for ; len(b) >= m.packetSize; b = b[m.packetSize:] {
+		// Skip sync byte
+		if h, err := parsePacketHeader(astikit.NewBytesIterator(b[1:])); err == nil {
+			m.addPacketInfo(h)
+		}
+	}
+}
+
+// PESOptDataAlignmentIndicator returns the option to set the PES data alignment indicator, which signals that the PES
+// payload starts with an access unit such as a video start code or a subtitle segment
+func PESOptDataAlignmentIndicator(v bool) func(*PESOptionalHeader) {
+	return func(h *PESOptionalHeader) {
+		h.DataAlignmentIndicator = v
+	}
+}
+
+// PESOptPriority returns the option to set the PES priority
+func PESOptPriority(v bool) func(*PESOptionalHeader) {
+	return func(h *PESOptionalHeader) {
+		h.Priority = v
+	}
+}
+
+// WriteElementaryStreamData writes raw elementary stream data to TS stream, building the PES header on the fly
+// The PES stream id is derived from the elementary stream type. pts and dts are optional: dts is only written when
+// pts is provided and both differ
+// randomAccess should be set when data starts with a random access point, such as a keyframe
+// Use opts such as PESOptDataAlignmentIndicator or PESOptPriority to set additional PES optional header flags
+func (m *Muxer) WriteElementaryStreamData(pid uint16, data []byte, pts, dts *ClockReference, randomAccess bool, opts ...func(*PESOptionalHeader)) (int, error) {
+	if m.closed {
+		return 0, ErrMuxerClosed
+	}
+
+	ctx, ok := m.esContexts[uint32(pid)]
+	if !ok {
+		return 0, ErrPIDNotFound
+	}
+
+	oh := &PESOptionalHeader{MarkerBits: 2}
+	if pts != nil {
+		oh.PTS = pts
+		oh.PTSDTSIndicator = PTSDTSIndicatorOnlyPTS
+		if dts != nil && dts.Base != pts.Base {
+			oh.DTS = dts
+			oh.PTSDTSIndicator = PTSDTSIndicatorBothPresent
+		}
+	}
+	for _, opt := range opts {
+		opt(oh)
+	}
+
+	var af *PacketAdaptationField
+	if randomAccess {
+		af = &PacketAdaptationField{RandomAccessIndicator: true}
+	}
+
+	return m.WriteData(&MuxerData{
+		PID:             pid,
+		AdaptationField: af,
+		PES: &PESData{
+			Data: data,
+			Header: &PESHeader{
+				OptionalHeader: oh,
+				StreamID:       ctx.es.StreamType.ToPESStreamID(),
+			},
+		},
+	})
+}
+
+// WritePSISection packetizes a caller-supplied PSI section payload on the provided elementary stream PID
+// raw must contain the pointer field followed by the section bytes, CRC included: the muxer doesn't interpret it
+// ComputeCRC32 can be used to compute the trailing CRC
+// This is useful to insert private sections such as SCTE-35 splice_info sections
+func (m *Muxer) WritePSISection(pid uint16, raw []byte) (int, error) {
+	if m.closed {
+		return 0, ErrMuxerClosed
+	}
+
+	if _, ok := m.esContexts[uint32(pid)]; !ok {
+		return 0, ErrPIDNotFound
+	}
+	return m.writePSIPayload(pid, raw)
+}
+
+// WriteSDT writes an SDT describing the provided services of the transport stream
+// Its transport stream id is the one set with MuxerOptTransportStreamID, as for the PAT, and its version is incremented whenever its content changes
+func (m *Muxer) WriteSDT(originalNetworkID uint16, services []*SDTDataService) (int, error) {
+	if m.closed {
+		return 0, ErrMuxerClosed
+	}
+
+	d := &SDTData{
+		OriginalNetworkID: originalNetworkID,
+		Services:          services,
+		TransportStreamID: m.transportStreamID,
+	}
+
+	// Update version
+	m.buf.Reset()
+	if _, err := writeSDTSection(astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &m.buf}), d); err != nil {
+		return 0, err
+	}
+	if m.sdtData == nil || !bytes.Equal(m.sdtData, m.buf.Bytes()) {
+		m.sdtVersion.inc()
+		m.sdtData = append(m.sdtData[:0], m.buf.Bytes()...)
+	}
+
+	s := &PSISection{
+		Header: &PSISectionHeader{
+			PrivateBit:             true,
+			SectionSyntaxIndicator: true,
+			TableID:                PSITableIDSDTVariant1,
+		},
+		Syntax: &PSISectionSyntax{
+			Data: &PSISectionSyntaxData{SDT: d},
+			Header: &PSISectionSyntaxHeader{
+				CurrentNextIndicator: true,
+				TableIDExtension:     d.TransportStreamID,
+				VersionNumber:        uint8(m.sdtVersion.get()),
+			},
+		},
+	}
+	s.Header.SectionLength = calcPSISectionLength(s)
+
+	m.buf.Reset()
+	if _, err := writePSIData(astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &m.buf}), &PSIData{Sections: []*PSISection{s}}); err != nil {
+		return 0, err
+	}
+	return m.writePSIPayload(PIDSDT, m.buf.Bytes())
+}
+
+// WriteTDT writes a TDT containing the provided UTC time
+func (m *Muxer) WriteTDT(utc time.Time) (int, error) {
+	if m.closed {
+		return 0, ErrMuxerClosed
+	}
+
+	d := &TDTData{UTCTime: utc}
+	return m.writeDVBTimeTable(&PSISection{
+		Header: &PSISectionHeader{
+			PrivateBit:    true,
+			SectionLength: calcTDTSectionLength(d),
+			TableID:       PSITableIDTDT,
+		},
+		Syntax: &PSISectionSyntax{Data: &PSISectionSyntaxData{TDT: d}},
+	})
+}
+
+// WriteTOT writes a TOT containing the provided UTC time and descriptors, which usually are local time offset
+// descriptors
+func (m *Muxer) WriteTOT(utc time.Time, descriptors []*Descriptor) (int, error) {
+	if m.closed {
+		return 0, ErrMuxerClosed
+	}
+
+	d := &TOTData{
+		Descriptors: descriptors,
+		UTCTime:     utc,
+	}
+	return m.writeDVBTimeTable(&PSISection{
+		Header: &PSISectionHeader{
+			PrivateBit:    true,
+			SectionLength: calcTOTSectionLength(d),
+			TableID:       PSITableIDTOT,
+		},
+		Syntax: &PSISectionSyntax{Data: &PSISectionSyntaxData{TOT: d}},
+	})
+}
+
+// writeDVBTimeTable writes a TDT or a TOT section on the TDT PID
+func (m *Muxer) writeDVBTimeTable(s *PSISection) (int, error) {
+	m.buf.Reset()
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &m.buf})
+	if _, err := writePSIData(w, &PSIData{Sections: []*PSISection{s}}); err != nil {
+		return 0, err
+	}
+	return m.writePSIPayload(PIDTDT, m.buf.Bytes())
+}
+
+// writePSIPayload packetizes a PSI payload, pointer field included, on the provided PID
+func (m *Muxer) writePSIPayload(pid uint16, raw []byte) (int, error) {
+	bytesWritten := 0
+	payloadStart := true
+	maxPayloadSize := m.packetSize - 1 - mpegTsPacketHeaderSize // sync byte + header
+	for payloadStart || len(raw) > 0 {
+		l := len(raw)
+		if l > maxPayloadSize {
+			l = maxPayloadSize
+		}
+
+		// last packet is stuffed with 0xff by writePacket, which is what PSI expects after a section
+		pkt := Packet{
+			Header: PacketHeader{
+				ContinuityCounter:         m.continuityCounter(pid, true),
+				HasPayload:                true,
+				PayloadUnitStartIndicator: payloadStart,
+				PID:                       pid,
+			},
+			Payload: raw[:l],
+		}
+
+		n, err := writePacket(m.bitsWriter, &pkt, m.packetSize)
+		if err != nil {
+			return bytesWritten, err
+		}
+		bytesWritten += n
+
+		raw = raw[l:]
+		payloadStart = false
+	}
+
+	return bytesWritten, nil
+}
+
+// continuityCounter returns the continuity counter of the next packet written on the provided PID
+// Counters are tracked per PID in a single place so that tables and elementary streams never reset each other's
+// It must be called right before writing the packet. As per the spec, the counter is only incremented when the packet
+// carries a payload
+func (m *Muxer) continuityCounter(pid uint16, hasPayload bool) uint8 {
+	cc, ok := m.ccs[uint32(pid)]
+	if !ok {
+		c := newWrappingCounter(0b1111) // CC is 4 bits
+		cc = &c
+		m.ccs[uint32(pid)] = cc
+	}
+	if !hasPayload {
+		return uint8(cc.get() & 0b1111)
+	}
+	return uint8(cc.inc())
+}
+
+// writeAdaptationFieldOnly writes a single packet carrying the provided adaptation field, stuffed to fill the packet,
+// and no payload
+func (m *Muxer) writeAdaptationFieldOnly(pid uint16, af *PacketAdaptationField) (int, error) {
+	af.StuffingLength = 0
+	// sync byte + header + one byte for adaptation field length field
+	af.StuffingLength = m.packetSize - 1 - mpegTsPacketHeaderSize - 1 - int(calcPacketAdaptationFieldLength(af))
+
+	p := &Packet{
+		AdaptationField: af,
+		Header: PacketHeader{
+			ContinuityCounter:  m.continuityCounter(pid, false),
+			HasAdaptationField: true,
+			PID:                pid,
+		},
+	}
+	n, err := writePacket(m.bitsWriter, p, m.packetSize)
+	af.StuffingLength = 0
+	if err == nil {
+		m.addPacketInfo(p.Header)
+	}
+	return n, err
+}
+
+// Close finalizes the stream: it writes a final set of tables if MuxerOptTablesOnClose is enabled and at least one
+// elementary stream has been added
+// Closing an empty muxer or closing a muxer twice is a no-op, and every write following Close fails with ErrMuxerClosed
+// Close doesn't close nor flush the underlying writer
+func (m *Muxer) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+
+	if !m.tablesOnClose || len(m.esContexts) == 0 {
+		return nil
+	}
+
+	_, err := m.writeTables()
+	return err
+}
+
 // Writes given packet to MPEG-TS stream
 // Stuffs with 0xffs if packet turns out to be shorter than target packet length
 func (m *Muxer) WritePacket(p *Packet) (int, error) {
+	if m.closed {
+		return 0, ErrMuxerClosed
+	}
 	return writePacket(m.bitsWriter, p, m.packetSize)
 }
 
@@ -285,7 +711,7 @@ func (m *Muxer) retransmitTables(force bool) (int, error) {
 		return 0, nil
 	}
 
-	n, err := m.WriteTables()
+	n, err := m.writeTables()
 	if err != nil {
 		return n, err
 	}
@@ -295,6 +721,13 @@ func (m *Muxer) retransmitTables(force bool) (int, error) {
 }
 
 func (m *Muxer) WriteTables() (int, error) {
+	if m.closed {
+		return 0, ErrMuxerClosed
+	}
+	return m.writeTables()
+}
+
+func (m *Muxer) writeTables() (int, error) {
 	bytesWritten := 0
 
 	if err := m.generatePAT(); err != nil {
@@ -310,18 +743,21 @@ func (m *Muxer) WriteTables() (int, error) {
 		return bytesWritten, err
 	}
 	bytesWritten += n
+	m.addPacketInfos(m.patBytes.Bytes())
 
 	n, err = m.w.Write(m.pmtBytes.Bytes())
 	if err != nil {
 		return bytesWritten, err
 	}
 	bytesWritten += n
+	m.addPacketInfos(m.pmtBytes.Bytes())
 
 	return bytesWritten, nil
 }
 
 func (m *Muxer) generatePAT() error {
 	d := m.pm.toPATDataUnlocked()
+	d.TransportStreamID = m.transportStreamID
 
 	versionNumber := m.patVersion.get()
 	if m.pmUpdated {
@@ -343,7 +779,7 @@ func (m *Muxer) generatePAT() error {
 		Header: &PSISectionHeader{
 			SectionLength:          calcPATSectionLength(d),
 			SectionSyntaxIndicator: true,
-			TableID:                PSITableID(d.TransportStreamID),
+			TableID:                PSITableIDPAT,
 		},
 		Syntax: syntax,
 	}
@@ -365,7 +801,7 @@ func (m *Muxer) generatePAT() error {
 			HasPayload:                true,
 			PayloadUnitStartIndicator: true,
 			PID:                       PIDPAT,
-			ContinuityCounter:         uint8(m.patCC.inc()),
+			ContinuityCounter:         m.continuityCounter(PIDPAT, true),
 		},
 		Payload: m.buf.Bytes(),
 	}
@@ -433,7 +869,7 @@ func (m *Muxer) generatePMT() error {
 			HasPayload:                true,
 			PayloadUnitStartIndicator: true,
 			PID:                       pmtStartPID, // FIXME multiple programs support
-			ContinuityCounter:         uint8(m.pmtCC.inc()),
+			ContinuityCounter:         m.continuityCounter(pmtStartPID, true),
 		},
 		Payload: m.buf.Bytes(),
 	}
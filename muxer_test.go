@@ -3,7 +3,9 @@ package astits
 import (
 	"bytes"
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/asticode/go-astikit"
 	"github.com/stretchr/testify/assert"
@@ -221,6 +223,40 @@ func TestMuxer_WriteTables_Error(t *testing.T) {
 	assert.Equal(t, ErrPCRPIDInvalid, err)
 }
 
+func TestMuxer_Close(t *testing.T) {
+	// Empty muxer
+	buf := bytes.Buffer{}
+	muxer := NewMuxer(context.Background(), &buf, MuxerOptTablesOnClose(true))
+	assert.NoError(t, muxer.Close())
+	assert.Equal(t, 0, buf.Len())
+
+	// Final tables
+	muxer = NewMuxer(context.Background(), &buf, MuxerOptTablesOnClose(true))
+	err := muxer.AddElementaryStream(PMTElementaryStream{
+		ElementaryPID: 0x1234,
+		StreamType:    StreamTypeH264Video,
+	})
+	assert.NoError(t, err)
+	muxer.SetPCRPID(0x1234)
+	assert.NoError(t, muxer.Close())
+	assert.Equal(t, append(patExpectedBytes(0, 0), pmtExpectedBytesVideoOnly(0, 0)...), buf.Bytes())
+
+	// Closing twice is a no-op
+	assert.NoError(t, muxer.Close())
+	assert.Equal(t, 2*MpegTsPacketSize, buf.Len())
+
+	// Writing after close fails
+	_, err = muxer.WriteData(&MuxerData{PID: 0x1234, PES: &PESData{Data: []byte{1}}})
+	assert.Equal(t, ErrMuxerClosed, err)
+	_, err = muxer.WriteTables()
+	assert.Equal(t, ErrMuxerClosed, err)
+	_, err = muxer.WritePacket(&Packet{Header: PacketHeader{PID: 0x1234}})
+	assert.Equal(t, ErrMuxerClosed, err)
+	_, err = muxer.WriteTDT(time.Now())
+	assert.Equal(t, ErrMuxerClosed, err)
+	assert.Equal(t, 2*MpegTsPacketSize, buf.Len())
+}
+
 func TestMuxer_AddElementaryStream(t *testing.T) {
 	muxer := NewMuxer(context.Background(), nil)
 	err := muxer.AddElementaryStream(PMTElementaryStream{
@@ -236,6 +272,44 @@ func TestMuxer_AddElementaryStream(t *testing.T) {
 	assert.Equal(t, ErrPIDAlreadyExists, err)
 }
 
+func TestMuxer_AddElementaryStreamWithRegistration(t *testing.T) {
+	buf := &bytes.Buffer{}
+	muxer := NewMuxer(context.Background(), buf)
+	ds := []*Descriptor{{Length: 2, Stuffing: &DescriptorStuffing{Data: []byte{0xff, 0xff}}, Tag: DescriptorTagStuffing}}
+	err := muxer.AddElementaryStreamWithRegistration(PMTElementaryStream{
+		ElementaryPID:               0x1234,
+		ElementaryStreamDescriptors: ds,
+		StreamType:                  StreamTypePrivateData,
+	}, [4]byte{'O', 'p', 'u', 's'})
+	assert.NoError(t, err)
+	assert.Len(t, ds, 1)
+	muxer.SetPCRPID(0x1234)
+
+	_, err = muxer.WriteTables()
+	assert.NoError(t, err)
+
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()))
+	var pmt *PMTData
+	for pmt == nil {
+		d, err := dmx.NextData()
+		assert.NoError(t, err)
+		if err != nil {
+			return
+		}
+		pmt = d.PMT
+	}
+	assert.Len(t, pmt.ElementaryStreams, 1)
+	es := pmt.ElementaryStreams[0]
+	assert.Len(t, es.ElementaryStreamDescriptors, 2)
+	assert.Equal(t, ds[0], es.ElementaryStreamDescriptors[0])
+	assert.Equal(t, &Descriptor{
+		Length:       4,
+		Registration: &DescriptorRegistration{FormatIdentifier: 0x4f707573},
+		Tag:          DescriptorTagRegistration,
+	}, es.ElementaryStreamDescriptors[1])
+	assert.Equal(t, "Opus Audio", es.Codec())
+}
+
 func TestMuxer_RemoveElementaryStream(t *testing.T) {
 	muxer := NewMuxer(context.Background(), nil)
 	err := muxer.AddElementaryStream(PMTElementaryStream{
@@ -334,3 +408,534 @@ func TestMuxer_WritePayload(t *testing.T) {
 	assert.Equal(t, patExpectedBytes(0, 0), bs[:MpegTsPacketSize])
 	assert.Equal(t, pmtExpectedBytesVideoAndAudio(0, 0), bs[MpegTsPacketSize:MpegTsPacketSize*2])
 }
+
+func TestMuxer_WriteElementaryStreamData(t *testing.T) {
+	newMuxer := func(buf *bytes.Buffer) *Muxer {
+		muxer := NewMuxer(context.Background(), buf)
+		err := muxer.AddElementaryStream(PMTElementaryStream{
+			ElementaryPID: 0x1234,
+			StreamType:    StreamTypeH264Video,
+		})
+		assert.NoError(t, err)
+		muxer.SetPCRPID(0x1234)
+		return muxer
+	}
+
+	payload := testPayload()
+	pts := ClockReference{Base: 5726623063}
+	dts := ClockReference{Base: 5726623060}
+
+	bufExpected := bytes.Buffer{}
+	muxer := newMuxer(&bufExpected)
+	_, err := muxer.WriteData(&MuxerData{
+		PID:             0x1234,
+		AdaptationField: &PacketAdaptationField{RandomAccessIndicator: true},
+		PES: &PESData{
+			Data: payload,
+			Header: &PESHeader{
+				OptionalHeader: &PESOptionalHeader{
+					DTS:             &dts,
+					MarkerBits:      2,
+					PTS:             &pts,
+					PTSDTSIndicator: PTSDTSIndicatorBothPresent,
+				},
+				StreamID: 0xe0,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	_, err = muxer.WriteData(&MuxerData{
+		PID: 0x1234,
+		PES: &PESData{
+			Data: payload,
+			Header: &PESHeader{
+				OptionalHeader: &PESOptionalHeader{
+					MarkerBits:      2,
+					PTS:             &pts,
+					PTSDTSIndicator: PTSDTSIndicatorOnlyPTS,
+				},
+				StreamID: 0xe0,
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	buf := bytes.Buffer{}
+	muxer = newMuxer(&buf)
+	_, err = muxer.WriteElementaryStreamData(0x1235, payload, &pts, &dts, true)
+	assert.Equal(t, ErrPIDNotFound, err)
+	n, err := muxer.WriteElementaryStreamData(0x1234, payload, &pts, &dts, true)
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Len(), n)
+	_, err = muxer.WriteElementaryStreamData(0x1234, payload, &pts, &pts, false)
+	assert.NoError(t, err)
+	assert.Equal(t, bufExpected.Bytes(), buf.Bytes())
+}
+
+func TestMuxer_WriteElementaryStreamDataOptions(t *testing.T) {
+	buf := bytes.Buffer{}
+	muxer := NewMuxer(context.Background(), &buf)
+	err := muxer.AddElementaryStream(PMTElementaryStream{
+		ElementaryPID: 0x1234,
+		StreamType:    StreamTypeH264Video,
+	})
+	assert.NoError(t, err)
+	muxer.SetPCRPID(0x1234)
+
+	pts := ClockReference{Base: 5726623060}
+	_, err = muxer.WriteElementaryStreamData(0x1234, []byte{1, 2, 3}, &pts, nil, false, PESOptDataAlignmentIndicator(true), PESOptPriority(true))
+	assert.NoError(t, err)
+
+	// Last packet contains the PES data, after tables
+	p, err := parsePacket(astikit.NewBytesIterator(buf.Bytes()[buf.Len()-MpegTsPacketSize:]), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0b10001100), p.Payload[6]) // marker bits, priority and data alignment indicator
+	d, err := parsePESData(astikit.NewBytesIterator(p.Payload), false, nil)
+	assert.NoError(t, err)
+	assert.True(t, d.Header.OptionalHeader.DataAlignmentIndicator)
+	assert.True(t, d.Header.OptionalHeader.Priority)
+	assert.Equal(t, []byte{1, 2, 3}, d.Data)
+}
+
+func TestMuxer_WriteDataPESPacketLength(t *testing.T) {
+	newMuxer := func(opts ...func(*Muxer)) (*Muxer, *bytes.Buffer) {
+		buf := &bytes.Buffer{}
+		muxer := NewMuxer(context.Background(), buf, opts...)
+		err := muxer.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video})
+		assert.NoError(t, err)
+		err = muxer.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x101, StreamType: StreamTypeAACAudio})
+		assert.NoError(t, err)
+		muxer.SetPCRPID(0x100)
+		return muxer, buf
+	}
+
+	pts := ClockReference{Base: 5726623060}
+	lastPESHeader := func(buf *bytes.Buffer) *PESHeader {
+		for o := buf.Len() - MpegTsPacketSize; o >= 0; o -= MpegTsPacketSize {
+			p, err := parsePacket(astikit.NewBytesIterator(buf.Bytes()[o:o+MpegTsPacketSize]), nil)
+			assert.NoError(t, err)
+			if !p.Header.PayloadUnitStartIndicator || (p.Header.PID != 0x100 && p.Header.PID != 0x101) {
+				continue
+			}
+			d, err := parsePESData(astikit.NewBytesIterator(p.Payload), false, nil)
+			assert.NoError(t, err)
+			return d.Header
+		}
+		return nil
+	}
+
+	// Audio packet length covers the optional header and the data
+	muxer, buf := newMuxer()
+	_, err := muxer.WriteElementaryStreamData(0x101, bytes.Repeat([]byte{1}, 100), &pts, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(108), lastPESHeader(buf).PacketLength)
+
+	// Video packet length is zero by default
+	_, err = muxer.WriteElementaryStreamData(0x100, bytes.Repeat([]byte{1}, 100), &pts, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), lastPESHeader(buf).PacketLength)
+
+	// Audio packet length can't be zero and nothing is written
+	l := buf.Len()
+	_, err = muxer.WriteElementaryStreamData(0x101, make([]byte, 0x10000), &pts, nil, true)
+	assert.True(t, errors.Is(err, ErrPESPacketTooLong))
+	assert.Equal(t, l, buf.Len())
+
+	// Video packet length is written when enabled and it fits
+	muxer, buf = newMuxer(MuxerOptVideoPESPacketLength(true))
+	_, err = muxer.WriteData(&MuxerData{
+		PES: &PESData{
+			Data: bytes.Repeat([]byte{1}, 100),
+			Header: &PESHeader{
+				OptionalHeader: &PESOptionalHeader{MarkerBits: 2},
+				StreamID:       0xe0,
+			},
+		},
+		PID: 0x100,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(103), lastPESHeader(buf).PacketLength)
+	_, err = muxer.WriteElementaryStreamData(0x100, make([]byte, 0x10000), &pts, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), lastPESHeader(buf).PacketLength)
+}
+
+func TestMuxer_WriteDataValidation(t *testing.T) {
+	buf := bytes.Buffer{}
+	muxer := NewMuxer(context.Background(), &buf)
+	err := muxer.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video})
+	assert.NoError(t, err)
+
+	for _, c := range []struct {
+		d   *MuxerData
+		err error
+	}{
+		{d: &MuxerData{PES: &PESData{Data: []byte{1}, Header: &PESHeader{StreamID: 0xe0}}, PID: 0x101}, err: ErrPIDNotFound},
+		{d: &MuxerData{PID: 0x100}, err: ErrMuxerDataEmpty},
+		{d: &MuxerData{PES: &PESData{Data: []byte{1}}, PID: 0x100}, err: ErrMissingPESHeader},
+	} {
+		_, err = muxer.WriteData(c.d)
+		assert.Equal(t, c.err, err)
+	}
+	assert.Equal(t, 0, buf.Len())
+}
+
+func TestMuxer_WriteDataAdaptationFieldOnly(t *testing.T) {
+	buf := bytes.Buffer{}
+	muxer := NewMuxer(context.Background(), &buf)
+	err := muxer.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video})
+	assert.NoError(t, err)
+	muxer.SetPCRPID(0x100)
+
+	_, err = muxer.WriteData(&MuxerData{PID: 0x100})
+	assert.Equal(t, ErrMuxerDataEmpty, err)
+
+	_, err = muxer.WriteElementaryStreamData(0x100, []byte{1, 2, 3}, nil, nil, false)
+	assert.NoError(t, err)
+	buf.Reset()
+
+	pcr := &ClockReference{Base: 5726623060, Extension: 12}
+	n, err := muxer.WriteData(&MuxerData{
+		AdaptationField: &PacketAdaptationField{HasPCR: true, PCR: pcr},
+		PID:             0x100,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, MpegTsPacketSize, n)
+	assert.Equal(t, MpegTsPacketSize, buf.Len())
+
+	p, err := parsePacket(astikit.NewBytesIterator(buf.Bytes()), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x100), p.Header.PID)
+	assert.True(t, p.Header.HasAdaptationField)
+	assert.False(t, p.Header.HasPayload)
+	assert.Equal(t, uint8(0), p.Header.ContinuityCounter) // Same as the previous packet since there's no payload
+	assert.Equal(t, 183, p.AdaptationField.Length)
+	assert.True(t, p.AdaptationField.HasPCR)
+	assert.Equal(t, pcr, p.AdaptationField.PCR)
+	assert.Empty(t, p.Payload)
+}
+
+func TestMuxer_WriteDataAdaptationFieldSplicing(t *testing.T) {
+	buf := bytes.Buffer{}
+	muxer := NewMuxer(context.Background(), &buf)
+	err := muxer.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video})
+	assert.NoError(t, err)
+	muxer.SetPCRPID(0x100)
+
+	afe := &PacketAdaptationExtensionField{
+		HasLegalTimeWindow:     true,
+		LegalTimeWindowIsValid: true,
+		LegalTimeWindowOffset:  0x1234,
+	}
+	_, err = muxer.WriteData(&MuxerData{
+		AdaptationField: &PacketAdaptationField{
+			AdaptationExtensionField:    afe,
+			HasAdaptationExtensionField: true,
+			HasSplicingCountdown:        true,
+			HasTransportPrivateData:     true,
+			SpliceCountdown:             -2,
+			TransportPrivateData:        []byte{1, 2, 3},
+		},
+		PES: &PESData{
+			Data:   []byte{4, 5, 6},
+			Header: &PESHeader{OptionalHeader: &PESOptionalHeader{MarkerBits: 2}, StreamID: 0xe0},
+		},
+		PID: 0x100,
+	})
+	assert.NoError(t, err)
+
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()))
+	for {
+		d, err := dmx.NextData()
+		assert.NoError(t, err)
+		if d.PES == nil {
+			continue
+		}
+		assert.Equal(t, []byte{4, 5, 6}, d.PES.Data)
+		af := d.FirstPacket.AdaptationField
+		assert.True(t, af.HasSplicingCountdown)
+		assert.Equal(t, -2, af.SpliceCountdown)
+		assert.True(t, af.HasTransportPrivateData)
+		assert.Equal(t, []byte{1, 2, 3}, af.TransportPrivateData)
+		assert.True(t, af.HasAdaptationExtensionField)
+		afe.Length = 3
+		assert.Equal(t, afe, af.AdaptationExtensionField)
+		break
+	}
+}
+
+func TestMuxer_WriteDataVerbose(t *testing.T) {
+	buf := bytes.Buffer{}
+	muxer := NewMuxer(context.Background(), &buf)
+	err := muxer.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video})
+	assert.NoError(t, err)
+	muxer.SetPCRPID(0x100)
+
+	// First data is preceded by tables and spans several packets
+	n, pkts, err := muxer.WriteDataVerbose(&MuxerData{
+		PES: &PESData{
+			Data:   bytes.Repeat([]byte{1}, 400),
+			Header: &PESHeader{OptionalHeader: &PESOptionalHeader{MarkerBits: 2}},
+		},
+		PID: 0x100,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Len(), n)
+	assert.Len(t, pkts, n/MpegTsPacketSize)
+	assert.Equal(t, []PacketInfo{
+		{ContinuityCounter: 0, HasPayload: true, PID: PIDPAT, PayloadUnitStartIndicator: true},
+		{ContinuityCounter: 0, HasPayload: true, PID: pmtStartPID, PayloadUnitStartIndicator: true},
+		{ContinuityCounter: 0, HasPayload: true, PID: 0x100, PayloadUnitStartIndicator: true},
+		{ContinuityCounter: 1, HasPayload: true, PID: 0x100},
+		{ContinuityCounter: 2, HasPayload: true, PID: 0x100},
+	}, pkts)
+
+	// Adaptation field only
+	n, pkts, err = muxer.WriteDataVerbose(&MuxerData{
+		AdaptationField: &PacketAdaptationField{HasPCR: true, PCR: &ClockReference{}},
+		PID:             0x100,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, MpegTsPacketSize, n)
+	assert.Equal(t, []PacketInfo{{ContinuityCounter: 2, PID: 0x100}}, pkts)
+
+	// Packet infos are not recorded by WriteData
+	_, err = muxer.WriteData(&MuxerData{
+		AdaptationField: &PacketAdaptationField{HasPCR: true, PCR: &ClockReference{}},
+		PID:             0x100,
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, muxer.packetInfos)
+}
+
+func TestMuxer_ContinuityCounterStart(t *testing.T) {
+	buf := bytes.Buffer{}
+	muxer := NewMuxer(context.Background(), &buf, MuxerOptContinuityCounterStart(map[uint16]uint8{PIDPAT: 0, 0x100: 14}))
+	err := muxer.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video})
+	assert.NoError(t, err)
+	muxer.SetPCRPID(0x100)
+	muxer.SetContinuityCounter(pmtStartPID, 7)
+
+	_, pkts, err := muxer.WriteDataVerbose(&MuxerData{
+		PES: &PESData{
+			Data:   bytes.Repeat([]byte{1}, 400),
+			Header: &PESHeader{OptionalHeader: &PESOptionalHeader{MarkerBits: 2}},
+		},
+		PID: 0x100,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []PacketInfo{
+		{ContinuityCounter: 0, HasPayload: true, PID: PIDPAT, PayloadUnitStartIndicator: true},
+		{ContinuityCounter: 7, HasPayload: true, PID: pmtStartPID, PayloadUnitStartIndicator: true},
+		{ContinuityCounter: 14, HasPayload: true, PID: 0x100, PayloadUnitStartIndicator: true},
+		{ContinuityCounter: 15, HasPayload: true, PID: 0x100},
+		{ContinuityCounter: 0, HasPayload: true, PID: 0x100},
+	}, pkts)
+}
+
+func TestMuxer_WriteTDTAndTOT(t *testing.T) {
+	buf := bytes.Buffer{}
+	muxer := NewMuxer(context.Background(), &buf)
+
+	utc := time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC)
+	n, err := muxer.WriteTDT(utc)
+	assert.NoError(t, err)
+	assert.Equal(t, MpegTsPacketSize, n)
+
+	ds := []*Descriptor{{
+		Length: 13,
+		LocalTimeOffset: &DescriptorLocalTimeOffset{Items: []*DescriptorLocalTimeOffsetItem{{
+			CountryCode:             []byte("fra"),
+			CountryRegionID:         0,
+			LocalTimeOffset:         time.Hour,
+			LocalTimeOffsetPolarity: false,
+			NextTimeOffset:          2 * time.Hour,
+			TimeOfChange:            time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC),
+		}}},
+		Tag: DescriptorTagLocalTimeOffset,
+	}}
+	n, err = muxer.WriteTOT(utc, ds)
+	assert.NoError(t, err)
+	assert.Equal(t, MpegTsPacketSize, n)
+
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()))
+	d, err := dmx.NextData()
+	assert.NoError(t, err)
+	assert.Equal(t, PIDTDT, d.PID)
+	assert.Equal(t, &TDTData{UTCTime: utc}, d.TDT)
+
+	d, err = dmx.NextData()
+	assert.NoError(t, err)
+	assert.Equal(t, PIDTDT, d.PID)
+	assert.Equal(t, uint8(1), d.FirstPacket.Header.ContinuityCounter)
+	assert.Equal(t, &TOTData{Descriptors: ds, UTCTime: utc}, d.TOT)
+}
+
+func TestMuxer_WriteSDT(t *testing.T) {
+	buf := bytes.Buffer{}
+	muxer := NewMuxer(context.Background(), &buf, MuxerOptTransportStreamID(0x1234))
+
+	services := []*SDTDataService{
+		{
+			Descriptors: []*Descriptor{{
+				Length: 11,
+				Service: &DescriptorService{
+					Name:     []byte("name1"),
+					Provider: []byte("pro"),
+					Type:     ServiceTypeDigitalTelevisionService,
+				},
+				Tag: DescriptorTagService,
+			}},
+			HasEITPresentFollowing: true,
+			RunningStatus:          RunningStatusRunning,
+			ServiceID:              1,
+		},
+		{
+			Descriptors: []*Descriptor{{
+				Length: 11,
+				Service: &DescriptorService{
+					Name:     []byte("name2"),
+					Provider: []byte("pro"),
+					Type:     ServiceTypeDigitalTelevisionService,
+				},
+				Tag: DescriptorTagService,
+			}},
+			RunningStatus: RunningStatusNotRunning,
+			ServiceID:     2,
+		},
+	}
+	for _, ss := range [][]*SDTDataService{services, services, services[:1]} {
+		_, err := muxer.WriteSDT(0x20, ss)
+		assert.NoError(t, err)
+	}
+	err := muxer.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video})
+	assert.NoError(t, err)
+	muxer.SetPCRPID(0x100)
+	_, err = muxer.WriteTables()
+	assert.NoError(t, err)
+
+	// Version is only incremented when the content changes
+	var versions []uint8
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(buf.Bytes()), DemuxerOptOnTableUpdate(func(pid uint16, tableID PSITableID, oldVer, newVer uint8) {
+		versions = append(versions, oldVer, newVer)
+	}))
+	var pat *PATData
+	var sdts []*SDTData
+	for {
+		d, err := dmx.NextData()
+		if errors.Is(err, ErrNoMorePackets) {
+			break
+		}
+		assert.NoError(t, err)
+		if d.PAT != nil {
+			pat = d.PAT
+		} else if d.SDT != nil {
+			sdts = append(sdts, d.SDT)
+		}
+	}
+	assert.Equal(t, []*SDTData{
+		{OriginalNetworkID: 0x20, Services: services, TransportStreamID: 0x1234},
+		{OriginalNetworkID: 0x20, Services: services, TransportStreamID: 0x1234},
+		{OriginalNetworkID: 0x20, Services: services[:1], TransportStreamID: 0x1234},
+	}, sdts)
+	assert.Equal(t, []uint8{0, 1}, versions)
+
+	// PAT shares the transport stream id
+	assert.NotNil(t, pat)
+	assert.Equal(t, uint16(0x1234), pat.TransportStreamID)
+}
+
+func TestMuxer_WritePSISection(t *testing.T) {
+	buf := bytes.Buffer{}
+	muxer := NewMuxer(context.Background(), &buf)
+
+	_, err := muxer.WritePSISection(0x1235, []byte{0})
+	assert.Equal(t, ErrPIDNotFound, err)
+
+	err = muxer.AddElementaryStream(PMTElementaryStream{
+		ElementaryPID: 0x1235,
+		StreamType:    StreamTypeSCTE35,
+	})
+	assert.NoError(t, err)
+
+	// Section fitting in one packet
+	section := append([]byte{0}, bytes.Repeat([]byte{0xfc}, 40)...)
+	n, err := muxer.WritePSISection(0x1235, section)
+	assert.NoError(t, err)
+	assert.Equal(t, MpegTsPacketSize, n)
+	assert.Equal(t, MpegTsPacketSize, buf.Len())
+
+	p, err := parsePacket(astikit.NewBytesIterator(buf.Bytes()), nil)
+	assert.NoError(t, err)
+	assert.True(t, p.Header.PayloadUnitStartIndicator)
+	assert.Equal(t, uint16(0x1235), p.Header.PID)
+	assert.Equal(t, uint8(0), p.Header.ContinuityCounter)
+	assert.Equal(t, section, p.Payload[:len(section)])
+	assert.Equal(t, bytes.Repeat([]byte{0xff}, len(p.Payload)-len(section)), p.Payload[len(section):])
+
+	// Section spanning over two packets
+	buf.Reset()
+	section = append([]byte{0}, bytes.Repeat([]byte{0xfc}, 200)...)
+	n, err = muxer.WritePSISection(0x1235, section)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*MpegTsPacketSize, n)
+
+	p, err = parsePacket(astikit.NewBytesIterator(buf.Bytes()[:MpegTsPacketSize]), nil)
+	assert.NoError(t, err)
+	assert.True(t, p.Header.PayloadUnitStartIndicator)
+	assert.Equal(t, uint8(1), p.Header.ContinuityCounter)
+	assert.Equal(t, section[:184], p.Payload)
+
+	p, err = parsePacket(astikit.NewBytesIterator(buf.Bytes()[MpegTsPacketSize:]), nil)
+	assert.NoError(t, err)
+	assert.False(t, p.Header.PayloadUnitStartIndicator)
+	assert.Equal(t, uint8(2), p.Header.ContinuityCounter)
+	assert.Equal(t, section[184:], p.Payload[:len(section)-184])
+}
+
+func TestMuxer_ContinuityCounters(t *testing.T) {
+	buf := bytes.Buffer{}
+	muxer := NewMuxer(context.Background(), &buf, MuxerOptTablesRetransmitPeriod(3))
+	err := muxer.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x100, StreamType: StreamTypeH264Video})
+	assert.NoError(t, err)
+	err = muxer.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x101, StreamType: StreamTypeAACAudio})
+	assert.NoError(t, err)
+	muxer.SetPCRPID(0x100)
+
+	// Interleave explicit table writes, retransmitted tables and elementary stream writes
+	for i := 0; i < 40; i++ {
+		if i%7 == 0 {
+			_, err = muxer.WriteTables()
+			assert.NoError(t, err)
+		}
+		_, err = muxer.WriteElementaryStreamData(0x100, bytes.Repeat([]byte{0x1}, 300), nil, nil, i%5 == 0)
+		assert.NoError(t, err)
+		_, err = muxer.WriteElementaryStreamData(0x101, bytes.Repeat([]byte{0x2}, 100), nil, nil, false)
+		assert.NoError(t, err)
+		if i == 20 {
+			// Re-adding a stream on the same PID shouldn't reset its continuity counter
+			assert.NoError(t, muxer.RemoveElementaryStream(0x101))
+			assert.NoError(t, muxer.AddElementaryStream(PMTElementaryStream{ElementaryPID: 0x101, StreamType: StreamTypeAACAudio}))
+		}
+	}
+
+	ccs := map[uint16]uint8{}
+	counts := map[uint16]int{}
+	for b := buf.Bytes(); len(b) > 0; b = b[MpegTsPacketSize:] {
+		p, err := parsePacket(astikit.NewBytesIterator(b[:MpegTsPacketSize]), nil)
+		assert.NoError(t, err)
+		if !p.Header.HasPayload {
+			continue
+		}
+		if last, ok := ccs[p.Header.PID]; ok {
+			assert.Equal(t, (last+1)&0xf, p.Header.ContinuityCounter, "pid %d packet %d", p.Header.PID, counts[p.Header.PID])
+		} else {
+			assert.Equal(t, uint8(0), p.Header.ContinuityCounter)
+		}
+		ccs[p.Header.PID] = p.Header.ContinuityCounter
+		counts[p.Header.PID]++
+	}
+	for _, pid := range []uint16{PIDPAT, pmtStartPID, 0x100, 0x101} {
+		assert.Greater(t, counts[pid], 16, "pid %d", pid)
+	}
+}
@@ -0,0 +1,51 @@
+package astits
+
+// splitNALUnits splits an Annex B byte stream into NAL units, start codes excluded
+func splitNALUnits(b []byte) (nals [][]byte) {
+	start := -1
+	for idx := 0; idx+2 < len(b); idx++ {
+		// Look for 0x000001 start code
+		if b[idx] != 0 || b[idx+1] != 0 || b[idx+2] != 1 {
+			continue
+		}
+
+		// Append previous NAL unit, trailing zero bytes excluded since they belong to 4-byte start codes
+		if start >= 0 {
+			end := idx
+			for end > start && b[end-1] == 0 {
+				end--
+			}
+			nals = append(nals, b[start:end])
+		}
+
+		// Move past start code
+		idx += 2
+		start = idx + 1
+	}
+
+	// Append last NAL unit
+	if start >= 0 && start < len(b) {
+		nals = append(nals, b[start:])
+	}
+	return
+}
+
+// removeEmulationPreventionBytes converts a NAL unit payload to its RBSP by removing the 0x03 bytes following
+// 0x0000 sequences
+func removeEmulationPreventionBytes(b []byte) []byte {
+	o := make([]byte, 0, len(b))
+	var zeros int
+	for _, c := range b {
+		if zeros >= 2 && c == 0x03 {
+			zeros = 0
+			continue
+		}
+		if c == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+		o = append(o, c)
+	}
+	return o
+}
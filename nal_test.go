@@ -0,0 +1,20 @@
+package astits
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitNALUnits(t *testing.T) {
+	assert.Equal(t, [][]byte{{0x09, 0xf0}, {0x67, 0x42}, {0x65, 0x88}}, splitNALUnits([]byte{
+		0, 0, 0, 1, 0x09, 0xf0, // 4-byte start code
+		0, 0, 1, 0x67, 0x42, // 3-byte start code
+		0, 0, 0, 1, 0x65, 0x88,
+	}))
+	assert.Empty(t, splitNALUnits([]byte{0x65, 0x88}))
+}
+
+func TestRemoveEmulationPreventionBytes(t *testing.T) {
+	assert.Equal(t, []byte{0, 0, 1, 0, 0, 3, 0, 0}, removeEmulationPreventionBytes([]byte{0, 0, 3, 1, 0, 0, 3, 3, 0, 0}))
+}
@@ -1,8 +1,11 @@
 package astits
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
+
 	"github.com/asticode/go-astikit"
 )
 
@@ -76,6 +79,34 @@ type PacketAdaptationExtensionField struct {
 	SpliceType             uint8  // Indicates the parameters of the H.262 splice.
 }
 
+// FindSyncByte returns the offset of the first sync byte in b that is followed by another sync byte one packet later,
+// or by the end of b. It returns -1 if no such sync byte exists
+// Use it to align a buffer whose first packet doesn't start at offset 0
+func FindSyncByte(b []byte) int {
+	for idx := range b {
+		if b[idx] == syncByte && (idx+MpegTsPacketSize >= len(b) || b[idx+MpegTsPacketSize] == syncByte) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// ParsePacketAt parses the 188 bytes packet starting at the provided offset of b
+func ParsePacketAt(b []byte, off int) (p *Packet, err error) {
+	// Check offset
+	if off < 0 || off+MpegTsPacketSize > len(b) {
+		err = fmt.Errorf("astits: offset %d is out of range for a %d bytes packet in %d bytes: %w", off, MpegTsPacketSize, len(b), ErrTruncatedPacket)
+		return
+	}
+
+	// Parse packet
+	if p, err = parsePacket(astikit.NewBytesIterator(b[off:off+MpegTsPacketSize]), nil); err != nil {
+		err = fmt.Errorf("astits: parsing packet failed: %w", err)
+		return
+	}
+	return
+}
+
 // parsePacket parses a packet
 func parsePacket(i *astikit.BytesIterator, s PacketSkipper) (p *Packet, err error) {
 	// Get next byte
@@ -91,6 +122,12 @@ func parsePacket(i *astikit.BytesIterator, s PacketSkipper) (p *Packet, err erro
 		return
 	}
 
+	// Packet must be complete
+	if i.Len() < MpegTsPacketSize {
+		err = fmt.Errorf("astits: packet is %d bytes long: %w", i.Len(), ErrTruncatedPacket)
+		return
+	}
+
 	// Create packet
 	p = &Packet{}
 
@@ -213,7 +250,7 @@ func parsePacketAdaptationField(i *astikit.BytesIterator) (a *PacketAdaptationFi
 				err = fmt.Errorf("astits: fetching next byte failed: %w", err)
 				return
 			}
-			a.SpliceCountdown = int(b)
+			a.SpliceCountdown = int(int8(b))
 		}
 
 		// Transport private data
@@ -295,6 +332,8 @@ func parsePacketAdaptationField(i *astikit.BytesIterator) (a *PacketAdaptationFi
 					i.Skip(-1)
 
 					// DTS Next access unit
+					// It shares the PES PTS/DTS layout, the splice type taking the place of the 4 bits prefix, which is
+					// what writePTSOrDTS writes as well
 					if a.AdaptationExtensionField.DTSNextAccessUnit, err = parsePTSOrDTS(i); err != nil {
 						err = fmt.Errorf("astits: parsing DTS failed: %w", err)
 						return
@@ -304,8 +343,13 @@ func parsePacketAdaptationField(i *astikit.BytesIterator) (a *PacketAdaptationFi
 		}
 	}
 
-	a.StuffingLength = a.Length - (i.Offset() - afStartOffset)
-
+	// Stuffing
+	// Malformed adaptation fields may have fields exceeding their length, in which case there's no stuffing
+	if a.StuffingLength = a.Length - (i.Offset() - afStartOffset); a.StuffingLength < 0 {
+		a.StuffingLength = 0
+	} else {
+		i.Skip(a.StuffingLength)
+	}
 	return
 }
 
@@ -322,6 +366,32 @@ func parsePCR(i *astikit.BytesIterator) (cr *ClockReference, err error) {
 	return
 }
 
+// WritePacketTo writes a fully-formed packet, adaptation field included, to w. Its payload is padded with stuffing
+// bytes so that exactly packetSize bytes are written.
+// Only 188-byte packets are supported for now: 192-byte packets (e.g. M2TS) carry a 4-byte header before the sync byte
+// which the parser doesn't handle yet, therefore other sizes are rejected with ErrInvalidPacketSize.
+func WritePacketTo(w io.Writer, p *Packet, packetSize int) (n int, err error) {
+	// Validate packet size
+	if packetSize != MpegTsPacketSize {
+		err = fmt.Errorf("astits: packet size %d is not %d: %w", packetSize, MpegTsPacketSize, ErrInvalidPacketSize)
+		return
+	}
+
+	// Write packet
+	buf := &bytes.Buffer{}
+	if _, err = writePacket(astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf}), p, MpegTsPacketSize); err != nil {
+		err = fmt.Errorf("astits: writing packet failed: %w", err)
+		return
+	}
+
+	// Write
+	if n, err = w.Write(buf.Bytes()); err != nil {
+		err = fmt.Errorf("astits: writing failed: %w", err)
+		return
+	}
+	return
+}
+
 func writePacket(w *astikit.BitsWriter, p *Packet, targetPacketSize int) (written int, retErr error) {
 	if retErr = w.Write(uint8(syncByte)); retErr != nil {
 		return
@@ -453,15 +523,16 @@ func writePacketAdaptationField(w *astikit.BitsWriter, af *PacketAdaptationField
 	}
 
 	if af.HasSplicingCountdown {
-		b.Write(uint8(af.SpliceCountdown))
+		b.Write(uint8(int8(af.SpliceCountdown)))
 		bytesWritten++
 	}
 
 	if af.HasTransportPrivateData {
-		// we can get length from TransportPrivateData itself, why do we need separate field?
-		b.Write(uint8(af.TransportPrivateDataLength))
+		// Length is taken from TransportPrivateData itself so that it's always consistent with
+		// calcPacketAdaptationFieldLength
+		b.Write(uint8(len(af.TransportPrivateData)))
 		bytesWritten++
-		if af.TransportPrivateDataLength > 0 {
+		if len(af.TransportPrivateData) > 0 {
 			b.Write(af.TransportPrivateData)
 		}
 		bytesWritten += len(af.TransportPrivateData)
@@ -11,6 +11,8 @@ import (
 
 // packetBuffer represents a packet buffer
 type packetBuffer struct {
+	index            int // Index of the next packet read, skipped packets included
+	offset           int // Number of leading bytes skipped before the first packet
 	packetSize       int
 	s                PacketSkipper
 	r                io.Reader
@@ -26,10 +28,16 @@ func newPacketBuffer(r io.Reader, packetSize int, s PacketSkipper) (pb *packetBu
 		r:          r,
 	}
 
+	// Packet size is too small
+	if pb.packetSize != 0 && pb.packetSize < MpegTsPacketSize {
+		err = fmt.Errorf("astits: packet size %d is smaller than %d: %w", pb.packetSize, MpegTsPacketSize, ErrInvalidPacketSize)
+		return
+	}
+
 	// Packet size is not set
 	if pb.packetSize == 0 {
 		// Auto detect packet size
-		if pb.packetSize, err = autoDetectPacketSize(r); err != nil {
+		if pb.packetSize, pb.offset, pb.index, err = autoDetectPacketSize(r); err != nil {
 			err = fmt.Errorf("astits: auto detecting packet size failed: %w", err)
 			return
 		}
@@ -39,67 +47,223 @@ func newPacketBuffer(r io.Reader, packetSize int, s PacketSkipper) (pb *packetBu
 
 // autoDetectPacketSize updates the packet size based on the first bytes
 // Minimum packet size is 188 and is bounded by 2 sync bytes
-// Assumption is made that the first byte of the reader is a sync byte
-func autoDetectPacketSize(r io.Reader) (packetSize int, err error) {
+// Up to one packet of leading bytes (e.g. a partial packet when joining a stream mid-packet) is skipped, in which
+// case offset is the position of the first sync byte and the reader is positioned on it
+// When the reader can neither be peeked nor seeked, the packets read during the detection are discarded, in which case
+// skipped is their number and the reader is positioned on the next packet
+func autoDetectPacketSize(r io.Reader) (packetSize, offset, skipped int, err error) {
 	// Read first bytes
-	const l = 193
+	const maxPacketSize = 192
+	const l = MpegTsPacketSize + maxPacketSize + 1
 	var b = make([]byte, l)
-	shouldRewind, rerr := peek(r, b)
+	n, shouldRewind, rerr := peek(r, b)
 	if rerr != nil {
 		err = fmt.Errorf("astits: reading first %d bytes failed: %w", l, rerr)
 		return
 	}
 
-	// Packet must start with a sync byte
-	if b[0] != syncByte {
-		err = ErrPacketMustStartWithASyncByte
-		return
+	// Look for 2 sync bytes a packet size apart
+search:
+	for o := 0; o < MpegTsPacketSize; o++ {
+		if b[o] != syncByte {
+			continue
+		}
+		for idx := o + MpegTsPacketSize; idx <= o+maxPacketSize && idx < n; idx++ {
+			if b[idx] == syncByte {
+				offset, packetSize = o, idx-o
+				break search
+			}
+		}
 	}
 
-	// Look for sync bytes
-	for idx, b := range b {
-		if b == syncByte && idx >= MpegTsPacketSize {
-			// Update packet size
-			packetSize = idx
+	// No packet size detected
+	if packetSize == 0 {
+		if b[0] != syncByte {
+			err = ErrPacketMustStartWithASyncByte
+		} else {
+			err = fmt.Errorf("astits: only one sync byte detected in first %d bytes: %w", n, ErrInvalidPacketSize)
+		}
+		return
+	}
 
-			if !shouldRewind {
+	// Peeked bytes have not been consumed
+	if !shouldRewind {
+		if offset > 0 {
+			if _, err = r.(*bufio.Reader).Discard(offset); err != nil {
+				err = fmt.Errorf("astits: discarding %d bytes failed: %w", offset, err)
 				return
 			}
+		}
+		return
+	}
 
-			// Rewind or sync reader
-			var n int64
-			if n, err = rewind(r); err != nil {
-				err = fmt.Errorf("astits: rewinding failed: %w", err)
-				return
-			} else if n == -1 {
-				var ls = packetSize - (l - packetSize)
-				if _, err = r.Read(make([]byte, ls)); err != nil {
-					err = fmt.Errorf("astits: reading %d bytes to sync reader failed: %w", ls, err)
+	// Seek to first packet
+	if s, ok := r.(io.Seeker); ok {
+		if _, err = s.Seek(int64(offset), io.SeekStart); err != nil {
+			err = fmt.Errorf("astits: seeking to %d failed: %w", offset, err)
+			return
+		}
+		return
+	}
+
+	// Sync reader on the next packet
+	ls := (n - offset) % packetSize
+	if ls > 0 {
+		ls = packetSize - ls
+		if _, err = io.ReadFull(r, make([]byte, ls)); err != nil {
+			err = fmt.Errorf("astits: reading %d bytes to sync reader failed: %w", ls, err)
+			return
+		}
+	}
+	skipped = (n - offset + ls) / packetSize
+	return
+}
+
+// segmentsReader reads segments one after the other as a single stream of whole packets
+// Segments may not be packet-aligned to each other, therefore leading bytes of each segment are skipped until its first
+// packet and partial packets at the end of a segment are dropped. Empty segments are skipped.
+// The packet size is auto detected once, from the first non-empty segment, and is assumed to be 188 bytes if that
+// segment starts with a single packet.
+type segmentsReader struct {
+	b          []byte // Bytes of the current packet that have not been read yet
+	buf        []byte
+	packetSize int
+	r          *bufio.Reader
+	rs         []io.Reader
+}
+
+// newSegmentsReader creates a new segments reader
+func newSegmentsReader(rs []io.Reader) *segmentsReader {
+	return &segmentsReader{rs: rs}
+}
+
+// Read implements the io.Reader interface
+func (r *segmentsReader) Read(p []byte) (n int, err error) {
+	// Get next packet
+	if len(r.b) == 0 {
+		if err = r.nextPacket(); err != nil {
+			return
+		}
+	}
+
+	// Copy
+	n = copy(p, r.b)
+	r.b = r.b[n:]
+	return
+}
+
+// nextPacket reads the next whole packet, switching to the next segment if needed
+func (r *segmentsReader) nextPacket() (err error) {
+	for {
+		// Switch to the next segment
+		if r.r == nil {
+			// No more segments
+			if len(r.rs) == 0 {
+				return io.EOF
+			}
+			r.r = bufio.NewReader(r.rs[0])
+			r.rs = r.rs[1:]
+
+			// Segment is empty
+			if _, err = r.r.Peek(1); err != nil {
+				if err != io.EOF {
+					err = fmt.Errorf("astits: peeking failed: %w", err)
+					return
+				}
+				err = nil
+				r.r = nil
+				continue
+			}
+
+			// Packet size is not known yet
+			if r.packetSize == 0 {
+				// Auto detect packet size, which skips leading bytes until the first packet
+				if r.packetSize, _, _, err = autoDetectPacketSize(r.r); err != nil {
+					// Segment is too short to detect the packet size
+					if !errors.Is(err, ErrInvalidPacketSize) {
+						err = fmt.Errorf("astits: auto detecting packet size failed: %w", err)
+						return
+					}
+					err = nil
+					r.packetSize = MpegTsPacketSize
+				}
+				r.buf = make([]byte, r.packetSize)
+			} else {
+				// Skip leading bytes until the first packet
+				var ok bool
+				if ok, err = r.skipLeadingBytes(); err != nil {
+					err = fmt.Errorf("astits: skipping leading bytes failed: %w", err)
 					return
+				} else if !ok {
+					r.r = nil
+					continue
 				}
 			}
+		}
+
+		// Read packet
+		if _, err = io.ReadFull(r.r, r.buf); err != nil {
+			// End of segment, partial packet is dropped
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				err = nil
+				r.r = nil
+				continue
+			}
+			err = fmt.Errorf("astits: reading %d bytes failed: %w", r.packetSize, err)
+			return
+		}
+		r.b = r.buf
+		return
+	}
+}
+
+// skipLeadingBytes skips the leading bytes of the current segment until its first packet, which is the first sync
+// byte followed by another one a packet size apart, if the segment is long enough. ok is false if none is found, in
+// which case the segment doesn't contain any packet.
+func (r *segmentsReader) skipLeadingBytes() (ok bool, err error) {
+	// Peek
+	var b []byte
+	if b, err = r.r.Peek(2 * r.packetSize); err != nil && err != io.EOF {
+		err = fmt.Errorf("astits: peeking failed: %w", err)
+		return
+	}
+	err = nil
+
+	// Look for the first packet
+	o := 0
+	for ; o < r.packetSize && o < len(b); o++ {
+		if b[o] == syncByte && (o+r.packetSize >= len(b) || b[o+r.packetSize] == syncByte) {
+			ok = true
+			break
+		}
+	}
+
+	// Discard
+	if ok && o > 0 {
+		if _, err = r.r.Discard(o); err != nil {
+			err = fmt.Errorf("astits: discarding %d bytes failed: %w", o, err)
 			return
 		}
 	}
-	err = fmt.Errorf("astits: only one sync byte detected in first %d bytes", l)
 	return
 }
 
 // bufio.Reader can't be rewinded, which leads to packet loss on packet size autodetection
 // but it has handy Peek() method
 // so what we do here is peeking bytes for bufio.Reader and falling back to rewinding/syncing for all other readers
-func peek(r io.Reader, b []byte) (shouldRewind bool, err error) {
+// n is the number of bytes actually available, which may be less than len(b) for short streams
+func peek(r io.Reader, b []byte) (n int, shouldRewind bool, err error) {
 	if br, ok := r.(*bufio.Reader); ok {
 		var bs []byte
-		bs, err = br.Peek(len(b))
-		if err != nil {
+		if bs, err = br.Peek(len(b)); err != nil && (err != io.EOF || len(bs) == 0) {
 			return
 		}
-		copy(b, bs)
-		return false, nil
+		return copy(b, bs), false, nil
 	}
 
-	_, err = r.Read(b)
+	if n, err = io.ReadFull(r, b); err == io.ErrUnexpectedEOF {
+		err = nil
+	}
 	shouldRewind = true
 	return
 }
@@ -117,6 +281,13 @@ func rewind(r io.Reader) (n int64, err error) {
 	return
 }
 
+// lastPosition returns the index and the byte offset of the last packet read
+func (pb *packetBuffer) lastPosition() (index int, offset int64) {
+	index = pb.index - 1
+	offset = int64(pb.offset) + int64(index)*int64(pb.packetSize)
+	return
+}
+
 // next fetches the next packet from the buffer
 func (pb *packetBuffer) next() (p *Packet, err error) {
 	// Read
@@ -134,6 +305,7 @@ func (pb *packetBuffer) next() (p *Packet, err error) {
 			}
 			return
 		}
+		pb.index++
 
 		// Parse packet
 		if p, err = parsePacket(astikit.NewBytesIterator(pb.packetReadBuffer), pb.s); err != nil {
@@ -2,6 +2,7 @@ package astits
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/asticode/go-astikit"
@@ -14,7 +15,7 @@ func TestAutoDetectPacketSize(t *testing.T) {
 	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
 	w.Write(uint8(2))
 	w.Write(byte(syncByte))
-	_, err := autoDetectPacketSize(bytes.NewReader(buf.Bytes()))
+	_, _, _, err := autoDetectPacketSize(bytes.NewReader(buf.Bytes()))
 	assert.EqualError(t, err, ErrPacketMustStartWithASyncByte.Error())
 
 	// Valid packet size
@@ -27,8 +28,29 @@ func TestAutoDetectPacketSize(t *testing.T) {
 	w.Write(make([]byte, 187))
 	w.Write([]byte("test"))
 	r := bytes.NewReader(buf.Bytes())
-	p, err := autoDetectPacketSize(r)
+	p, o, s, err := autoDetectPacketSize(r)
 	assert.NoError(t, err)
 	assert.Equal(t, MpegTsPacketSize, p)
+	assert.Equal(t, 0, o)
+	assert.Equal(t, 0, s)
 	assert.Equal(t, 380, r.Len())
+
+	// Leading bytes are skipped
+	b := append([]byte{0x1, 0x2, 0x3}, buf.Bytes()...)
+	r = bytes.NewReader(b)
+	p, o, s, err = autoDetectPacketSize(r)
+	assert.NoError(t, err)
+	assert.Equal(t, MpegTsPacketSize, p)
+	assert.Equal(t, 3, o)
+	assert.Equal(t, 0, s)
+	assert.Equal(t, 380, r.Len())
+
+	// Only one sync byte
+	_, _, _, err = autoDetectPacketSize(bytes.NewReader(append([]byte{syncByte}, make([]byte, 400)...)))
+	assert.True(t, errors.Is(err, ErrInvalidPacketSize))
+}
+
+func TestNewPacketBufferInvalidPacketSize(t *testing.T) {
+	_, err := newPacketBuffer(bytes.NewReader(nil), 100, nil)
+	assert.True(t, errors.Is(err, ErrInvalidPacketSize))
 }
@@ -47,8 +47,8 @@ func (b *packetAccumulator) add(p *Packet) (ps []*Packet) {
 	mps = append(mps, p)
 
 	// Check if PSI payload is complete
-	if b.programMap != nil &&
-		(b.pid == PIDPAT || b.programMap.existsUnlocked(b.pid)) &&
+	if b.programMap != nil && !b.programMap.isPESUnlocked(b.pid) &&
+		(b.pid == PIDPAT || b.pid == PIDCAT || b.pid == PIDTSDT || b.programMap.existsUnlocked(b.pid) || b.programMap.isSCTE35Unlocked(b.pid)) &&
 		isPSIComplete(mps) {
 		ps = mps
 		mps = nil
@@ -63,14 +63,16 @@ type packetPool struct {
 	// We use map[uint32] instead map[uint16] as go runtime provide optimized hash functions for (u)int32/64 keys
 	b map[uint32]*packetAccumulator // Indexed by PID
 
+	dropTEI    bool
 	programMap *programMap
 }
 
 // newPacketPool creates a new packet pool with an optional parser and programMap
-func newPacketPool(programMap *programMap) *packetPool {
+func newPacketPool(programMap *programMap, dropTEI bool) *packetPool {
 	return &packetPool{
 		b: make(map[uint32]*packetAccumulator),
 
+		dropTEI:    dropTEI,
 		programMap: programMap,
 	}
 }
@@ -78,7 +80,7 @@ func newPacketPool(programMap *programMap) *packetPool {
 // addUnlocked adds a new packet to the pool
 func (b *packetPool) addUnlocked(p *Packet) (ps []*Packet) {
 	// Throw away packet if error indicator
-	if p.Header.TransportErrorIndicator {
+	if b.dropTEI && p.Header.TransportErrorIndicator {
 		return
 	}
 
@@ -21,7 +21,7 @@ func TestIsSameAsPrevious(t *testing.T) {
 }
 
 func TestPacketPool(t *testing.T) {
-	b := newPacketPool(nil)
+	b := newPacketPool(nil, true)
 	ps := b.addUnlocked(&Packet{Header: PacketHeader{ContinuityCounter: 0, HasPayload: true, PID: 1}})
 	assert.Len(t, ps, 0)
 	ps = b.addUnlocked(&Packet{Header: PacketHeader{ContinuityCounter: 1, HasPayload: true, PayloadUnitStartIndicator: true, PID: 1}})
@@ -47,3 +47,13 @@ func TestPacketPool(t *testing.T) {
 	ps = b.dumpUnlocked()
 	assert.Len(t, ps, 0)
 }
+
+func TestPacketPoolTEI(t *testing.T) {
+	p := &Packet{Header: PacketHeader{ContinuityCounter: 0, HasPayload: true, PID: 1, TransportErrorIndicator: true}}
+	b := newPacketPool(nil, true)
+	b.addUnlocked(p)
+	assert.Len(t, b.dumpUnlocked(), 0)
+	b = newPacketPool(nil, false)
+	b.addUnlocked(p)
+	assert.Len(t, b.dumpUnlocked(), 1)
+}
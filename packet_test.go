@@ -2,6 +2,7 @@ package astits
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -57,6 +58,42 @@ func TestParsePacket(t *testing.T) {
 	// Skip
 	_, err = parsePacket(astikit.NewBytesIterator(b), func(p *Packet) bool { return true })
 	assert.EqualError(t, err, errSkippedPacket.Error())
+
+	// Truncated
+	_, err = parsePacket(astikit.NewBytesIterator(b[:100]), nil)
+	assert.True(t, errors.Is(err, ErrTruncatedPacket))
+}
+
+func TestFindSyncByte(t *testing.T) {
+	b1, _ := packet(packetHeader, *packetAdaptationField, []byte("payload"), false)
+	b2, _ := packet(packetHeader, *packetAdaptationField, []byte("payload"), false)
+
+	// Leading junk bytes, including a sync byte that doesn't start a packet
+	b := append([]byte{0x1, syncByte, 0x2}, b1...)
+	b = append(b, b2...)
+	assert.Equal(t, 3, FindSyncByte(b))
+	assert.Equal(t, 0, FindSyncByte(b1))
+	assert.Equal(t, -1, FindSyncByte([]byte{0x1, 0x2}))
+}
+
+func TestParsePacketAt(t *testing.T) {
+	b1, ep := packet(packetHeader, *packetAdaptationField, []byte("payload"), false)
+	b := append([]byte{0x1, 0x2, 0x3}, b1...)
+	b = append(b, b1...)
+
+	p, err := ParsePacketAt(b, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, ep, p)
+	p, err = ParsePacketAt(b, 3+MpegTsPacketSize)
+	assert.NoError(t, err)
+	assert.Equal(t, ep, p)
+
+	_, err = ParsePacketAt(b, 0)
+	assert.True(t, errors.Is(err, ErrPacketMustStartWithASyncByte))
+	_, err = ParsePacketAt(b, 4+MpegTsPacketSize)
+	assert.True(t, errors.Is(err, ErrTruncatedPacket))
+	_, err = ParsePacketAt(b, -1)
+	assert.True(t, errors.Is(err, ErrTruncatedPacket))
 }
 
 func TestPayloadOffset(t *testing.T) {
@@ -76,6 +113,26 @@ func TestWritePacket(t *testing.T) {
 	assert.Equal(t, eb, buf.Bytes())
 }
 
+func TestWritePacketTo(t *testing.T) {
+	_, ep := packet(packetHeader, *packetAdaptationField, []byte("payload"), false)
+	buf := &bytes.Buffer{}
+	n, err := WritePacketTo(buf, ep, MpegTsPacketSize)
+	assert.NoError(t, err)
+	assert.Equal(t, MpegTsPacketSize, n)
+	assert.Equal(t, n, buf.Len())
+	p, err := parsePacket(astikit.NewBytesIterator(buf.Bytes()), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ep, p)
+
+	// Invalid packet size
+	for _, s := range []int{100, 192} {
+		buf.Reset()
+		_, err = WritePacketTo(buf, ep, s)
+		assert.True(t, errors.Is(err, ErrInvalidPacketSize))
+		assert.Equal(t, 0, buf.Len())
+	}
+}
+
 func TestWritePacket_HeaderOnly(t *testing.T) {
 	shortPacketHeader := packetHeader
 	shortPacketHeader.HasPayload = false
@@ -215,6 +272,82 @@ func TestWritePacketAdaptationField(t *testing.T) {
 	assert.Equal(t, eb, buf.Bytes())
 }
 
+func TestPacketAdaptationFieldDTSNextAccessUnitRoundTrip(t *testing.T) {
+	for _, base := range []int64{0, 1, dtsClockReference.Base, 1<<30 - 1, 1 << 30, 1<<33 - 1} {
+		afe := &PacketAdaptationExtensionField{
+			DTSNextAccessUnit: &ClockReference{Base: base},
+			HasSeamlessSplice: true,
+			SpliceType:        0xf,
+		}
+		afe.Length = int(calcPacketAdaptationFieldExtensionLength(afe))
+		af := &PacketAdaptationField{
+			AdaptationExtensionField:    afe,
+			HasAdaptationExtensionField: true,
+		}
+		af.Length = int(calcPacketAdaptationFieldLength(af))
+
+		buf := &bytes.Buffer{}
+		w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+		_, err := writePacketAdaptationField(w, af)
+		assert.NoError(t, err)
+
+		v, err := parsePacketAdaptationField(astikit.NewBytesIterator(buf.Bytes()))
+		assert.NoError(t, err)
+		assert.Equal(t, af, v, "base %d", base)
+	}
+}
+
+func TestPacketAdaptationFieldNegativeSpliceCountdown(t *testing.T) {
+	b := []byte{
+		0x2,  // Length
+		0x04, // Splicing point flag
+		0xfe, // Splice countdown
+	}
+	v, err := parsePacketAdaptationField(astikit.NewBytesIterator(b))
+	assert.NoError(t, err)
+	assert.Equal(t, -2, v.SpliceCountdown)
+
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	_, err = writePacketAdaptationField(w, v)
+	assert.NoError(t, err)
+	assert.Equal(t, b, buf.Bytes())
+}
+
+func TestParsePacketAdaptationFieldStuffing(t *testing.T) {
+	for _, c := range []struct {
+		af             []byte
+		name           string
+		stuffingLength int
+	}{
+		{
+			af:             append([]byte{0xa, 0x0}, bytes.Repeat([]byte{0xff}, 9)...),
+			name:           "explicit stuffing",
+			stuffingLength: 9,
+		},
+		{
+			af:             []byte{0x1, 0x10}, // PCR flag is set but the PCR doesn't fit
+			name:           "fields exceeding length",
+			stuffingLength: 0,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+			w.Write(uint8(syncByte))                                                                           // Sync byte
+			w.Write(packetHeaderBytes(PacketHeader{HasAdaptationField: true, HasPayload: true, PID: 1}, "11")) // Header
+			w.Write(c.af)                                                                                      // Adaptation field
+			payload := append([]byte("payload"), bytes.Repeat([]byte{0}, MpegTsPacketSize-buf.Len()-7)...)
+			w.Write(payload) // Payload
+
+			p, err := parsePacket(astikit.NewBytesIterator(buf.Bytes()), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, c.stuffingLength, p.AdaptationField.StuffingLength)
+			assert.Equal(t, payload, p.Payload)
+		})
+	}
+}
+
 var pcr = &ClockReference{
 	Base:      5726623061,
 	Extension: 341,
@@ -3,13 +3,19 @@ package astits
 // programMap represents a program ids map
 type programMap struct {
 	// We use map[uint32] instead map[uint16] as go runtime provide optimized hash functions for (u)int32/64 keys
-	p map[uint32]uint16 // map[ProgramMapID]ProgramNumber
+	c map[uint32]bool       // map[CAPID]true, for both ECM and EMM PIDs
+	e map[uint32]bool       // map[ElementaryPID]true, for PIDs forced to carry PES data
+	p map[uint32]uint16     // map[ProgramMapID]ProgramNumber
+	s map[uint32]StreamType // map[ElementaryPID]StreamType
 }
 
 // newProgramMap creates a new program ids map
 func newProgramMap() *programMap {
 	return &programMap{
+		c: make(map[uint32]bool),
+		e: make(map[uint32]bool),
 		p: make(map[uint32]uint16),
+		s: make(map[uint32]StreamType),
 	}
 }
 
@@ -28,6 +34,43 @@ func (m programMap) unsetUnlocked(pid uint16) {
 	delete(m.p, uint32(pid))
 }
 
+// setCAPIDUnlocked marks the pid as carrying ECMs or EMMs
+func (m programMap) setCAPIDUnlocked(pid uint16) {
+	m.c[uint32(pid)] = true
+}
+
+// isCAPIDUnlocked checks whether the pid carries ECMs or EMMs
+func (m programMap) isCAPIDUnlocked(pid uint16) bool {
+	return m.c[uint32(pid)]
+}
+
+// setPESUnlocked forces the pid to be considered as carrying PES data
+func (m programMap) setPESUnlocked(pid uint16) {
+	m.e[uint32(pid)] = true
+}
+
+// isPESUnlocked checks whether the pid is forced to be considered as carrying PES data
+func (m programMap) isPESUnlocked(pid uint16) bool {
+	return m.e[uint32(pid)]
+}
+
+// setStreamTypeUnlocked sets the stream type of an elementary pid
+func (m programMap) setStreamTypeUnlocked(pid uint16, t StreamType) {
+	m.s[uint32(pid)] = t
+}
+
+// streamTypeUnlocked returns the stream type of an elementary pid
+func (m programMap) streamTypeUnlocked(pid uint16) (t StreamType, ok bool) {
+	t, ok = m.s[uint32(pid)]
+	return
+}
+
+// isSCTE35Unlocked checks whether the elementary pid carries SCTE35 sections
+func (m programMap) isSCTE35Unlocked(pid uint16) bool {
+	t, ok := m.s[uint32(pid)]
+	return ok && t == StreamTypeSCTE35
+}
+
 func (m programMap) toPATDataUnlocked() *PATData {
 	d := &PATData{
 		Programs:          make([]*PATProgram, 0, len(m.p)),
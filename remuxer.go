@@ -0,0 +1,355 @@
+package astits
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/asticode/go-astikit"
+)
+
+// Remuxer copies selected programs and PIDs from a source stream to a destination stream
+// Packets of selected PIDs are copied as is, which preserves their PCRs and continuity counters, whereas the PAT and
+// the PMTs are rewritten so that they only reference what has been selected. When a PID is selected on its own, its
+// program is kept with only the selected elementary streams. The PCR PID of a kept program is always copied.
+type Remuxer struct {
+	demuxerOpts []func(*Demuxer)
+	dst         io.Writer
+	src         io.Reader
+
+	// We use map[uint32] instead map[uint16] as go runtime provide optimized hash functions for (u)int32/64 keys
+	pids     map[uint32]bool // Selected PIDs
+	programs map[uint32]bool // Selected program numbers
+
+	buf        bytes.Buffer
+	copied     map[uint32]bool // PIDs of kept programs
+	muxer      *Muxer
+	pat        *PATData
+	patData    []byte
+	patVersion wrappingCounter
+	pm         *programMap
+	pmts       map[uint32]*remuxerPMT // Indexed by program number
+	pool       *packetPool
+}
+
+// remuxerPMT represents a PMT of the source stream and the state of its rewritten version
+type remuxerPMT struct {
+	d       *PMTData
+	data    []byte
+	pid     uint16
+	version wrappingCounter
+}
+
+// RemuxerOptDemuxerOptions sets the options of the demuxer reading the source stream
+func RemuxerOptDemuxerOptions(opts ...func(*Demuxer)) func(*Remuxer) {
+	return func(r *Remuxer) {
+		r.demuxerOpts = opts
+	}
+}
+
+// NewRemuxer creates a new remuxer
+// The remuxer owns neither src nor dst
+func NewRemuxer(src io.Reader, dst io.Writer, opts ...func(*Remuxer)) *Remuxer {
+	r := &Remuxer{
+		dst:      dst,
+		pids:     make(map[uint32]bool),
+		programs: make(map[uint32]bool),
+		src:      src,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// SelectProgram selects a program and all its elementary streams
+func (r *Remuxer) SelectProgram(n uint16) {
+	r.programs[uint32(n)] = true
+}
+
+// SelectPID selects a PID
+func (r *Remuxer) SelectPID(pid uint16) {
+	r.pids[uint32(pid)] = true
+}
+
+// Run copies the selected programs and PIDs until the source stream is exhausted or the context is cancelled
+func (r *Remuxer) Run(ctx context.Context) (err error) {
+	// Reset state
+	r.copied = make(map[uint32]bool)
+	r.muxer = NewMuxer(ctx, r.dst)
+	r.pat = nil
+	r.patData = nil
+	r.patVersion = newWrappingCounter(0b11111) // table version is 5-bit field
+	r.pm = newProgramMap()
+	r.pmts = make(map[uint32]*remuxerPMT)
+	r.pool = newPacketPool(r.pm, false)
+
+	// Loop through packets
+	dmx := NewDemuxer(ctx, r.src, r.demuxerOpts...)
+	defer dmx.Close()
+	for {
+		// Get next packet
+		var p *Packet
+		if p, err = dmx.NextPacket(); err != nil {
+			if errors.Is(err, ErrNoMorePackets) {
+				err = nil
+			} else {
+				err = fmt.Errorf("astits: fetching next packet failed: %w", err)
+			}
+			return
+		}
+
+		// Tables are rewritten
+		if p.Header.PID == PIDPAT || r.pm.existsUnlocked(p.Header.PID) {
+			if err = r.handleTablePacket(p); err != nil {
+				err = fmt.Errorf("astits: handling table packet failed: %w", err)
+				return
+			}
+			continue
+		}
+
+		// Packets of selected PIDs are copied as is
+		if !r.pids[uint32(p.Header.PID)] && !r.copied[uint32(p.Header.PID)] {
+			continue
+		}
+		if _, err = r.muxer.WritePacket(p); err != nil {
+			err = fmt.Errorf("astits: writing packet failed: %w", err)
+			return
+		}
+	}
+}
+
+// handleTablePacket accumulates a PAT or PMT packet and writes rewritten tables once they are complete
+func (r *Remuxer) handleTablePacket(p *Packet) (err error) {
+	// Table is not complete yet
+	ps := r.pool.addUnlocked(p)
+	if len(ps) == 0 {
+		return
+	}
+
+	// Parse data
+	var ds []*DemuxerData
+	if ds, err = parseData(ps, nil, r.pm, nil, dataParseOptions{}, nil); err != nil {
+		err = fmt.Errorf("astits: parsing data failed: %w", err)
+		return
+	}
+
+	// Loop through data
+	for _, d := range ds {
+		switch {
+		case d.PAT != nil:
+			// Update program map
+			r.pat = d.PAT
+			for _, pgm := range d.PAT.Programs {
+				// Program number 0 is reserved to NIT
+				if pgm.ProgramNumber > 0 {
+					r.pm.setUnlocked(pgm.ProgramMapID, pgm.ProgramNumber)
+				}
+			}
+
+			// Write PAT
+			if _, err = r.writePAT(true); err != nil {
+				err = fmt.Errorf("astits: writing PAT failed: %w", err)
+				return
+			}
+		case d.PMT != nil:
+			// Store PMT
+			pmt, ok := r.pmts[uint32(d.PMT.ProgramNumber)]
+			if !ok {
+				pmt = &remuxerPMT{version: newWrappingCounter(0b11111)}
+				r.pmts[uint32(d.PMT.ProgramNumber)] = pmt
+			}
+			pmt.d = d.PMT
+			pmt.pid = d.PID
+
+			// Update copied PIDs
+			r.updateCopied()
+
+			// PAT is only written if the programs it references have changed, and before the PMT so that the PMT PID
+			// is known when the PMT is read
+			var patWritten bool
+			if r.pat != nil {
+				if patWritten, err = r.writePAT(false); err != nil {
+					err = fmt.Errorf("astits: writing PAT failed: %w", err)
+					return
+				}
+			}
+
+			// When the PAT has been written, all PMTs are written again since previous ones may have been written
+			// before their PID was referenced
+			pmts := []*remuxerPMT{pmt}
+			if patWritten {
+				pmts = r.sortedPMTs()
+			}
+
+			// Write PMTs
+			for _, p := range pmts {
+				if err = r.writePMT(p); err != nil {
+					err = fmt.Errorf("astits: writing PMT failed: %w", err)
+					return
+				}
+			}
+		}
+	}
+	return
+}
+
+// sortedPMTs returns PMTs sorted by program number
+func (r *Remuxer) sortedPMTs() (pmts []*remuxerPMT) {
+	for _, pmt := range r.pmts {
+		pmts = append(pmts, pmt)
+	}
+	sort.Slice(pmts, func(i, j int) bool { return pmts[i].d.ProgramNumber < pmts[j].d.ProgramNumber })
+	return
+}
+
+// updateCopied updates the PIDs of kept programs
+func (r *Remuxer) updateCopied() {
+	r.copied = make(map[uint32]bool)
+	for _, pmt := range r.pmts {
+		// Program is not kept
+		d := r.keptPMT(pmt.d)
+		if d == nil {
+			continue
+		}
+
+		// PCR PID is copied even if its elementary stream is not kept
+		r.copied[uint32(d.PCRPID)] = true
+		for _, es := range d.ElementaryStreams {
+			r.copied[uint32(es.ElementaryPID)] = true
+		}
+	}
+}
+
+// keptPMT returns the rewritten version of the provided PMT, or nil if its program is not kept
+func (r *Remuxer) keptPMT(d *PMTData) *PMTData {
+	// Program is selected
+	if r.programs[uint32(d.ProgramNumber)] {
+		return d
+	}
+
+	// Only keep selected elementary streams
+	var ess []*PMTElementaryStream
+	for _, es := range d.ElementaryStreams {
+		if r.pids[uint32(es.ElementaryPID)] {
+			ess = append(ess, es)
+		}
+	}
+	if len(ess) == 0 {
+		return nil
+	}
+	return &PMTData{
+		ElementaryStreams:  ess,
+		PCRPID:             d.PCRPID,
+		ProgramDescriptors: d.ProgramDescriptors,
+		ProgramNumber:      d.ProgramNumber,
+	}
+}
+
+// writePAT writes a PAT that only references kept programs. Unless forced, it's only written if it has changed.
+// Programs that are not selected and whose PMT has not been received yet are not kept, since their PMT is needed to
+// know whether their elementary streams are selected: the PAT is written again once it is received, if it changes the
+// kept programs. Nothing is written until at least one program is kept.
+func (r *Remuxer) writePAT(force bool) (written bool, err error) {
+	// Get kept programs
+	d := &PATData{TransportStreamID: r.pat.TransportStreamID}
+	for _, pgm := range r.pat.Programs {
+		// Program number 0 is reserved to NIT
+		if pgm.ProgramNumber == 0 {
+			continue
+		}
+
+		// Program is selected
+		if r.programs[uint32(pgm.ProgramNumber)] {
+			d.Programs = append(d.Programs, pgm)
+			continue
+		}
+
+		// Program is kept
+		if pmt, ok := r.pmts[uint32(pgm.ProgramNumber)]; ok && r.keptPMT(pmt.d) != nil {
+			d.Programs = append(d.Programs, pgm)
+		}
+	}
+
+	// No program is kept yet
+	if r.patData == nil && len(d.Programs) == 0 {
+		return
+	}
+
+	// Write section
+	previous := r.patData
+	if r.patData, err = r.writeSection(PIDPAT, &PSISection{
+		Header: &PSISectionHeader{TableID: PSITableIDPAT},
+		Syntax: &PSISectionSyntax{
+			Data:   &PSISectionSyntaxData{PAT: d},
+			Header: &PSISectionSyntaxHeader{TableIDExtension: d.TransportStreamID},
+		},
+	}, force, previous, &r.patVersion); err != nil {
+		return
+	}
+	written = force || !bytes.Equal(previous, r.patData)
+	return
+}
+
+// writePMT writes the rewritten version of the provided PMT if its program is kept
+func (r *Remuxer) writePMT(pmt *remuxerPMT) (err error) {
+	// PAT has not been written yet or program is not kept
+	d := r.keptPMT(pmt.d)
+	if r.patData == nil || d == nil {
+		return
+	}
+
+	// Write section
+	pmt.data, err = r.writeSection(pmt.pid, &PSISection{
+		Header: &PSISectionHeader{TableID: PSITableIDPMT},
+		Syntax: &PSISectionSyntax{
+			Data:   &PSISectionSyntaxData{PMT: d},
+			Header: &PSISectionSyntaxHeader{TableIDExtension: d.ProgramNumber},
+		},
+	}, true, pmt.data, &pmt.version)
+	return
+}
+
+// writeSection writes a single section table on the provided PID. Its version is incremented whenever its data
+// differs from the previous one, which is returned for the next call. Unless forced, an unchanged table is not
+// written.
+func (r *Remuxer) writeSection(pid uint16, s *PSISection, force bool, previous []byte, version *wrappingCounter) (data []byte, err error) {
+	// Get data
+	r.buf.Reset()
+	if _, err = writePSISectionSyntaxData(astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &r.buf}), s.Syntax.Data, s.Header.TableID); err != nil {
+		err = fmt.Errorf("astits: writing PSI section syntax data failed: %w", err)
+		return
+	}
+	data = make([]byte, r.buf.Len())
+	copy(data, r.buf.Bytes())
+
+	// Update version
+	if previous == nil || !bytes.Equal(previous, data) {
+		version.inc()
+	} else if !force {
+		return
+	}
+
+	// Update section
+	s.Header.SectionSyntaxIndicator = true
+	s.Syntax.Header.CurrentNextIndicator = true
+	s.Syntax.Header.VersionNumber = uint8(version.get())
+	s.Header.SectionLength = calcPSISectionLength(s)
+
+	// Write PSI data
+	r.buf.Reset()
+	if _, err = writePSIData(astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: &r.buf}), &PSIData{Sections: []*PSISection{s}}); err != nil {
+		err = fmt.Errorf("astits: writing PSI data failed: %w", err)
+		return
+	}
+
+	// Write payload
+	if _, err = r.muxer.writePSIPayload(pid, r.buf.Bytes()); err != nil {
+		err = fmt.Errorf("astits: writing PSI payload failed: %w", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,221 @@
+package astits
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	remuxerPAT = &PATData{
+		Programs: []*PATProgram{
+			{ProgramMapID: 0x1000, ProgramNumber: 1},
+			{ProgramMapID: 0x1001, ProgramNumber: 2},
+		},
+		TransportStreamID: 1,
+	}
+	remuxerPMTs = map[uint16]*PMTData{
+		0x1000: {
+			ElementaryStreams: []*PMTElementaryStream{
+				{ElementaryPID: 0x100, StreamType: StreamTypeH264Video},
+				{ElementaryPID: 0x101, StreamType: StreamTypeAACAudio},
+			},
+			PCRPID:        0x100,
+			ProgramNumber: 1,
+		},
+		0x1001: {
+			ElementaryStreams: []*PMTElementaryStream{
+				{ElementaryPID: 0x200, StreamType: StreamTypeH264Video},
+				{ElementaryPID: 0x201, StreamType: StreamTypeAACAudio},
+			},
+			PCRPID:        0x200,
+			ProgramNumber: 2,
+		},
+	}
+)
+
+func remuxerSourceBytes(t *testing.T, pat *PATData) []byte {
+	buf := &bytes.Buffer{}
+	w := astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: buf})
+	writeSection := func(pid uint16, tableID PSITableID, tableIDExtension uint16, d *PSISectionSyntaxData) {
+		s := &PSISection{
+			Header: &PSISectionHeader{SectionSyntaxIndicator: true, TableID: tableID},
+			Syntax: &PSISectionSyntax{
+				Data:   d,
+				Header: &PSISectionSyntaxHeader{CurrentNextIndicator: true, TableIDExtension: tableIDExtension},
+			},
+		}
+		s.Header.SectionLength = calcPSISectionLength(s)
+		b := &bytes.Buffer{}
+		_, err := writePSIData(astikit.NewBitsWriter(astikit.BitsWriterOptions{Writer: b}), &PSIData{Sections: []*PSISection{s}})
+		assert.NoError(t, err)
+		_, err = writePacket(w, &Packet{
+			Header:  PacketHeader{HasPayload: true, PayloadUnitStartIndicator: true, PID: pid},
+			Payload: b.Bytes(),
+		}, MpegTsPacketSize)
+		assert.NoError(t, err)
+	}
+	ccs := map[uint16]uint8{}
+	writeES := func(pid uint16, pcr *ClockReference) {
+		p := &Packet{
+			Header: PacketHeader{
+				ContinuityCounter: ccs[pid],
+				HasPayload:        true,
+				PID:               pid,
+			},
+			Payload: []byte{byte(pid), ccs[pid]},
+		}
+		if pcr != nil {
+			p.AdaptationField = &PacketAdaptationField{HasPCR: true, PCR: pcr}
+			p.Header.HasAdaptationField = true
+		}
+		_, err := writePacket(w, p, MpegTsPacketSize)
+		assert.NoError(t, err)
+		ccs[pid] = (ccs[pid] + 1) & 0xf
+	}
+	for idx := 0; idx < 2; idx++ {
+		writeSection(PIDPAT, PSITableIDPAT, pat.TransportStreamID, &PSISectionSyntaxData{PAT: pat})
+		for _, pid := range []uint16{0x1000, 0x1001} {
+			writeSection(pid, PSITableIDPMT, remuxerPMTs[pid].ProgramNumber, &PSISectionSyntaxData{PMT: remuxerPMTs[pid]})
+		}
+		for esIdx := 0; esIdx < 3; esIdx++ {
+			writeES(0x100, &ClockReference{Base: int64(idx*3+esIdx) * 3000})
+			writeES(0x101, nil)
+			writeES(0x200, &ClockReference{Base: int64(idx*3+esIdx)*3000 + 1000})
+			writeES(0x201, nil)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestRemuxer(t *testing.T) {
+	src := remuxerSourceBytes(t, remuxerPAT)
+
+	// Get source packets
+	srcPackets := map[uint16][]*Packet{}
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(src), DemuxerOptPacketSize(188))
+	for {
+		p, err := dmx.NextPacket()
+		if err == ErrNoMorePackets {
+			break
+		}
+		assert.NoError(t, err)
+		srcPackets[p.Header.PID] = append(srcPackets[p.Header.PID], p)
+	}
+
+	for _, c := range []struct {
+		name    string
+		pat     *PATData
+		pmt     *PMTData
+		pids    []uint16
+		program uint16
+		selects func(r *Remuxer)
+	}{
+		{
+			name:    "program",
+			pat:     &PATData{Programs: []*PATProgram{remuxerPAT.Programs[1]}, TransportStreamID: 1},
+			pmt:     remuxerPMTs[0x1001],
+			pids:    []uint16{PIDPAT, 0x200, 0x201, 0x1001},
+			program: 0x1001,
+			selects: func(r *Remuxer) { r.SelectProgram(2) },
+		},
+		{
+			name: "pid",
+			pat:  &PATData{Programs: []*PATProgram{remuxerPAT.Programs[0]}, TransportStreamID: 1},
+			pmt: &PMTData{
+				ElementaryStreams: []*PMTElementaryStream{remuxerPMTs[0x1000].ElementaryStreams[1]},
+				PCRPID:            0x100,
+				ProgramNumber:     1,
+			},
+			pids:    []uint16{PIDPAT, 0x100, 0x101, 0x1000},
+			program: 0x1000,
+			selects: func(r *Remuxer) { r.SelectPID(0x101) },
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			// Remux
+			dst := &bytes.Buffer{}
+			r := NewRemuxer(bytes.NewReader(src), dst, RemuxerOptDemuxerOptions(DemuxerOptPacketSize(188)))
+			c.selects(r)
+			assert.NoError(t, r.Run(context.Background()))
+
+			// Tables are rewritten
+			var pats []*PATData
+			var pmts []*PMTData
+			dmx := NewDemuxer(context.Background(), bytes.NewReader(dst.Bytes()), DemuxerOptPacketSize(188))
+			for {
+				d, err := dmx.NextData()
+				if err == ErrNoMorePackets {
+					break
+				}
+				assert.NoError(t, err)
+				if d.PAT != nil {
+					pats = append(pats, d.PAT)
+				}
+				if d.PMT != nil {
+					pmts = append(pmts, d.PMT)
+				}
+			}
+			assert.Equal(t, []*PATData{c.pat, c.pat}, pats)
+			assert.Equal(t, []*PMTData{c.pmt, c.pmt}, pmts)
+			assert.Equal(t, c.pids, dmx.PIDs())
+
+			// Elementary stream packets are copied as is
+			dmx = NewDemuxer(context.Background(), bytes.NewReader(dst.Bytes()), DemuxerOptPacketSize(188))
+			dstPackets := map[uint16][]*Packet{}
+			for {
+				p, err := dmx.NextPacket()
+				if err == ErrNoMorePackets {
+					break
+				}
+				assert.NoError(t, err)
+				if p.Header.PID != PIDPAT && p.Header.PID != c.program {
+					dstPackets[p.Header.PID] = append(dstPackets[p.Header.PID], p)
+				}
+			}
+			for pid, ps := range dstPackets {
+				assert.Equal(t, srcPackets[pid], ps)
+			}
+		})
+	}
+}
+
+func TestRemuxerMissingPMT(t *testing.T) {
+	// PAT references a program whose PMT is never carried
+	pat := &PATData{
+		Programs:          append([]*PATProgram{{ProgramMapID: 0x1002, ProgramNumber: 3}}, remuxerPAT.Programs...),
+		TransportStreamID: 1,
+	}
+	src := remuxerSourceBytes(t, pat)
+
+	// Remux
+	dst := &bytes.Buffer{}
+	r := NewRemuxer(bytes.NewReader(src), dst, RemuxerOptDemuxerOptions(DemuxerOptPacketSize(188)))
+	r.SelectPID(0x101)
+	assert.NoError(t, r.Run(context.Background()))
+
+	// Program whose PMT is missing is not kept
+	var pats []*PATData
+	var pmts []*PMTData
+	dmx := NewDemuxer(context.Background(), bytes.NewReader(dst.Bytes()), DemuxerOptPacketSize(188))
+	for {
+		d, err := dmx.NextData()
+		if err == ErrNoMorePackets {
+			break
+		}
+		assert.NoError(t, err)
+		if d.PAT != nil {
+			pats = append(pats, d.PAT)
+		}
+		if d.PMT != nil {
+			pmts = append(pmts, d.PMT)
+		}
+	}
+	expectedPAT := &PATData{Programs: []*PATProgram{remuxerPAT.Programs[0]}, TransportStreamID: 1}
+	assert.Equal(t, []*PATData{expectedPAT, expectedPAT}, pats)
+	assert.Len(t, pmts, 2)
+	assert.Equal(t, []uint16{PIDPAT, 0x100, 0x101, 0x1000}, dmx.PIDs())
+}
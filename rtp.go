@@ -0,0 +1,94 @@
+package astits
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Errors
+var (
+	ErrInvalidRTPPacket = errors.New("astits: invalid rtp packet")
+)
+
+const (
+	rtpHeaderSize    = 12
+	rtpMaxPacketSize = 65536
+	rtpVersion       = 2
+)
+
+// rtpReader represents a reader stripping RTP headers from datagrams
+type rtpReader struct {
+	b   []byte
+	buf []byte
+	r   io.Reader
+}
+
+// NewRTPReader creates a new reader that strips the RTP header of each datagram read from the underlying reader
+// Each Read on the underlying reader must return exactly one RTP packet, which is the case for UDP connections
+// https://datatracker.ietf.org/doc/html/rfc3550#section-5.1
+func NewRTPReader(r io.Reader) io.Reader {
+	return &rtpReader{
+		buf: make([]byte, rtpMaxPacketSize),
+		r:   r,
+	}
+}
+
+// Read implements the io.Reader interface
+func (r *rtpReader) Read(p []byte) (n int, err error) {
+	// Read next datagram if no payload is left
+	for len(r.b) == 0 {
+		// Read
+		var l int
+		if l, err = r.r.Read(r.buf); err != nil && (err != io.EOF || l == 0) {
+			return
+		}
+		err = nil
+
+		// Strip header
+		if r.b, err = rtpPayload(r.buf[:l]); err != nil {
+			err = fmt.Errorf("astits: parsing rtp packet failed: %w", err)
+			return
+		}
+	}
+
+	// Copy
+	n = copy(p, r.b)
+	r.b = r.b[n:]
+	return
+}
+
+// rtpPayload returns the payload of an RTP packet
+func rtpPayload(b []byte) (payload []byte, err error) {
+	// Check header
+	if len(b) < rtpHeaderSize || b[0]>>6 != rtpVersion {
+		err = ErrInvalidRTPPacket
+		return
+	}
+
+	// Fixed header and CSRCs
+	offset := rtpHeaderSize + int(b[0]&0xf)*4
+
+	// Extension
+	if b[0]&0x10 > 0 {
+		if len(b) < offset+4 {
+			err = ErrInvalidRTPPacket
+			return
+		}
+		offset += 4 + (int(b[offset+2])<<8|int(b[offset+3]))*4
+	}
+
+	// Padding
+	end := len(b)
+	if b[0]&0x20 > 0 {
+		end -= int(b[end-1])
+	}
+
+	// Check offsets
+	if offset > end {
+		err = ErrInvalidRTPPacket
+		return
+	}
+	payload = b[offset:end]
+	return
+}
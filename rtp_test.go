@@ -0,0 +1,55 @@
+package astits
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// datagramReader returns one datagram per Read
+type datagramReader struct {
+	ds [][]byte
+}
+
+func (r *datagramReader) Read(p []byte) (n int, err error) {
+	if len(r.ds) == 0 {
+		return 0, io.EOF
+	}
+	n = copy(p, r.ds[0])
+	r.ds = r.ds[1:]
+	return
+}
+
+func TestRTPReader(t *testing.T) {
+	ts1 := bytes.Repeat([]byte{syncByte, 1}, MpegTsPacketSize/2)
+	ts2 := bytes.Repeat([]byte{syncByte, 2}, MpegTsPacketSize/2)
+
+	// Without extension
+	r := NewRTPReader(&datagramReader{ds: [][]byte{
+		append([]byte{0x80, 0x21, 0x0, 0x1, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0, 0x1}, ts1...),
+		append([]byte{0x80, 0x21, 0x0, 0x2, 0x0, 0x0, 0x0, 0x2, 0x0, 0x0, 0x0, 0x1}, ts2...),
+	}})
+	b, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, append(append([]byte{}, ts1...), ts2...), b)
+
+	// With CSRCs, extension and padding
+	d := []byte{0xb1, 0x21, 0x0, 0x1, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0, 0x1} // Header with P, X and CC = 1
+	d = append(d, 0x0, 0x0, 0x0, 0x2)                                         // CSRC
+	d = append(d, 0xbe, 0xde, 0x0, 0x1, 0x1, 0x2, 0x3, 0x4)                   // Extension with 1 word
+	d = append(d, ts1...)                                                     // Payload
+	d = append(d, 0x0, 0x2)                                                   // Padding
+	r = NewRTPReader(&datagramReader{ds: [][]byte{d}})
+	b, err = ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, ts1, b)
+
+	// Invalid version
+	r = NewRTPReader(&datagramReader{ds: [][]byte{append([]byte{0x40, 0x21, 0x0, 0x1, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0, 0x1}, ts1...)}})
+	_, err = ioutil.ReadAll(r)
+	assert.True(t, errors.Is(err, ErrInvalidRTPPacket))
+}